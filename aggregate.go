@@ -0,0 +1,104 @@
+package simple
+
+import (
+	"fmt"
+	"math"
+)
+
+// The aggregation functions below take the values at p inside of each element
+// of an Array, so with a p of MustParsePath("price") they aggregate the prices
+// of an Array of Structs, and with an empty p they aggregate the elements
+// themselves. Elements without a value at p and nulls are skipped, any other
+// value that is not a number is an error.
+
+type aggregateError struct {
+	path Path
+	v    Value
+}
+
+func (a aggregateError) Error() string {
+	return fmt.Sprintf("cannot aggregate value at %s: %s is not a number", a.path, kindName(a.v))
+}
+
+func (a aggregateError) Pointer() string { return a.path.Pointer() }
+
+// numbersAt calls fn with every number at p inside the elements of a.
+func numbersAt(a Array, p Path, fn func(v Value, f float64)) error {
+	for i, ev := range a {
+		v, ok := Lookup(ev, p)
+		if !ok || v == nil {
+			continue
+		}
+		f, ok := numberValue(v)
+		if !ok {
+			return aggregateError{path: append(Path{i}, p...), v: v}
+		}
+		fn(v, f)
+	}
+	return nil
+}
+
+// Sum adds up the numbers at p. The sum of no numbers is 0.
+func Sum(a Array, p Path) (Number, error) {
+	var sum float64
+	err := numbersAt(a, p, func(_ Value, f float64) { sum += f })
+	return Number(sum), err
+}
+
+// Avg returns the mean of the numbers at p as a [Number], or nil if there
+// are none.
+func Avg(a Array, p Path) (Value, error) {
+	var sum float64
+	n := 0
+	err := numbersAt(a, p, func(_ Value, f float64) {
+		sum += f
+		n++
+	})
+	if err != nil || n == 0 {
+		return nil, err
+	}
+	return Number(sum / float64(n)), nil
+}
+
+// Min returns the smallest number at p, as it is stored, or nil if there are
+// none. If more than one number is the smallest, the first one wins. NaN is
+// skipped, as it cannot be ordered.
+func Min(a Array, p Path) (Value, error) {
+	return extreme(a, p, func(f, best float64) bool { return f < best })
+}
+
+// Max returns the largest number at p, as it is stored, or nil if there are
+// none. If more than one number is the largest, the first one wins. NaN is
+// skipped, as it cannot be ordered.
+func Max(a Array, p Path) (Value, error) {
+	return extreme(a, p, func(f, best float64) bool { return f > best })
+}
+
+func extreme(a Array, p Path, better func(f, best float64) bool) (Value, error) {
+	var out Value
+	var best float64
+	err := numbersAt(a, p, func(v Value, f float64) {
+		if math.IsNaN(f) {
+			return
+		}
+		if out == nil || better(f, best) {
+			out, best = v, f
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Count returns the number of elements of a that have a value other than
+// null at p, of any kind.
+func Count(a Array, p Path) int {
+	n := 0
+	for _, ev := range a {
+		if v, ok := Lookup(ev, p); ok && v != nil {
+			n++
+		}
+	}
+	return n
+}
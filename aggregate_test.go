@@ -0,0 +1,69 @@
+package simple
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregates(t *testing.T) {
+	orders := Array{
+		Struct{"price": Number(10.5)},
+		Struct{"price": Int(4)},
+		Struct{"price": nil},
+		Struct{},
+		Struct{"price": RawNumber("20")},
+	}
+	price := MustParsePath("price")
+
+	sum, err := Sum(orders, price)
+	require.NoError(t, err)
+	require.Equal(t, Number(34.5), sum)
+
+	avg, err := Avg(orders, price)
+	require.NoError(t, err)
+	require.Equal(t, Number(11.5), avg)
+
+	lo, err := Min(orders, price)
+	require.NoError(t, err)
+	require.Equal(t, Int(4), lo)
+
+	hi, err := Max(orders, price)
+	require.NoError(t, err)
+	require.Equal(t, RawNumber("20"), hi)
+
+	require.Equal(t, 3, Count(orders, price))
+	require.Equal(t, 5, Count(orders, nil))
+
+	_, err = Sum(append(orders, Struct{"price": String("1")}), price)
+	require.EqualError(t, err, "cannot aggregate value at [5].price: string is not a number")
+}
+
+func TestAggregatesOfNothing(t *testing.T) {
+	sum, err := Sum(Array{nil}, nil)
+	require.NoError(t, err)
+	require.Equal(t, Number(0), sum)
+	for _, fn := range []func(Array, Path) (Value, error){Avg, Min, Max} {
+		v, err := fn(Array{}, nil)
+		require.NoError(t, err)
+		require.Nil(t, v)
+	}
+	hi, err := Max(Array{Number(1), Int(3), Number(3)}, nil)
+	require.NoError(t, err)
+	require.Equal(t, Int(3), hi)
+}
+
+func TestMinMaxSkipNaN(t *testing.T) {
+	nums := Array{Number(math.NaN()), Number(2), Number(-1), Number(math.NaN())}
+	lo, err := Min(nums, nil)
+	require.NoError(t, err)
+	require.Equal(t, Number(-1), lo)
+	hi, err := Max(nums, nil)
+	require.NoError(t, err)
+	require.Equal(t, Number(2), hi)
+
+	lo, err = Min(Array{Number(math.NaN())}, nil)
+	require.NoError(t, err)
+	require.Nil(t, lo)
+}
@@ -0,0 +1,872 @@
+package simple
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// AvroSchema is a parsed Avro schema, used to convert between Values and the
+// Avro binary encoding, for example to produce and consume Kafka messages
+// with Avro contracts.
+//
+// Values map to Avro types as follows:
+//   - null, boolean, int, long, float, double and string map to nil, [Bool],
+//     [Int] (a [Number] or [RawNumber] without a fractional part is also
+//     accepted), [Number] and [String].
+//   - bytes and fixed are base64 encoded [String]s.
+//   - records and maps are [Struct]s, arrays are [Array]s and enums are
+//     [String]s holding one of the symbols.
+//   - a union holds the value of its first branch that the Value fits.
+//
+// The logical types date, time-millis, time-micros, timestamp-millis and
+// timestamp-micros are decoded to [String]s in the formats "2006-01-02",
+// "15:04:05.000" and RFC 3339, and either those strings or the underlying
+// numbers are accepted when encoding. The decimal logical type is decoded to
+// an exact [RawNumber]. Other logical types are ignored, as the Avro
+// specification requires.
+type AvroSchema struct {
+	root *avroType
+}
+
+type avroType struct {
+	kind    string
+	logical string
+	name    string
+
+	precision, scale int
+	size             int
+	fields           []avroField
+	symbols          []string
+	items            *avroType
+	branches         []*avroType
+
+	// width caches minWidth, once widthKnown is set
+	width      int64
+	widthKnown bool
+}
+
+// minWidth returns the least number of bytes that a value of type t takes in
+// the binary encoding. It is 0 for nulls, for fixed of size 0 and for records
+// made only of those. The widths of all types nested in t are computed along
+// the way.
+func (t *avroType) minWidth() int64 {
+	if t.widthKnown {
+		return t.width
+	}
+	// a record that contains itself is only reached through a union, an
+	// array or a map, which take at least a byte anyway
+	t.widthKnown = true
+	var w int64
+	switch t.kind {
+	case "null":
+	case "float":
+		w = 4
+	case "double":
+		w = 8
+	case "fixed":
+		w = int64(t.size)
+	case "record":
+		for _, f := range t.fields {
+			w += f.typ.minWidth()
+		}
+	case "array", "map":
+		t.items.minWidth()
+		w = 1
+	case "union":
+		for _, b := range t.branches {
+			b.minWidth()
+		}
+		w = 1
+	default:
+		// a single byte, a varint, a length or a block count
+		w = 1
+	}
+	t.width = w
+	return w
+}
+
+type avroField struct {
+	name       string
+	typ        *avroType
+	def        Value
+	hasDefault bool
+}
+
+var avroPrimitives = map[string]bool{
+	"null": true, "boolean": true, "int": true, "long": true,
+	"float": true, "double": true, "bytes": true, "string": true,
+}
+
+// avroLogicalKinds are the supported logical types and the type they annotate.
+var avroLogicalKinds = map[string]string{
+	"date":             "int",
+	"time-millis":      "int",
+	"time-micros":      "long",
+	"timestamp-millis": "long",
+	"timestamp-micros": "long",
+	"decimal":          "bytes",
+}
+
+// ParseAvroSchema parses an Avro schema in its JSON form.
+func ParseAvroSchema(schema string) (*AvroSchema, error) {
+	v, err := FromJSON([]byte(schema))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Avro schema: %w", err)
+	}
+	p := avroParser{named: map[string]*avroType{}}
+	root, err := p.parse(v, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid Avro schema: %w", err)
+	}
+	// computed up front, so that Decode does not write to the schema
+	root.minWidth()
+	return &AvroSchema{root: root}, nil
+}
+
+type avroParser struct {
+	named map[string]*avroType
+}
+
+func (p *avroParser) lookup(name, ns string) (*avroType, error) {
+	if avroPrimitives[name] {
+		return &avroType{kind: name}, nil
+	}
+	if ns != "" && !strings.Contains(name, ".") {
+		if t, ok := p.named[ns+"."+name]; ok {
+			return t, nil
+		}
+	}
+	if t, ok := p.named[name]; ok {
+		return t, nil
+	}
+	return nil, fmt.Errorf("unknown type %q", name)
+}
+
+// define registers a named type, returning the namespace for the types
+// nested inside of it.
+func (p *avroParser) define(t *avroType, s Struct, ns string) (string, error) {
+	name, _ := s["name"].(String)
+	if name == "" {
+		return "", fmt.Errorf("%s is missing a name", t.kind)
+	}
+	full := string(name)
+	if i := strings.LastIndexByte(full, '.'); i >= 0 {
+		ns = full[:i]
+	} else {
+		if n, ok := s["namespace"].(String); ok {
+			ns = string(n)
+		}
+		if ns != "" {
+			full = ns + "." + full
+		}
+	}
+	if _, ok := p.named[full]; ok || avroPrimitives[full] {
+		return "", fmt.Errorf("type %q is defined twice", full)
+	}
+	t.name = full
+	p.named[full] = t
+	return ns, nil
+}
+
+func (p *avroParser) parse(s Value, ns string) (*avroType, error) {
+	switch ts := s.(type) {
+	case String:
+		return p.lookup(string(ts), ns)
+	case Array:
+		t := &avroType{kind: "union"}
+		for _, b := range ts {
+			bt, err := p.parse(b, ns)
+			if err != nil {
+				return nil, err
+			}
+			if bt.kind == "union" {
+				return nil, errors.New("unions may not immediately contain other unions")
+			}
+			t.branches = append(t.branches, bt)
+		}
+		return t, nil
+	case Struct:
+		kind, ok := ts["type"].(String)
+		if !ok {
+			return p.parse(ts["type"], ns)
+		}
+		t := &avroType{kind: string(kind)}
+		if l, ok := ts["logicalType"].(String); ok {
+			t.logical = string(l)
+		}
+		switch t.kind {
+		case "record", "error":
+			t.kind = "record"
+			childNS, err := p.define(t, ts, ns)
+			if err != nil {
+				return nil, err
+			}
+			fields, ok := ts["fields"].(Array)
+			if !ok {
+				return nil, fmt.Errorf("record %q is missing its fields", t.name)
+			}
+			for _, f := range fields {
+				fs, _ := f.(Struct)
+				name, _ := fs["name"].(String)
+				if name == "" {
+					return nil, fmt.Errorf("record %q has a field without a name", t.name)
+				}
+				ft, err := p.parse(fs["type"], childNS)
+				if err != nil {
+					return nil, err
+				}
+				def, hasDefault := fs["default"]
+				t.fields = append(t.fields, avroField{name: string(name), typ: ft, def: def, hasDefault: hasDefault})
+			}
+		case "enum":
+			if _, err := p.define(t, ts, ns); err != nil {
+				return nil, err
+			}
+			symbols, _ := ts["symbols"].(Array)
+			for _, sym := range symbols {
+				ss, ok := sym.(String)
+				if !ok {
+					return nil, fmt.Errorf("enum %q has a symbol that is not a string", t.name)
+				}
+				t.symbols = append(t.symbols, string(ss))
+			}
+		case "array", "map":
+			key := "items"
+			if t.kind == "map" {
+				key = "values"
+			}
+			it, err := p.parse(ts[key], ns)
+			if err != nil {
+				return nil, err
+			}
+			t.items = it
+		case "fixed":
+			if _, err := p.define(t, ts, ns); err != nil {
+				return nil, err
+			}
+			size, err := AsInt64(ts["size"])
+			if err != nil || size < 0 {
+				return nil, fmt.Errorf("fixed %q has an invalid size", t.name)
+			}
+			t.size = int(size)
+		default:
+			pt, err := p.lookup(t.kind, ns)
+			if err != nil {
+				return nil, err
+			}
+			if pt.name != "" || t.logical == "" {
+				return pt, nil
+			}
+		}
+		if avroLogicalKinds[t.logical] != t.kind && !(t.logical == "decimal" && t.kind == "fixed") {
+			t.logical = ""
+		}
+		if t.logical == "decimal" {
+			precision, err1 := AsInt64(ts["precision"])
+			scale, err2 := AsInt64(ts["scale"])
+			if ts["scale"] == nil {
+				scale, err2 = 0, nil
+			}
+			// invalid decimals fall back to their underlying type
+			if err1 != nil || err2 != nil || precision <= 0 || scale < 0 || scale > precision {
+				t.logical = ""
+			}
+			t.precision, t.scale = int(precision), int(scale)
+		}
+		return t, nil
+	}
+	return nil, fmt.Errorf("%s is not a valid schema", kindName(s))
+}
+
+type avroError struct {
+	path    Path
+	problem string
+}
+
+func (a avroError) Error() string {
+	return fmt.Sprintf("cannot encode value at %s as Avro: %s", a.path, a.problem)
+}
+
+func (a avroError) Pointer() string { return a.path.Pointer() }
+
+// Encode returns the Avro binary encoding of v.
+func (s *AvroSchema) Encode(v Value) ([]byte, error) {
+	return avroEncode(nil, Path{}, s.root, v)
+}
+
+func avroEncode(b []byte, path Path, t *avroType, v Value) ([]byte, error) {
+	mismatch := func() error {
+		return avroError{path: path, problem: fmt.Sprintf("%s does not match Avro type %s", kindName(v), t.describe())}
+	}
+	switch t.kind {
+	case "null":
+		if v != nil {
+			return nil, mismatch()
+		}
+		return b, nil
+	case "boolean":
+		bv, ok := v.(Bool)
+		if !ok {
+			return nil, mismatch()
+		}
+		if bv {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+	case "int", "long":
+		n, err := avroInteger(t, v)
+		if err != nil {
+			return nil, avroError{path: path, problem: err.Error()}
+		}
+		if t.kind == "int" && (n < math.MinInt32 || n > math.MaxInt32) {
+			return nil, avroError{path: path, problem: fmt.Sprintf("%d is out of range for Avro type int", n)}
+		}
+		return avroAppendLong(b, n), nil
+	case "float", "double":
+		f, ok := numberValue(v)
+		if !ok {
+			return nil, mismatch()
+		}
+		if t.kind == "float" {
+			return binary.LittleEndian.AppendUint32(b, math.Float32bits(float32(f))), nil
+		}
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(f)), nil
+	case "bytes", "fixed":
+		data, err := avroBytes(t, v)
+		if err != nil {
+			return nil, avroError{path: path, problem: err.Error()}
+		}
+		if t.kind == "fixed" {
+			if len(data) != t.size {
+				return nil, avroError{path: path, problem: fmt.Sprintf("fixed %q needs %d bytes, got %d", t.name, t.size, len(data))}
+			}
+			return append(b, data...), nil
+		}
+		return append(avroAppendLong(b, int64(len(data))), data...), nil
+	case "string":
+		sv, ok := v.(String)
+		if !ok {
+			return nil, mismatch()
+		}
+		return append(avroAppendLong(b, int64(len(sv))), sv...), nil
+	case "enum":
+		sv, ok := v.(String)
+		if !ok {
+			return nil, mismatch()
+		}
+		for i, sym := range t.symbols {
+			if sym == string(sv) {
+				return avroAppendLong(b, int64(i)), nil
+			}
+		}
+		return nil, avroError{path: path, problem: fmt.Sprintf("%q is not a symbol of enum %q", string(sv), t.name)}
+	case "array":
+		av, ok := v.(Array)
+		if !ok {
+			return nil, mismatch()
+		}
+		if len(av) > 0 {
+			b = avroAppendLong(b, int64(len(av)))
+		}
+		for i, ev := range av {
+			var err error
+			if b, err = avroEncode(b, path.Index(i), t.items, ev); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, 0), nil
+	case "map":
+		sv, ok := v.(Struct)
+		if !ok {
+			return nil, mismatch()
+		}
+		if len(sv) > 0 {
+			b = avroAppendLong(b, int64(len(sv)))
+		}
+		for _, k := range sortedKeys(sv) {
+			b = append(avroAppendLong(b, int64(len(k))), k...)
+			var err error
+			if b, err = avroEncode(b, path.Key(k), t.items, sv[k]); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, 0), nil
+	case "record":
+		sv, ok := v.(Struct)
+		if !ok {
+			return nil, mismatch()
+		}
+		known := make(map[string]bool, len(t.fields))
+		for _, f := range t.fields {
+			known[f.name] = true
+			fv, ok := sv[f.name]
+			if !ok && f.hasDefault {
+				fv = f.def
+				if f.typ.kind == "union" && len(f.typ.branches) > 0 {
+					// the default of a union is always for its first branch
+					var err error
+					b = avroAppendLong(b, 0)
+					if b, err = avroEncode(b, path.Key(f.name), f.typ.branches[0], fv); err != nil {
+						return nil, err
+					}
+					continue
+				}
+			} else if !ok && !f.typ.accepts(nil) {
+				return nil, avroError{path: path, problem: fmt.Sprintf("missing field %q of record %q", f.name, t.name)}
+			}
+			var err error
+			if b, err = avroEncode(b, path.Key(f.name), f.typ, fv); err != nil {
+				return nil, err
+			}
+		}
+		for _, k := range sortedKeys(sv) {
+			if !known[k] {
+				return nil, avroError{path: path.Key(k), problem: fmt.Sprintf("record %q has no such field", t.name)}
+			}
+		}
+		return b, nil
+	case "union":
+		for i, bt := range t.branches {
+			if bt.accepts(v) {
+				return avroEncode(avroAppendLong(b, int64(i)), path, bt, v)
+			}
+		}
+		return nil, mismatch()
+	}
+	return nil, avroError{path: path, problem: fmt.Sprintf("unsupported Avro type %s", t.kind)}
+}
+
+func (t *avroType) describe() string {
+	switch {
+	case t.name != "":
+		return fmt.Sprintf("%s %q", t.kind, t.name)
+	case t.logical != "":
+		return fmt.Sprintf("%s (%s)", t.kind, t.logical)
+	case t.kind == "union":
+		names := make([]string, len(t.branches))
+		for i, bt := range t.branches {
+			names[i] = bt.describe()
+		}
+		return "[" + strings.Join(names, ", ") + "]"
+	}
+	return t.kind
+}
+
+// accepts reports whether v could be encoded as t, which is used to pick the
+// branch of a union.
+func (t *avroType) accepts(v Value) bool {
+	switch t.kind {
+	case "null":
+		return v == nil
+	case "boolean":
+		_, ok := v.(Bool)
+		return ok
+	case "int", "long":
+		n, err := avroInteger(t, v)
+		return err == nil && (t.kind == "long" || (n >= math.MinInt32 && n <= math.MaxInt32))
+	case "float", "double":
+		_, ok := numberValue(v)
+		return ok
+	case "bytes", "fixed":
+		data, err := avroBytes(t, v)
+		return err == nil && (t.kind == "bytes" || len(data) == t.size)
+	case "string":
+		_, ok := v.(String)
+		return ok
+	case "enum":
+		sv, ok := v.(String)
+		if ok {
+			for _, sym := range t.symbols {
+				if sym == string(sv) {
+					return true
+				}
+			}
+		}
+		return false
+	case "array":
+		_, ok := v.(Array)
+		return ok
+	case "map":
+		_, ok := v.(Struct)
+		return ok
+	case "record":
+		sv, ok := v.(Struct)
+		if !ok {
+			return false
+		}
+		known := make(map[string]bool, len(t.fields))
+		for _, f := range t.fields {
+			known[f.name] = true
+			if _, ok := sv[f.name]; !ok && !f.hasDefault && !f.typ.accepts(nil) {
+				return false
+			}
+		}
+		for k := range sv {
+			if !known[k] {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// avroInteger converts v to the number stored by an int or a long, which for
+// the time logical types may also be given as a formatted string.
+func avroInteger(t *avroType, v Value) (int64, error) {
+	if sv, ok := v.(String); ok {
+		var layout string
+		switch t.logical {
+		case "date":
+			layout = time.DateOnly
+		case "time-millis", "time-micros":
+			layout = "15:04:05.999999"
+		case "timestamp-millis", "timestamp-micros":
+			layout = time.RFC3339Nano
+		default:
+			return 0, fmt.Errorf("%s does not match Avro type %s", kindName(v), t.kind)
+		}
+		tm, err := time.Parse(layout, string(sv))
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a valid %s", string(sv), t.logical)
+		}
+		sinceMidnight := time.Duration(tm.Hour())*time.Hour + time.Duration(tm.Minute())*time.Minute +
+			time.Duration(tm.Second())*time.Second + time.Duration(tm.Nanosecond())
+		switch t.logical {
+		case "date":
+			return tm.Unix() / 86400, nil
+		case "time-millis":
+			return sinceMidnight.Milliseconds(), nil
+		case "time-micros":
+			return sinceMidnight.Microseconds(), nil
+		case "timestamp-millis":
+			return tm.UnixMilli(), nil
+		}
+		return tm.UnixMicro(), nil
+	}
+	switch v.(type) {
+	case Number, RawNumber, Int:
+		n, err := AsInt64(v)
+		if err != nil {
+			return 0, fmt.Errorf("%s cannot be stored as Avro type %s: %s", shortString(v), t.kind, err.(coercionError).problem)
+		}
+		return n, nil
+	}
+	return 0, fmt.Errorf("%s does not match Avro type %s", kindName(v), t.kind)
+}
+
+// avroBytes converts v to the contents of a bytes or fixed, which hold
+// either base64 encoded data or a decimal.
+func avroBytes(t *avroType, v Value) ([]byte, error) {
+	if t.logical != "decimal" {
+		sv, ok := v.(String)
+		if !ok {
+			return nil, fmt.Errorf("%s does not match Avro type %s", kindName(v), t.kind)
+		}
+		data, err := base64.StdEncoding.DecodeString(string(sv))
+		if err != nil {
+			return nil, fmt.Errorf("%s is not valid base64", shortString(v))
+		}
+		return data, nil
+	}
+	var text string
+	switch tv := v.(type) {
+	case Number:
+		if !isFinite(float64(tv)) {
+			return nil, fmt.Errorf("%s is not a valid decimal", nonFiniteName(float64(tv)))
+		}
+		text = formatNumber(float64(tv))
+	case String:
+		text = string(tv)
+	case RawNumber, Int:
+		text = tv.String()
+	default:
+		return nil, fmt.Errorf("%s does not match Avro type %s", kindName(v), t.describe())
+	}
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(text))
+	if !ok {
+		return nil, fmt.Errorf("%q is not a valid decimal", text)
+	}
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(t.scale)), nil)))
+	if !r.IsInt() {
+		return nil, fmt.Errorf("%s has more than %d decimal places", text, t.scale)
+	}
+	unscaled := r.Num()
+	if len(new(big.Int).Abs(unscaled).String()) > t.precision {
+		return nil, fmt.Errorf("%s has more than %d digits", text, t.precision)
+	}
+	// minimal big-endian two's complement
+	mag := unscaled
+	if unscaled.Sign() < 0 {
+		mag = new(big.Int).Sub(new(big.Int).Neg(unscaled), big.NewInt(1))
+	}
+	n := mag.BitLen()/8 + 1
+	if t.kind == "fixed" {
+		if n > t.size {
+			return nil, fmt.Errorf("%s does not fit in %d bytes", text, t.size)
+		}
+		n = t.size
+	}
+	u := new(big.Int).Set(unscaled)
+	if u.Sign() < 0 {
+		u.Add(u, new(big.Int).Lsh(big.NewInt(1), uint(8*n)))
+	}
+	return u.FillBytes(make([]byte, n)), nil
+}
+
+func avroAppendLong(b []byte, n int64) []byte {
+	return binary.AppendUvarint(b, uint64(n<<1)^uint64(n>>63))
+}
+
+// Decode converts data in the Avro binary encoding to a Value. All of data
+// must be consumed.
+func (s *AvroSchema) Decode(data []byte) (Value, error) {
+	d := avroDecoder{data: data}
+	v, err := d.decode(s.root)
+	if err != nil {
+		return nil, err
+	}
+	if d.off != len(d.data) {
+		return nil, d.errorf("%d bytes of unexpected trailing data", len(d.data)-d.off)
+	}
+	return v, nil
+}
+
+// maxAvroEmptyItems is how many items that take no bytes, like nulls, the
+// arrays of a single [AvroSchema.Decode] may hold in total. The number of
+// other items is bounded by the length of the input, but a few bytes of
+// block counts could otherwise make the decoder allocate without limit.
+const maxAvroEmptyItems = 1 << 16
+
+type avroDecoder struct {
+	data []byte
+	off  int
+
+	// emptyItems counts the items decoded that take no bytes
+	emptyItems int64
+}
+
+func (d *avroDecoder) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid Avro data at offset %d: %s", d.off, fmt.Sprintf(format, args...))
+}
+
+func (d *avroDecoder) long() (int64, error) {
+	u, n := binary.Uvarint(d.data[d.off:])
+	if n <= 0 {
+		return 0, d.errorf("invalid or truncated varint")
+	}
+	d.off += n
+	return int64(u>>1) ^ -int64(u&1), nil
+}
+
+func (d *avroDecoder) next(n int64) ([]byte, error) {
+	if n < 0 || n > int64(len(d.data)-d.off) {
+		return nil, d.errorf("length %d exceeds the remaining input", n)
+	}
+	out := d.data[d.off : d.off+int(n)]
+	d.off += int(n)
+	return out, nil
+}
+
+// blockCount reads the item count of the next block of an array or a map,
+// which is 0 at the end. Every item takes at least width bytes.
+func (d *avroDecoder) blockCount(width int64) (int64, error) {
+	count, err := d.long()
+	if err != nil {
+		return 0, err
+	}
+	if count < 0 {
+		// a negative count is followed by the size of the block in bytes
+		count = -count
+		if _, err := d.long(); err != nil {
+			return 0, err
+		}
+	}
+	if count < 0 {
+		return 0, d.errorf("block of %d items exceeds the remaining input", count)
+	}
+	if width == 0 {
+		d.emptyItems += count
+		if d.emptyItems > maxAvroEmptyItems {
+			return 0, d.errorf("more than %d items that take no space", maxAvroEmptyItems)
+		}
+		return count, nil
+	}
+	if count > int64(len(d.data)-d.off)/width {
+		return 0, d.errorf("block of %d items exceeds the remaining input", count)
+	}
+	return count, nil
+}
+
+func (d *avroDecoder) decode(t *avroType) (Value, error) {
+	switch t.kind {
+	case "null":
+		return nil, nil
+	case "boolean":
+		b, err := d.next(1)
+		if err != nil {
+			return nil, err
+		}
+		switch b[0] {
+		case 0:
+			return Bool(false), nil
+		case 1:
+			return Bool(true), nil
+		}
+		return nil, d.errorf("invalid boolean %d", b[0])
+	case "int", "long":
+		n, err := d.long()
+		if err != nil {
+			return nil, err
+		}
+		if t.kind == "int" && (n < math.MinInt32 || n > math.MaxInt32) {
+			return nil, d.errorf("%d is out of range for an int", n)
+		}
+		switch t.logical {
+		case "date":
+			return String(time.Unix(n*86400, 0).UTC().Format(time.DateOnly)), nil
+		case "time-millis":
+			return String(time.UnixMilli(n).UTC().Format("15:04:05.000")), nil
+		case "time-micros":
+			return String(time.UnixMicro(n).UTC().Format("15:04:05.000000")), nil
+		case "timestamp-millis":
+			return String(time.UnixMilli(n).UTC().Format(time.RFC3339Nano)), nil
+		case "timestamp-micros":
+			return String(time.UnixMicro(n).UTC().Format(time.RFC3339Nano)), nil
+		}
+		return Int(n), nil
+	case "float":
+		b, err := d.next(4)
+		if err != nil {
+			return nil, err
+		}
+		return Number(math.Float32frombits(binary.LittleEndian.Uint32(b))), nil
+	case "double":
+		b, err := d.next(8)
+		if err != nil {
+			return nil, err
+		}
+		return Number(math.Float64frombits(binary.LittleEndian.Uint64(b))), nil
+	case "bytes", "fixed", "string":
+		n := int64(t.size)
+		if t.kind != "fixed" {
+			var err error
+			if n, err = d.long(); err != nil {
+				return nil, err
+			}
+		}
+		b, err := d.next(n)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case t.kind == "string":
+			return String(b), nil
+		case t.logical == "decimal":
+			return avroDecimal(b, t.scale), nil
+		}
+		return String(base64.StdEncoding.EncodeToString(b)), nil
+	case "enum":
+		i, err := d.long()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= int64(len(t.symbols)) {
+			return nil, d.errorf("enum index %d is out of range", i)
+		}
+		return String(t.symbols[i]), nil
+	case "array":
+		out := Array{}
+		for {
+			count, err := d.blockCount(t.items.minWidth())
+			if err != nil {
+				return nil, err
+			}
+			if count == 0 {
+				return out, nil
+			}
+			for ; count > 0; count-- {
+				ev, err := d.decode(t.items)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, ev)
+			}
+		}
+	case "map":
+		out := Struct{}
+		for {
+			// every entry has at least the length of its key
+			count, err := d.blockCount(1 + t.items.minWidth())
+			if err != nil {
+				return nil, err
+			}
+			if count == 0 {
+				return out, nil
+			}
+			for ; count > 0; count-- {
+				n, err := d.long()
+				if err != nil {
+					return nil, err
+				}
+				k, err := d.next(n)
+				if err != nil {
+					return nil, err
+				}
+				ev, err := d.decode(t.items)
+				if err != nil {
+					return nil, err
+				}
+				out[string(k)] = ev
+			}
+		}
+	case "record":
+		out := make(Struct, len(t.fields))
+		for _, f := range t.fields {
+			fv, err := d.decode(f.typ)
+			if err != nil {
+				return nil, err
+			}
+			out[f.name] = fv
+		}
+		return out, nil
+	case "union":
+		i, err := d.long()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= int64(len(t.branches)) {
+			return nil, d.errorf("union index %d is out of range", i)
+		}
+		return d.decode(t.branches[i])
+	}
+	return nil, d.errorf("unsupported Avro type %s", t.kind)
+}
+
+// avroDecimal formats big-endian two's complement bytes as a decimal number
+// with the given scale.
+func avroDecimal(b []byte, scale int) RawNumber {
+	n := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(8*len(b))))
+	}
+	sign := ""
+	if n.Sign() < 0 {
+		sign = "-"
+		n.Neg(n)
+	}
+	digits := n.String()
+	if scale == 0 {
+		return RawNumber(sign + digits)
+	}
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	return RawNumber(sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:])
+}
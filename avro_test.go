@@ -0,0 +1,171 @@
+package simple
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testAvroSchema = `{
+	"type": "record",
+	"name": "Event",
+	"namespace": "com.example",
+	"fields": [
+		{"name": "id", "type": "long"},
+		{"name": "kind", "type": {"type": "enum", "name": "Kind", "symbols": ["CREATED", "DELETED"]}},
+		{"name": "at", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "day", "type": {"type": "int", "logicalType": "date"}},
+		{"name": "amount", "type": {"type": "bytes", "logicalType": "decimal", "precision": 9, "scale": 2}},
+		{"name": "note", "type": ["null", "string"], "default": null},
+		{"name": "tags", "type": {"type": "array", "items": "string"}},
+		{"name": "attrs", "type": {"type": "map", "values": "double"}},
+		{"name": "hash", "type": {"type": "fixed", "name": "MD5", "size": 4}},
+		{"name": "parent", "type": ["null", "Event"], "default": null}
+	]
+}`
+
+func TestAvroSpecExample(t *testing.T) {
+	s, err := ParseAvroSchema(`{"type": "record", "name": "test", "fields": [{"name": "a", "type": "long"}, {"name": "b", "type": "string"}]}`)
+	require.NoError(t, err)
+	data, err := s.Encode(Struct{"a": Number(27), "b": String("foo")})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x36, 0x06, 0x66, 0x6f, 0x6f}, data)
+	v, err := s.Decode(data)
+	require.NoError(t, err)
+	require.Equal(t, Struct{"a": Int(27), "b": String("foo")}, v)
+}
+
+func TestAvroRoundTrip(t *testing.T) {
+	s, err := ParseAvroSchema(testAvroSchema)
+	require.NoError(t, err)
+	in := Struct{
+		"id":     Int(1),
+		"kind":   String("CREATED"),
+		"at":     String("2024-03-01T12:30:00.5Z"),
+		"day":    String("1969-12-31"),
+		"amount": RawNumber("-1234.5"),
+		"tags":   Array{String("a"), String("b")},
+		"attrs":  Struct{"x": Number(0.5)},
+		"hash":   String("3q2+7w=="),
+		"parent": Struct{
+			"id":     Int(2),
+			"kind":   String("DELETED"),
+			"at":     Int(0),
+			"day":    Int(0),
+			"amount": Number(0.01),
+			"note":   String("hi"),
+			"tags":   Array{},
+			"attrs":  Struct{},
+			"hash":   String("AAAAAA=="),
+		},
+	}
+	data, err := s.Encode(in)
+	require.NoError(t, err)
+	v, err := s.Decode(data)
+	require.NoError(t, err)
+	require.Equal(t, Struct{
+		"id":     Int(1),
+		"kind":   String("CREATED"),
+		"at":     String("2024-03-01T12:30:00.5Z"),
+		"day":    String("1969-12-31"),
+		"amount": RawNumber("-1234.50"),
+		"note":   nil,
+		"tags":   Array{String("a"), String("b")},
+		"attrs":  Struct{"x": Number(0.5)},
+		"hash":   String("3q2+7w=="),
+		"parent": Struct{
+			"id":     Int(2),
+			"kind":   String("DELETED"),
+			"at":     String("1970-01-01T00:00:00Z"),
+			"day":    String("1970-01-01"),
+			"amount": RawNumber("0.01"),
+			"note":   String("hi"),
+			"tags":   Array{},
+			"attrs":  Struct{},
+			"hash":   String("AAAAAA=="),
+			"parent": nil,
+		},
+	}, v)
+}
+
+func TestAvroDecimal(t *testing.T) {
+	s, err := ParseAvroSchema(`{"type": "fixed", "name": "d", "size": 2, "logicalType": "decimal", "precision": 4, "scale": 1}`)
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		in      Value
+		encoded []byte
+		out     RawNumber
+	}{
+		{RawNumber("-0.1"), []byte{0xff, 0xff}, "-0.1"},
+		{String("12.8"), []byte{0x00, 0x80}, "12.8"},
+		{Int(-12), []byte{0xff, 0x88}, "-12.0"},
+	} {
+		data, err := s.Encode(tc.in)
+		require.NoError(t, err)
+		require.Equal(t, tc.encoded, data)
+		v, err := s.Decode(data)
+		require.NoError(t, err)
+		require.Equal(t, tc.out, v)
+	}
+	_, err = s.Encode(Number(1.25))
+	require.EqualError(t, err, "cannot encode value at  as Avro: 1.25 has more than 1 decimal places")
+	_, err = s.Encode(Number(1000))
+	require.EqualError(t, err, "cannot encode value at  as Avro: 1000 has more than 4 digits")
+}
+
+func TestAvroErrors(t *testing.T) {
+	s, err := ParseAvroSchema(testAvroSchema)
+	require.NoError(t, err)
+	_, err = s.Encode(Struct{"id": Int(1)})
+	require.EqualError(t, err, `cannot encode value at  as Avro: missing field "kind" of record "com.example.Event"`)
+	_, err = s.Encode(Struct{
+		"id": Int(1), "kind": String("UPDATED"), "at": Int(0), "day": Int(0), "amount": Int(0),
+		"tags": Array{}, "attrs": Struct{}, "hash": String("AAAAAA=="),
+	})
+	require.EqualError(t, err, `cannot encode value at .kind as Avro: "UPDATED" is not a symbol of enum "com.example.Kind"`)
+	_, err = s.Encode(Struct{
+		"id": Number(1.5), "kind": String("CREATED"), "at": Int(0), "day": Int(0), "amount": Int(0),
+		"tags": Array{Bool(true)}, "attrs": Struct{}, "hash": String("AAAAAA=="),
+	})
+	require.EqualError(t, err, `cannot encode value at .id as Avro: 1.5 cannot be stored as Avro type long: has a fractional part`)
+	_, err = s.Encode(Struct{
+		"id": Int(1), "kind": String("CREATED"), "at": Int(0), "day": Int(0), "amount": Int(0),
+		"tags": Array{}, "attrs": Struct{}, "hash": String("AAAAAA=="), "extra": nil,
+	})
+	require.EqualError(t, err, `cannot encode value at .extra as Avro: record "com.example.Event" has no such field`)
+
+	_, err = s.Decode([]byte{0x02, 0x08})
+	require.EqualError(t, err, "invalid Avro data at offset 2: enum index 4 is out of range")
+	ls, err := ParseAvroSchema(`{"type": "array", "items": "long"}`)
+	require.NoError(t, err)
+	_, err = ls.Decode([]byte{0xfe, 0xff, 0xff, 0xff, 0x0f})
+	require.EqualError(t, err, "invalid Avro data at offset 5: block of 2147483647 items exceeds the remaining input")
+	ns, err := ParseAvroSchema(`{"type": "array", "items": "null"}`)
+	require.NoError(t, err)
+	v, err := ns.Decode([]byte{0x06, 0x00})
+	require.NoError(t, err)
+	require.Equal(t, Array{nil, nil, nil}, v)
+	// many small blocks of items that take no space
+	many := bytes.Repeat([]byte{0xfe, 0xff, 0x07}, 200)
+	_, err = ns.Decode(append(many, 0x00))
+	require.EqualError(t, err, "invalid Avro data at offset 6: more than 65536 items that take no space")
+	es, err := ParseAvroSchema(`{"type": "array", "items": {"type": "record", "name": "E", "fields": [{"name": "f", "type": {"type": "fixed", "name": "F", "size": 0}}]}}`)
+	require.NoError(t, err)
+	v, err = es.Decode([]byte{0x04, 0x00})
+	require.NoError(t, err)
+	require.Equal(t, Array{Struct{"f": String("")}, Struct{"f": String("")}}, v)
+	_, err = es.Decode(append(many, 0x00))
+	require.EqualError(t, err, "invalid Avro data at offset 6: more than 65536 items that take no space")
+	_, err = ls.Decode([]byte{0x02, 0x02, 0x00, 0x00})
+	require.EqualError(t, err, "invalid Avro data at offset 3: 1 bytes of unexpected trailing data")
+
+	for schema, msg := range map[string]string{
+		`"nope"`:            `invalid Avro schema: unknown type "nope"`,
+		`["null", ["int"]]`: "invalid Avro schema: unions may not immediately contain other unions",
+		`{"type": "record", "name": "a", "fields": [{"name": "b", "type": {"type": "enum", "name": "a"}}]}`: `invalid Avro schema: type "a" is defined twice`,
+	} {
+		_, err := ParseAvroSchema(schema)
+		require.EqualError(t, err, msg)
+	}
+}
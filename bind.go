@@ -0,0 +1,143 @@
+package simple
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+type bindError struct {
+	pattern string
+	// path is the part of pattern before its first wildcard
+	path    Path
+	problem string
+	err     error
+}
+
+func (b bindError) Unwrap() error { return b.err }
+
+func (b bindError) Error() string {
+	return fmt.Sprintf("cannot bind %q: %s", b.pattern, b.problem)
+}
+
+func (b bindError) Pointer() string { return b.path.Pointer() }
+
+// hasWildcard reports whether the pattern can match more than one path.
+func (pp pathPattern) hasWildcard() bool {
+	for _, e := range pp {
+		if e.kind != patternKey && e.kind != patternIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// path returns the elements of pp up to its first wildcard as a Path, which
+// is all of pp if it has none.
+func (pp pathPattern) path() Path {
+	out := Path{}
+	for _, e := range pp {
+		switch e.kind {
+		case patternKey:
+			out = append(out, e.key)
+		case patternIndex:
+			out = append(out, e.index)
+		default:
+			return out
+		}
+	}
+	return out
+}
+
+// Extract returns the value found at pattern inside of v. Patterns use the
+// same syntax as the path globs of [Redactor]. A pattern without wildcards
+// returns the single value it refers to, and fails if there is nothing there.
+// A pattern with wildcards (like "items[*].sku") returns an [Array] of every
+// match, in the order that [Walk] finds them, which may be empty.
+func Extract(v Value, pattern string) (Value, error) {
+	pp, err := parsePathPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if !pp.hasWildcard() {
+		found, ok := Lookup(v, pp.path())
+		if !ok {
+			return nil, bindError{pattern: pattern, path: pp.path(), problem: "nothing found at path"}
+		}
+		return found, nil
+	}
+	out := Array{}
+	_ = Walk(v, func(path Path, v Value) (bool, error) {
+		if pp.Match(path) {
+			out = append(out, v)
+		}
+		return true, nil
+	})
+	return out, nil
+}
+
+// BindMap extracts every pattern in captures from v, and returns them in a
+// [Struct] keyed the same way as captures.
+func BindMap(v Value, captures map[string]string) (Struct, error) {
+	out := make(Struct, len(captures))
+	for _, name := range sortedKeysOf(captures) {
+		found, err := Extract(v, captures[name])
+		if err != nil {
+			return nil, err
+		}
+		out[name] = found
+	}
+	return out, nil
+}
+
+// Bind destructures v into the struct pointed to by target in one call, which
+// saves on long chains of lookups and type assertions. Each field to fill is
+// given a pattern understood by [Extract] with the bind option of its simple
+// tag, and the extracted value is stored with [Decode]:
+//
+//	var order struct {
+//		ID   int      `simple:"bind=id"`
+//		SKUs []string `simple:"bind=items[*].sku"`
+//		Note string   `simple:"bind=meta.note,optional"`
+//	}
+//	err := simple.Bind(v, &order)
+//
+// A pattern without wildcards must be found in v unless the "optional" option
+// is given. Fields without a bind option are left alone.
+func Bind(v Value, target any, opts ...DecodeOption) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("simple.Bind: target must be a non-nil pointer to a struct, got %T", target)
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag := field.Tag.Get("simple")
+		pattern, ok := tagOptionValue(tag, "bind")
+		if !ok || !field.IsExported() {
+			continue
+		}
+		pp, err := parsePathPattern(pattern)
+		if err != nil {
+			return err
+		}
+		found, err := Extract(v, pattern)
+		if err != nil {
+			if _, missing := err.(bindError); missing && hasTagOption(tag, "optional") {
+				continue
+			}
+			return err
+		}
+		ptr := rv.Field(i).Addr().Interface()
+		if err := Decode(found, ptr, opts...); err != nil {
+			path := pp.path()
+			var de decodeError
+			if !pp.hasWildcard() && errors.As(err, &de) {
+				path = append(path, de.path...)
+			}
+			return bindError{pattern: pattern, path: path, problem: err.Error(), err: err}
+		}
+	}
+	return nil
+}
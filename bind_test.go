@@ -0,0 +1,82 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBind(t *testing.T) {
+	order := mustFromJSON(t, `{
+		"id": 42,
+		"customer": {"name": "ann"},
+		"items": [{"sku": "a-1", "qty": 2}, {"sku": "b-2", "qty": 1}]
+	}`)
+
+	t.Run("Extract", func(t *testing.T) {
+		got, err := Extract(order, "customer.name")
+		require.NoError(t, err)
+		require.Equal(t, String("ann"), got)
+
+		got, err = Extract(order, "items[*].sku")
+		require.NoError(t, err)
+		require.Equal(t, Array{String("a-1"), String("b-2")}, got)
+
+		got, err = Extract(order, "**.qty")
+		require.NoError(t, err)
+		require.Equal(t, Array{Number(2), Number(1)}, got)
+
+		_, err = Extract(order, "customer.email")
+		require.EqualError(t, err, `cannot bind "customer.email": nothing found at path`)
+	})
+	t.Run("Bind", func(t *testing.T) {
+		var dest struct {
+			ID       int      `simple:"bind=id"`
+			Customer string   `simple:"bind=.customer.name"`
+			SKUs     []string `simple:"bind=items[*].sku"`
+			Note     string   `simple:"bind=meta.note,optional"`
+			Other    string
+		}
+		require.NoError(t, Bind(order, &dest))
+		require.Equal(t, 42, dest.ID)
+		require.Equal(t, "ann", dest.Customer)
+		require.Equal(t, []string{"a-1", "b-2"}, dest.SKUs)
+
+		var bad struct {
+			Name int `simple:"bind=customer.name"`
+		}
+		require.EqualError(t, Bind(order, &bad), `cannot bind "customer.name": cannot decode value at : cannot store string in int`)
+		err := Bind(order, &struct {
+			Qty string `simple:"bind=items[1].qty"`
+		}{})
+		var de decodeError
+		require.ErrorAs(t, err, &de)
+		pointer, ok := ErrorPointer(err)
+		require.True(t, ok)
+		require.Equal(t, "/items/1/qty", pointer)
+
+		require.ErrorIs(t, Bind(order, &struct {
+			C chan int `simple:"bind=id"`
+		}{}), ErrUnsupportedKind)
+
+		var ignored struct {
+			Name string `simple:"redact"`
+		}
+		require.NoError(t, Bind(order, &ignored))
+		var missing struct {
+			Note string `simple:"bind=meta.note"`
+		}
+		require.Error(t, Bind(order, &missing))
+	})
+	t.Run("BindMap", func(t *testing.T) {
+		got, err := BindMap(order, map[string]string{
+			"id":   "id",
+			"skus": "items[*].sku",
+		})
+		require.NoError(t, err)
+		require.Equal(t, Struct{
+			"id":   Number(42),
+			"skus": Array{String("a-1"), String("b-2")},
+		}, got)
+	})
+}
@@ -0,0 +1,185 @@
+package simple
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+type encodeError struct {
+	path    Path
+	problem string
+	err     error
+}
+
+func (e encodeError) Unwrap() error { return e.err }
+
+func (e encodeError) Error() string {
+	return fmt.Sprintf("cannot encode value at %s: %s", e.path, e.problem)
+}
+
+func (e encodeError) Pointer() string { return e.path.Pointer() }
+
+// CanonicalJSON serializes v following the JSON Canonicalization Scheme (RFC
+// 8785). Struct keys are sorted by their UTF-16 code units, numbers are
+// formatted like ECMAScript does, and strings use the minimal escaping allowed
+// by JSON. The output is byte-for-byte stable across systems, which makes it
+// suitable for signing, hashing and comparison.
+//
+// NaN and infinite numbers, as well as strings with invalid UTF-8 cannot be
+// represented and result in an error.
+func CanonicalJSON(v Value) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, Path{}, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, path Path, v Value) error {
+	switch tv := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case Bool:
+		if tv {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	case Number, RawNumber, Int:
+		f, ok := numberValue(tv)
+		if !ok {
+			return encodeError{path: path, problem: fmt.Sprintf("%q is not a valid JSON number", tv.String())}
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return encodeError{path: path, problem: fmt.Sprintf("%v is not a valid JSON number", f)}
+		}
+		if f == 0 {
+			// no negative zero
+			f = 0
+		}
+		buf.WriteString(formatNumber(f))
+	case String:
+		if !utf8.ValidString(string(tv)) {
+			return encodeError{path: path, problem: "string is not valid UTF-8"}
+		}
+		writeJSONString(buf, string(tv), false, false)
+	case Array:
+		buf.WriteByte('[')
+		for i, ev := range tv {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, path.Index(i), ev); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case Struct:
+		keys := make([]string, 0, len(tv))
+		for k := range tv {
+			if !utf8.ValidString(k) {
+				return encodeError{path: path, problem: fmt.Sprintf("key %q is not valid UTF-8", k)}
+			}
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return lessUTF16(keys[i], keys[j])
+		})
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONString(buf, k, false, false)
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, path.Key(k), tv[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return encodeError{path: path, problem: fmt.Sprintf("unsupported type %T", v), err: ErrUnsupportedKind}
+	}
+	return nil
+}
+
+// lessUTF16 compares strings by their UTF-16 code units, as required by RFC
+// 8785. This only differs from comparing the UTF-8 bytes when characters
+// outside of the basic multilingual plane are involved.
+func lessUTF16(a, b string) bool {
+	ua := utf16.Encode([]rune(a))
+	ub := utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
+
+const hexDigits = "0123456789abcdef"
+
+// writeJSONString writes s as a quoted JSON string. Only what JSON requires to
+// be escaped is escaped, unless escapeHTML (<, > and &) or asciiOnly
+// (everything outside of ASCII) ask for more. Invalid UTF-8 is replaced with
+// U+FFFD.
+func writeJSONString(buf *bytes.Buffer, s string, escapeHTML, asciiOnly bool) {
+	buf.WriteByte('"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"':
+				buf.WriteString(`\"`)
+			case c == '\\':
+				buf.WriteString(`\\`)
+			case c == '\b':
+				buf.WriteString(`\b`)
+			case c == '\f':
+				buf.WriteString(`\f`)
+			case c == '\n':
+				buf.WriteString(`\n`)
+			case c == '\r':
+				buf.WriteString(`\r`)
+			case c == '\t':
+				buf.WriteString(`\t`)
+			case c < 0x20, escapeHTML && (c == '<' || c == '>' || c == '&'):
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hexDigits[c>>4])
+				buf.WriteByte(hexDigits[c&0xF])
+			default:
+				buf.WriteByte(c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if asciiOnly || (escapeHTML && (r == '\u2028' || r == '\u2029')) {
+			writeUnicodeEscape(buf, r)
+		} else {
+			buf.WriteRune(r)
+		}
+		i += size
+	}
+	buf.WriteByte('"')
+}
+
+func writeUnicodeEscape(buf *bytes.Buffer, r rune) {
+	write := func(u uint16) {
+		buf.WriteString(`\u`)
+		buf.WriteByte(hexDigits[u>>12&0xF])
+		buf.WriteByte(hexDigits[u>>8&0xF])
+		buf.WriteByte(hexDigits[u>>4&0xF])
+		buf.WriteByte(hexDigits[u&0xF])
+	}
+	if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+		write(uint16(r1))
+		write(uint16(r2))
+		return
+	}
+	write(uint16(r))
+}
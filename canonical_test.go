@@ -0,0 +1,53 @@
+package simple
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		input  Value
+		output string
+	}{
+		{
+			name: "key ordering",
+			input: Struct{
+				"b":      Number(1),
+				"a":      Struct{"z": nil, "y": Bool(true)},
+				"\ufb00": String("ff"),
+				"😀":      String("emoji"),
+				"\r":     String("cr"),
+				"1":      Array{},
+			},
+			output: `{"\r":"cr","1":[],"a":{"y":true,"z":null},"b":1,"😀":"emoji","` + "\ufb00" + `":"ff"}`,
+		},
+		{
+			name: "numbers",
+			input: Array{
+				Number(0), Number(math.Copysign(0, -1)), Number(1e21), Number(1e-7), Number(0.000001),
+				Number(333333333.33333329), Number(4.50), Number(2e-3), Number(-1.5e300),
+			},
+			output: `[0,0,1e+21,1e-7,0.000001,333333333.3333333,4.5,0.002,-1.5e+300]`,
+		},
+		{
+			name:   "string escaping",
+			input:  String("€$\u000F\u000aA'B\"\\\\\"/<>&\u2028"),
+			output: `"€$\u000f\nA'B\"\\\\\"/<>&` + "\u2028" + `"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := CanonicalJSON(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.output, string(got))
+		})
+	}
+
+	_, err := CanonicalJSON(Struct{"a": Array{Number(math.NaN())}})
+	require.EqualError(t, err, `cannot encode value at .a[0]: NaN is not a valid JSON number`)
+	_, err = CanonicalJSON(String("\xff"))
+	require.EqualError(t, err, `cannot encode value at : string is not valid UTF-8`)
+}
@@ -0,0 +1,37 @@
+package simple
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"strings"
+)
+
+// multicodec and multihash codes used when building CIDs
+const (
+	multicodecJSON   = 0x0200
+	multihashSHA2256 = 0x12
+)
+
+// CID computes a content identifier for v that is compatible with IPLD: a
+// CIDv1 using the "json" multicodec and a SHA2-256 multihash of the
+// [CanonicalJSON] form of v, rendered as a base32 multibase string (so it
+// always starts with "b"). Equal values always get the same CID, which allows
+// them to be stored and referenced in content-addressed stores.
+func CID(v Value) (string, error) {
+	cj, err := CanonicalJSON(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(cj)
+
+	var raw []byte
+	raw = binary.AppendUvarint(raw, 1) // CID version
+	raw = binary.AppendUvarint(raw, multicodecJSON)
+	raw = binary.AppendUvarint(raw, multihashSHA2256)
+	raw = binary.AppendUvarint(raw, uint64(len(sum)))
+	raw = append(raw, sum[:]...)
+
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw)
+	return "b" + strings.ToLower(encoded), nil
+}
@@ -0,0 +1,32 @@
+package simple
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCID(t *testing.T) {
+	v := mustFromJSON(t, `{"hello": "world", "n": [1, 2]}`)
+	cid, err := CID(v)
+	require.NoError(t, err)
+	// CIDv1, json codec, sha2-256
+	require.True(t, strings.HasPrefix(cid, "bagaaiera"), cid)
+
+	raw, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(cid[1:]))
+	require.NoError(t, err)
+	sum := sha256.Sum256([]byte(`{"hello":"world","n":[1,2]}`))
+	require.Equal(t, []byte{0x01, 0x80, 0x04, 0x12, 0x20}, raw[:5])
+	require.Equal(t, sum[:], raw[5:])
+
+	same, err := CID(mustFromJSON(t, `{"n": [1.0, 2], "hello": "world"}`))
+	require.NoError(t, err)
+	require.Equal(t, cid, same)
+
+	_, err = CID(Number(math.NaN()))
+	require.Error(t, err)
+}
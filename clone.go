@@ -0,0 +1,28 @@
+package simple
+
+// Clone returns a deep copy of v: every Struct and Array in it is duplicated,
+// so the copy can be cached or modified without aliasing v. Nil Structs and
+// Arrays stay nil.
+func Clone(v Value) Value {
+	switch tv := v.(type) {
+	case Struct:
+		if tv == nil {
+			return tv
+		}
+		out := make(Struct, len(tv))
+		for k, ev := range tv {
+			out[k] = Clone(ev)
+		}
+		return out
+	case Array:
+		if tv == nil {
+			return tv
+		}
+		out := make(Array, len(tv))
+		for i, ev := range tv {
+			out[i] = Clone(ev)
+		}
+		return out
+	}
+	return v
+}
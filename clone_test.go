@@ -0,0 +1,25 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	v := Struct{
+		"list": Array{Struct{"a": Int(1)}, String("x")},
+		"nil":  Struct(nil),
+		"n":    Number(1.5),
+	}
+	c := Clone(v).(Struct)
+	require.Equal(t, v, c)
+	c["list"].(Array)[0].(Struct)["a"] = Int(2)
+	c["list"].(Array)[1] = nil
+	c["n"] = nil
+	require.Equal(t, Struct{"a": Int(1)}, v["list"].(Array)[0])
+	require.Equal(t, String("x"), v["list"].(Array)[1])
+	require.Equal(t, Number(1.5), v["n"])
+	require.Nil(t, c["nil"].(Struct))
+	require.Nil(t, Clone(nil))
+}
@@ -0,0 +1,100 @@
+// Command simplegen generates type definitions from sample JSON documents,
+// for graduating schema-less data to typed code. It is meant to be used with
+// go:generate:
+//
+//	//go:generate go run code.nkcmr.net/simple/cmd/simplegen -name User -pkg api -o user_gen.go testdata/user.json
+//
+// With -lang ts, TypeScript definitions are generated instead of Go.
+//
+// Every file given is a sample of the same type. With -elements, the elements
+// of a top-level array are samples instead. Without any files, a single
+// sample is read from standard input.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"code.nkcmr.net/simple"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "simplegen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("simplegen", flag.ContinueOnError)
+	name := fs.String("name", "", "name of the generated type (required)")
+	pkg := fs.String("pkg", os.Getenv("GOPACKAGE"), "package of the generated file, defaults to $GOPACKAGE")
+	out := fs.String("o", "", "file to write to instead of standard output")
+	lang := fs.String("lang", "go", "language to generate: go or ts")
+	elements := fs.Bool("elements", false, "treat the elements of a top-level array as samples")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *name == "" {
+		return fmt.Errorf("-name is required")
+	}
+
+	var samples []simple.Value
+	add := func(r io.Reader, source string) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		v, err := simple.FromJSON(data, simple.UseInt())
+		if err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+		if a, ok := v.(simple.Array); ok && *elements {
+			samples = append(samples, a...)
+		} else {
+			samples = append(samples, v)
+		}
+		return nil
+	}
+	if fs.NArg() == 0 {
+		if err := add(stdin, "standard input"); err != nil {
+			return err
+		}
+	}
+	for _, file := range fs.Args() {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		err = add(f, file)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	schema := simple.InferSchema(samples...)
+	var src []byte
+	switch *lang {
+	case "go":
+		var opts []simple.GoTypesOption
+		if *pkg != "" {
+			opts = append(opts, simple.GoPackage(*pkg), simple.GeneratedBy("simplegen"))
+		}
+		var err error
+		if src, err = simple.GoTypes(*name, schema, opts...); err != nil {
+			return err
+		}
+	case "ts":
+		src = simple.TypeScriptTypes(*name, schema)
+	default:
+		return fmt.Errorf("unknown language %q", *lang)
+	}
+	if *out != "" {
+		return os.WriteFile(*out, src, 0o644)
+	}
+	_, err := stdout.Write(src)
+	return err
+}
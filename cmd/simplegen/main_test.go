@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	var out strings.Builder
+	require.NoError(t, run([]string{"-name", "thing", "-pkg", "x", "-elements"}, strings.NewReader(`[{"a":1},{"a":2,"b":"x"}]`), &out))
+	require.Equal(t, "// Code generated by simplegen; DO NOT EDIT.\n\npackage x\n\ntype Thing struct {\n\tA int64   `json:\"a\"`\n\tB *string `json:\"b,omitempty\"`\n}\n", out.String())
+
+	dir := t.TempDir()
+	sample := filepath.Join(dir, "sample.json")
+	require.NoError(t, os.WriteFile(sample, []byte(`[1]`), 0o644))
+	target := filepath.Join(dir, "out.go")
+	require.NoError(t, run([]string{"-name", "list", "-pkg", "x", "-o", target, sample}, nil, nil))
+	src, err := os.ReadFile(target)
+	require.NoError(t, err)
+	require.Equal(t, "// Code generated by simplegen; DO NOT EDIT.\n\npackage x\n\ntype List []int64\n", string(src))
+
+	out.Reset()
+	require.NoError(t, run([]string{"-name", "thing", "-lang", "ts"}, strings.NewReader(`{"a":1}`), &out))
+	require.Equal(t, "export interface Thing {\n  a: number;\n}\n", out.String())
+
+	require.EqualError(t, run(nil, nil, nil), "-name is required")
+	require.EqualError(t, run([]string{"-name", "x", "-lang", "rust"}, strings.NewReader(`1`), nil), `unknown language "rust"`)
+}
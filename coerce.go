@@ -0,0 +1,264 @@
+package simple
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The As* functions leniently convert a [Value] to a Go scalar, crossing
+// [Value] kinds where the conversion is unambiguous. They are intended for
+// consuming sloppy upstream data (numbers that arrive as strings, booleans
+// that arrive as 0/1, etc.). The rules are:
+//
+//   - [Number] converts to int64 only when it has no fractional part and fits.
+//   - [String] is trimmed of surrounding whitespace and parsed with strconv.
+//   - [Bool] converts to the numbers 1 and 0, and to "true" and "false".
+//   - Only the numbers 0 and 1 convert to [Bool].
+//   - nil, [Struct] and [Array] never convert.
+
+type coercionError struct {
+	from    Value
+	to      string
+	problem string
+}
+
+func (c coercionError) Error() string {
+	msg := fmt.Sprintf("cannot coerce %s %s to %s", kindName(c.from), shortString(c.from), c.to)
+	if c.problem != "" {
+		msg += ": " + c.problem
+	}
+	return msg
+}
+
+// kindName returns a human readable name for the kind of v.
+func kindName(v Value) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case Struct:
+		return "struct"
+	case Array:
+		return "array"
+	case Number, RawNumber, Int:
+		return "number"
+	case String:
+		return "string"
+	case Bool:
+		return "bool"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// shortString renders v for use in error messages.
+func shortString(v Value) string {
+	switch tv := v.(type) {
+	case nil:
+		return "null"
+	case Struct:
+		return "{...}"
+	case Array:
+		return "[...]"
+	case Number:
+		return formatNumber(float64(tv))
+	}
+	s := v.String()
+	if len(s) > 40 {
+		s = s[:37] + "..."
+	}
+	return s
+}
+
+// formatNumber renders a float64 the same way encoding/json does, without the
+// exponent for "reasonably sized" numbers.
+func formatNumber(f float64) string {
+	abs := math.Abs(f)
+	fmtb := byte('f')
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		fmtb = 'e'
+	}
+	s := strconv.FormatFloat(f, fmtb, -1, 64)
+	if fmtb == 'e' {
+		// clean up e-09 to e-9
+		n := len(s)
+		if n >= 4 && s[n-4] == 'e' && s[n-3] == '-' && s[n-2] == '0' {
+			s = s[:n-2] + s[n-1:]
+		}
+	}
+	return s
+}
+
+// AsFloat64 coerces v to a float64.
+func AsFloat64(v Value) (float64, error) {
+	switch tv := v.(type) {
+	case Number:
+		return float64(tv), nil
+	case Int:
+		return float64(tv), nil
+	case RawNumber:
+		f, ok := numberValue(v)
+		if !ok || !isFinite(f) {
+			return 0, coercionError{from: v, to: "float64", problem: "not a finite number"}
+		}
+		return f, nil
+	case Bool:
+		if tv {
+			return 1, nil
+		}
+		return 0, nil
+	case String:
+		f, ok := parseDecimal(strings.TrimSpace(string(tv)))
+		if !ok {
+			return 0, coercionError{from: v, to: "float64", problem: "not a number"}
+		}
+		if !isFinite(f) {
+			return 0, coercionError{from: v, to: "float64", problem: "not a finite number"}
+		}
+		return f, nil
+	}
+	return 0, coercionError{from: v, to: "float64"}
+}
+
+// AsInt64 coerces v to an int64. Numbers (and numeric strings) with a
+// fractional part are not truncated, they fail to convert.
+func AsInt64(v Value) (int64, error) {
+	switch tv := v.(type) {
+	case Number:
+		return floatToInt64(v, float64(tv))
+	case Int:
+		return int64(tv), nil
+	case RawNumber:
+		if i, err := strconv.ParseInt(string(tv), 10, 64); err == nil {
+			return i, nil
+		}
+		f, ok := numberValue(v)
+		if !ok {
+			return 0, coercionError{from: v, to: "int64", problem: "not a number"}
+		}
+		return floatToInt64(v, f)
+	case Bool:
+		if tv {
+			return 1, nil
+		}
+		return 0, nil
+	case String:
+		s := strings.TrimSpace(string(tv))
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i, nil
+		}
+		f, ok := parseDecimal(s)
+		if !ok {
+			return 0, coercionError{from: v, to: "int64", problem: "not a number"}
+		}
+		return floatToInt64(v, f)
+	}
+	return 0, coercionError{from: v, to: "int64"}
+}
+
+// parseDecimal parses s as a decimal number. Unlike strconv.ParseFloat it
+// does not accept hexadecimal floats, underscores, NaN or Inf. Numbers too
+// large for a float64 are returned as infinite.
+func parseDecimal(s string) (float64, bool) {
+	if s == "" || strings.Trim(s, "0123456789+-.eE") != "" {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return 0, false
+	}
+	return f, true
+}
+
+func floatToInt64(from Value, f float64) (int64, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, coercionError{from: from, to: "int64", problem: "not a finite number"}
+	}
+	if f != math.Trunc(f) {
+		return 0, coercionError{from: from, to: "int64", problem: "has a fractional part"}
+	}
+	// -2^63 is exactly representable, 2^63 is the first value out of range
+	if f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, coercionError{from: from, to: "int64", problem: "out of range"}
+	}
+	return int64(f), nil
+}
+
+// AsBool coerces v to a bool. Strings are parsed with [strconv.ParseBool], so
+// "1", "t", "TRUE", "false", etc. are all accepted.
+func AsBool(v Value) (bool, error) {
+	switch tv := v.(type) {
+	case Bool:
+		return bool(tv), nil
+	case Number, RawNumber, Int:
+		f, _ := numberValue(v)
+		switch f {
+		case 0:
+			return false, nil
+		case 1:
+			return true, nil
+		}
+		return false, coercionError{from: v, to: "bool", problem: "only 0 and 1 are allowed"}
+	case String:
+		b, err := strconv.ParseBool(strings.TrimSpace(string(tv)))
+		if err != nil {
+			return false, coercionError{from: v, to: "bool", problem: "not a boolean"}
+		}
+		return b, nil
+	}
+	return false, coercionError{from: v, to: "bool"}
+}
+
+// AsString coerces v to a string. Numbers are formatted the same way they
+// would be in JSON, and a [RawNumber] keeps its text.
+func AsString(v Value) (string, error) {
+	switch tv := v.(type) {
+	case String:
+		return string(tv), nil
+	case Number:
+		f := float64(tv)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", coercionError{from: v, to: "string", problem: "not a finite number"}
+		}
+		return formatNumber(f), nil
+	case RawNumber:
+		if !isJSONNumber(string(tv)) {
+			return "", coercionError{from: v, to: "string", problem: "not a number"}
+		}
+		return string(tv), nil
+	case Int:
+		return tv.String(), nil
+	case Bool:
+		return strconv.FormatBool(bool(tv)), nil
+	}
+	return "", coercionError{from: v, to: "string"}
+}
+
+// inferScalar guesses the type of a string that came from an untyped source,
+// like an environment variable or a CSV cell. "true" and "false" (in any case)
+// become a [Bool], and JSON number literals become a [Number], unless they are
+// integers too large to be represented exactly. Everything else, including
+// numbers with leading zeros like zip codes, stays a [String].
+func inferScalar(s string) Value {
+	switch strings.ToLower(s) {
+	case "true":
+		return Bool(true)
+	case "false":
+		return Bool(false)
+	}
+	if !isJSONNumber(s) {
+		return String(s)
+	}
+	if !strings.ContainsAny(s, ".eE") {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || i > 1<<53 || i < -(1<<53) {
+			return String(s)
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return String(s)
+	}
+	return Number(f)
+}
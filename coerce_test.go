@@ -0,0 +1,103 @@
+package simple
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoerce(t *testing.T) {
+	t.Run("AsInt64", func(t *testing.T) {
+		for _, tc := range []struct {
+			input  Value
+			output int64
+			err    string
+		}{
+			{input: Number(42), output: 42},
+			{input: String(" 42 "), output: 42},
+			{input: String("1e3"), output: 1000},
+			{input: Bool(true), output: 1},
+			{input: Number(1.5), err: `cannot coerce number 1.5 to int64: has a fractional part`},
+			{input: String("nope"), err: `cannot coerce string "nope" to int64: not a number`},
+			{input: nil, err: `cannot coerce null null to int64`},
+			{input: Number(1e19), err: `cannot coerce number 10000000000000000000 to int64: out of range`},
+		} {
+			got, err := AsInt64(tc.input)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				continue
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.output, got)
+		}
+	})
+	t.Run("AsFloat64", func(t *testing.T) {
+		got, err := AsFloat64(String("3.25"))
+		require.NoError(t, err)
+		require.Equal(t, 3.25, got)
+		_, err = AsFloat64(Array{})
+		require.EqualError(t, err, `cannot coerce array [...] to float64`)
+		for _, s := range []string{"NaN", "Inf", "-infinity", "0x1p-2", "0x10", "1_000", ""} {
+			_, err = AsFloat64(String(s))
+			require.EqualError(t, err, fmt.Sprintf("cannot coerce string %q to float64: not a number", s))
+		}
+		_, err = AsFloat64(String("1e400"))
+		require.EqualError(t, err, `cannot coerce string "1e400" to float64: not a finite number`)
+		_, err = AsInt64(String("0x10"))
+		require.EqualError(t, err, `cannot coerce string "0x10" to int64: not a number`)
+	})
+	t.Run("AsBool", func(t *testing.T) {
+		for input, output := range map[Value]bool{
+			Number(1):       true,
+			Number(0):       false,
+			String("TRUE"):  true,
+			String("f"):     false,
+			Bool(true):      true,
+			String(" 1 \n"): true,
+		} {
+			got, err := AsBool(input)
+			require.NoError(t, err)
+			require.Equal(t, output, got, "input: %s", input)
+		}
+		_, err := AsBool(Number(2))
+		require.EqualError(t, err, `cannot coerce number 2 to bool: only 0 and 1 are allowed`)
+	})
+	t.Run("AsString", func(t *testing.T) {
+		for input, output := range map[Value]string{
+			Number(42):    "42",
+			Number(0.5):   "0.5",
+			Number(1e21):  "1e+21",
+			Number(1e-7):  "1e-7",
+			Bool(false):   "false",
+			String("foo"): "foo",
+		} {
+			got, err := AsString(input)
+			require.NoError(t, err)
+			require.Equal(t, output, got)
+		}
+		_, err := AsString(Struct{})
+		require.Error(t, err)
+	})
+}
+
+func TestInferScalar(t *testing.T) {
+	for input, want := range map[string]Value{
+		"":                     String(""),
+		"True":                 Bool(true),
+		"false":                Bool(false),
+		"1":                    Number(1),
+		"-2.5e3":               Number(-2500),
+		"007":                  String("007"),
+		"+1":                   String("+1"),
+		"NaN":                  String("NaN"),
+		"0x10":                 String("0x10"),
+		"9007199254740992":     Number(9007199254740992),
+		"12345678901234567890": String("12345678901234567890"),
+		"1e400":                String("1e400"),
+		" 1":                   String(" 1"),
+		"hello":                String("hello"),
+	} {
+		require.Equal(t, want, inferScalar(input), input)
+	}
+}
@@ -0,0 +1,134 @@
+package simple
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Compare returns -1, 0 or +1 depending on whether a sorts before, the same
+// as, or after b. Values of different kinds are ordered by their kind:
+//
+//	null < bool < number < string < array < struct
+//
+// Within a kind, false sorts before true, numbers are compared numerically
+// with NaN before every other number, strings are compared by their bytes,
+// arrays are compared element by element and then by length, and structs are
+// compared by their sorted keys and the values of those keys, the same way.
+// Values that are semantically equal compare as 0, like a [Number] and an
+// [Int] of the same value, or a nil [Struct] and an empty one.
+func Compare(a, b Value) int {
+	if c := cmp.Compare(kindRank(a), kindRank(b)); c != 0 {
+		return c
+	}
+	switch ta := a.(type) {
+	case Bool:
+		tb := b.(Bool)
+		switch {
+		case ta == tb:
+			return 0
+		case bool(tb):
+			return -1
+		}
+		return 1
+	case Number, RawNumber, Int:
+		ia, aInt := a.(Int)
+		ib, bInt := b.(Int)
+		if aInt && bInt {
+			return cmp.Compare(ia, ib)
+		}
+		fa, _ := numberValue(a)
+		fb, _ := numberValue(b)
+		if fa == 0 && fb == 0 {
+			// negative zero is the same as zero
+			return 0
+		}
+		return cmp.Compare(fa, fb)
+	case String:
+		return strings.Compare(string(ta), string(b.(String)))
+	case Array:
+		tb := b.(Array)
+		for i := 0; i < len(ta) && i < len(tb); i++ {
+			if c := Compare(ta[i], tb[i]); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(len(ta), len(tb))
+	case Struct:
+		tb := b.(Struct)
+		ka, kb := sortedKeys(ta), sortedKeys(tb)
+		for i := 0; i < len(ka) && i < len(kb); i++ {
+			if c := strings.Compare(ka[i], kb[i]); c != 0 {
+				return c
+			}
+			if c := Compare(ta[ka[i]], tb[kb[i]]); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(len(ka), len(kb))
+	}
+	return 0
+}
+
+func kindRank(v Value) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case Bool:
+		return 1
+	case Number, RawNumber, Int:
+		return 2
+	case String:
+		return 3
+	case Array:
+		return 4
+	case Struct:
+		return 5
+	}
+	return 6
+}
+
+// SortOrder is the direction of [SortBy] and [SortByFunc].
+type SortOrder int
+
+const (
+	// Ascending puts the smallest keys first.
+	Ascending SortOrder = iota
+	// Descending puts the largest keys first.
+	Descending
+)
+
+// SortBy returns a copy of a sorted by the value at key inside of each
+// element, ordered by [Compare]. Elements without a value at key sort like
+// null. The sort is stable, so elements with equal keys keep their order in
+// either direction.
+func SortBy(a Array, key Path, order SortOrder) Array {
+	return SortByFunc(a, func(v Value) Value {
+		kv, _ := Lookup(v, key)
+		return kv
+	}, order)
+}
+
+// SortByFunc is like [SortBy], with the key of each element extracted by a
+// function, which is called once per element.
+func SortByFunc(a Array, key func(Value) Value, order SortOrder) Array {
+	type keyed struct {
+		key Value
+		v   Value
+	}
+	ks := make([]keyed, len(a))
+	for i, ev := range a {
+		ks[i] = keyed{key: key(ev), v: ev}
+	}
+	slices.SortStableFunc(ks, func(x, y keyed) int {
+		if order == Descending {
+			return Compare(y.key, x.key)
+		}
+		return Compare(x.key, y.key)
+	})
+	out := make(Array, len(ks))
+	for i, k := range ks {
+		out[i] = k.v
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package simple
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	ordered := []Value{
+		nil,
+		Bool(false),
+		Bool(true),
+		Number(math.NaN()),
+		Number(math.Inf(-1)),
+		Int(-1),
+		Number(0.5),
+		RawNumber("1e3"),
+		String(""),
+		String("a"),
+		String("b"),
+		Array{},
+		Array{Int(1)},
+		Array{Int(1), Int(2)},
+		Array{Int(2)},
+		Struct{},
+		Struct{"a": Int(1)},
+		Struct{"a": Int(2)},
+		Struct{"a": Int(2), "b": nil},
+		Struct{"b": nil},
+	}
+	for i := range ordered {
+		for j := range ordered {
+			require.Equal(t, cmpInts(i, j), Compare(ordered[i], ordered[j]), "%d %d", i, j)
+		}
+	}
+	require.Equal(t, 0, Compare(Int(1), Number(1)))
+	require.Equal(t, 0, Compare(Number(math.Copysign(0, -1)), Int(0)))
+	require.Equal(t, 0, Compare(Struct(nil), Struct{}))
+	require.Equal(t, -1, Compare(Int(1<<62), Int(1<<62+1)))
+}
+
+func cmpInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func TestSortBy(t *testing.T) {
+	a := Array{
+		Struct{"id": Int(1), "age": Int(30)},
+		Struct{"id": Int(2)},
+		Struct{"id": Int(3), "age": Number(25)},
+		Struct{"id": Int(4), "age": Int(30)},
+	}
+	age := MustParsePath("age")
+	ids := func(a Array) []Value {
+		var out []Value
+		for _, ev := range a {
+			out = append(out, ev.(Struct)["id"])
+		}
+		return out
+	}
+	require.Equal(t, []Value{Int(2), Int(3), Int(1), Int(4)}, ids(SortBy(a, age, Ascending)))
+	require.Equal(t, []Value{Int(1), Int(4), Int(3), Int(2)}, ids(SortBy(a, age, Descending)))
+	require.Equal(t, Int(1), a[0].(Struct)["id"], "the input is not modified")
+
+	byLen := SortByFunc(Array{String("ccc"), String("a"), String("bb")}, func(v Value) Value {
+		return Int(len(v.(String)))
+	}, Ascending)
+	require.Equal(t, Array{String("a"), String("bb"), String("ccc")}, byLen)
+}
@@ -0,0 +1,149 @@
+package simple
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// CSVOption changes the behavior of [ReadCSV] and [WriteCSV].
+type CSVOption func(*csvOptions)
+
+type csvOptions struct {
+	comma   rune
+	infer   bool
+	columns []string
+}
+
+// CSVComma sets the field delimiter, which is ',' by default.
+func CSVComma(r rune) CSVOption {
+	return func(co *csvOptions) { co.comma = r }
+}
+
+// InferTypes makes [ReadCSV] convert cells that look like booleans or numbers
+// to a [Bool] or a [Number]. Without it, every cell is a [String].
+func InferTypes() CSVOption {
+	return func(co *csvOptions) { co.infer = true }
+}
+
+// CSVColumns sets the columns written by [WriteCSV], and their order. By
+// default every key that appears in any row is written, in sorted order.
+func CSVColumns(columns ...string) CSVOption {
+	return func(co *csvOptions) { co.columns = columns }
+}
+
+func csvOptionsOf(opts []CSVOption) csvOptions {
+	co := csvOptions{comma: ','}
+	for _, o := range opts {
+		o(&co)
+	}
+	return co
+}
+
+// ReadCSV reads CSV data with a header row and returns an [Array] with a
+// [Struct] for every other row, keyed by the header. Every row must have as
+// many fields as the header.
+func ReadCSV(r io.Reader, opts ...CSVOption) (Array, error) {
+	co := csvOptionsOf(opts)
+	cr := csv.NewReader(r)
+	cr.Comma = co.comma
+	header, err := cr.Read()
+	if err == io.EOF {
+		return Array{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(header))
+	for _, h := range header {
+		if seen[h] {
+			return nil, fmt.Errorf("duplicate CSV column %q", h)
+		}
+		seen[h] = true
+	}
+	out := Array{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := make(Struct, len(header))
+		for i, cell := range record {
+			if co.infer {
+				row[header[i]] = inferScalar(cell)
+			} else {
+				row[header[i]] = String(cell)
+			}
+		}
+		out = append(out, row)
+	}
+}
+
+// WriteCSV writes a, which must hold nothing but Structs, as CSV data with a
+// header row. Scalars are rendered with [AsString], nulls and missing keys
+// are written as empty cells, and composites are written as JSON.
+func WriteCSV(w io.Writer, a Array, opts ...CSVOption) error {
+	co := csvOptionsOf(opts)
+	rows, err := structRows(a, "CSV")
+	if err != nil {
+		return err
+	}
+	columns := co.columns
+	if columns == nil {
+		columns = allKeys(rows)
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = co.comma
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	record := make([]string, len(columns))
+	for i, row := range rows {
+		for j, col := range columns {
+			switch tv := row[col].(type) {
+			case nil:
+				record[j] = ""
+			case Struct, Array:
+				record[j] = tv.String()
+			default:
+				str, err := AsString(tv)
+				if err != nil {
+					return encodeError{path: Path{i, col}, problem: err.Error(), err: err}
+				}
+				record[j] = str
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// structRows checks that every element of a is a Struct.
+func structRows(a Array, format string) ([]Struct, error) {
+	rows := make([]Struct, len(a))
+	for i, ev := range a {
+		row, ok := ev.(Struct)
+		if !ok {
+			return nil, encodeError{path: Path{i}, problem: fmt.Sprintf("%s cannot be written as a %s row", kindName(ev), format)}
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// allKeys returns every key that appears in any of rows, sorted.
+func allKeys(rows []Struct) []string {
+	keys := map[string]bool{}
+	for _, row := range rows {
+		for k := range row {
+			keys[k] = true
+		}
+	}
+	return sortedKeysOf(keys)
+}
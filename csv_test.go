@@ -0,0 +1,60 @@
+package simple
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCSV(t *testing.T) {
+	input := "name,age,active,zip\nann,31,true,02134\n\"b, c\",,FALSE,x\n"
+	a, err := ReadCSV(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Equal(t, Array{
+		Struct{"name": String("ann"), "age": String("31"), "active": String("true"), "zip": String("02134")},
+		Struct{"name": String("b, c"), "age": String(""), "active": String("FALSE"), "zip": String("x")},
+	}, a)
+
+	a, err = ReadCSV(strings.NewReader(input), InferTypes())
+	require.NoError(t, err)
+	require.Equal(t, `[{"active":true,"age":31,"name":"ann","zip":"02134"},{"active":false,"age":"","name":"b, c","zip":"x"}]`, a.String())
+
+	a, err = ReadCSV(strings.NewReader("a;b\n1;2\n"), CSVComma(';'))
+	require.NoError(t, err)
+	require.Equal(t, Array{Struct{"a": String("1"), "b": String("2")}}, a)
+
+	a, err = ReadCSV(strings.NewReader(""))
+	require.NoError(t, err)
+	require.Equal(t, Array{}, a)
+
+	_, err = ReadCSV(strings.NewReader("a,a\n1,2\n"))
+	require.EqualError(t, err, `duplicate CSV column "a"`)
+	_, err = ReadCSV(strings.NewReader("a,b\n1\n"))
+	require.Error(t, err)
+}
+
+func TestWriteCSV(t *testing.T) {
+	a := Array{
+		Struct{"name": String("ann"), "age": Number(31), "tags": Array{String("x")}},
+		Struct{"name": String("b, c"), "active": Bool(false), "age": nil},
+	}
+	var sb strings.Builder
+	require.NoError(t, WriteCSV(&sb, a))
+	require.Equal(t, "active,age,name,tags\n,31,ann,\"[\"\"x\"\"]\"\nfalse,,\"b, c\",\n", sb.String())
+
+	sb.Reset()
+	require.NoError(t, WriteCSV(&sb, a, CSVColumns("name", "age"), CSVComma('\t')))
+	require.Equal(t, "name\tage\nann\t31\nb, c\t\n", sb.String())
+
+	back, err := ReadCSV(strings.NewReader(sb.String()), CSVComma('\t'), InferTypes())
+	require.NoError(t, err)
+	require.Equal(t, Array{
+		Struct{"name": String("ann"), "age": Number(31)},
+		Struct{"name": String("b, c"), "age": String("")},
+	}, back)
+
+	require.EqualError(t, WriteCSV(&sb, Array{Struct{}, Number(1)}), "cannot encode value at [1]: number cannot be written as a CSV row")
+	require.EqualError(t, WriteCSV(&sb, Array{Struct{"n": Number(math.Inf(1))}}), "cannot encode value at [0].n: cannot coerce number +Inf to string: not a finite number")
+}
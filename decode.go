@@ -0,0 +1,380 @@
+package simple
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DecodeOption changes the behavior of [Decode].
+type DecodeOption func(*decoder)
+
+// Strict makes [Decode] fail when the value has struct keys that do not match
+// any field of the target, when null is given for something that cannot be
+// nil, and when a number cannot be stored without losing precision.
+func Strict() DecodeOption {
+	return func(d *decoder) { d.strict = true }
+}
+
+// WeaklyTyped makes [Decode] convert scalars between kinds when the target
+// type calls for it, following the rules of [AsString], [AsBool], [AsInt64]
+// and [AsFloat64]. So "1" can be stored in an int, 0 and 1 in a bool, and
+// numbers in a string. A single value given for a slice is decoded as a slice
+// of one element. This is useful for data from sources with sloppy typing, like
+// environment variables and YAML.
+func WeaklyTyped() DecodeOption {
+	return func(d *decoder) { d.weak = true }
+}
+
+type decoder struct {
+	strict bool
+	weak   bool
+}
+
+type decodeError struct {
+	path    Path
+	problem string
+	err     error
+}
+
+func (d decodeError) Unwrap() error { return d.err }
+
+func (d decodeError) Error() string {
+	return fmt.Sprintf("cannot decode value at %s: %s", d.path, d.problem)
+}
+
+func (d decodeError) Pointer() string { return d.path.Pointer() }
+
+var valueReflectType = reflect.TypeFor[Value]()
+
+// Decode is the inverse of [FromValue], it stores v in the Go value pointed to
+// by target.
+//
+// Struct keys are matched to exported struct fields by name, preferring an
+// exact match but falling back to a case-insensitive one. Fields of type
+// [Value] (or any of the concrete [Value] types) receive the value as-is, and
+// fields of type any receive the result of [ToAny]. Numbers must fit in the
+// integer types they are stored in.
+//
+// By default, unknown keys are ignored and null leaves the target untouched,
+// like encoding/json. Use [Strict] to turn those into errors.
+func Decode(v Value, target any, opts ...DecodeOption) error {
+	var d decoder
+	for _, o := range opts {
+		o(&d)
+	}
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("simple.Decode: target must be a non-nil pointer, got %T", target)
+	}
+	return d.decode(Path{}, v, rv.Elem())
+}
+
+func (d *decoder) mismatch(path Path, v Value, rt reflect.Type) error {
+	return decodeError{
+		path:    path,
+		problem: fmt.Sprintf("cannot store %s in %s", kindName(v), rt),
+	}
+}
+
+func (d *decoder) decode(path Path, v Value, rv reflect.Value) error {
+	rt := rv.Type()
+
+	// interfaces are filled with the value itself, or the plain Go version of
+	// it when that is what fits.
+	if rt.Kind() == reflect.Interface {
+		if rt.NumMethod() == 0 {
+			if v == nil {
+				rv.SetZero()
+			} else {
+				rv.Set(reflect.ValueOf(ToAny(v)))
+			}
+			return nil
+		}
+		if rt == valueReflectType {
+			if v == nil {
+				rv.SetZero()
+			} else {
+				rv.Set(reflect.ValueOf(v))
+			}
+			return nil
+		}
+		return decodeError{path: path, problem: fmt.Sprintf("unsupported interface type %s", rt), err: ErrUnsupportedKind}
+	}
+	if v != nil && reflect.TypeOf(v) == rt {
+		rv.Set(reflect.ValueOf(v))
+		return nil
+	}
+
+	if v == nil {
+		switch rt.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Slice:
+			rv.SetZero()
+			return nil
+		}
+		if d.strict {
+			return decodeError{path: path, problem: fmt.Sprintf("cannot store null in %s", rt)}
+		}
+		return nil
+	}
+
+	switch rt.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rt.Elem()))
+		}
+		return d.decode(path, v, rv.Elem())
+	case reflect.Struct:
+		sv, ok := v.(Struct)
+		if !ok {
+			return d.mismatch(path, v, rt)
+		}
+		return d.decodeStruct(path, sv, rv)
+	case reflect.Map:
+		sv, ok := v.(Struct)
+		if !ok {
+			return d.mismatch(path, v, rt)
+		}
+		return d.decodeMap(path, sv, rv)
+	case reflect.Slice:
+		av, ok := v.(Array)
+		if !ok && d.weak {
+			av, ok = Array{v}, true
+		}
+		if !ok {
+			return d.mismatch(path, v, rt)
+		}
+		out := reflect.MakeSlice(rt, len(av), len(av))
+		for i, ev := range av {
+			if err := d.decode(path.Index(i), ev, out.Index(i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(out)
+		return nil
+	case reflect.Array:
+		av, ok := v.(Array)
+		if !ok {
+			return d.mismatch(path, v, rt)
+		}
+		if len(av) > rt.Len() && d.strict {
+			return decodeError{path: path, problem: fmt.Sprintf("%d elements do not fit in %s", len(av), rt)}
+		}
+		rv.SetZero()
+		for i := 0; i < len(av) && i < rt.Len(); i++ {
+			if err := d.decode(path.Index(i), av[i], rv.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return d.decodeScalar(path, v, rv)
+}
+
+func (d *decoder) decodeScalar(path Path, v Value, rv reflect.Value) error {
+	rt := rv.Type()
+	switch rt.Kind() {
+	case reflect.String:
+		s, ok := v.(String)
+		if !ok && d.weak {
+			str, err := AsString(v)
+			if err != nil {
+				return decodeError{path: path, problem: err.Error()}
+			}
+			s, ok = String(str), true
+		}
+		if !ok {
+			return d.mismatch(path, v, rt)
+		}
+		rv.SetString(string(s))
+		return nil
+	case reflect.Bool:
+		b, ok := v.(Bool)
+		if !ok && d.weak {
+			bb, err := AsBool(v)
+			if err != nil {
+				return decodeError{path: path, problem: err.Error()}
+			}
+			b, ok = Bool(bb), true
+		}
+		if !ok {
+			return d.mismatch(path, v, rt)
+		}
+		rv.SetBool(bool(b))
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// integers beyond 2^53 survive when they never become a float
+		switch tv := v.(type) {
+		case Int:
+			if !rv.OverflowInt(int64(tv)) {
+				rv.SetInt(int64(tv))
+				return nil
+			}
+		case RawNumber:
+			if i, err := strconv.ParseInt(string(tv), 10, 64); err == nil && !rv.OverflowInt(i) {
+				rv.SetInt(i)
+				return nil
+			}
+		}
+		n, ok := d.number(v)
+		if !ok {
+			return d.mismatch(path, v, rt)
+		}
+		i, err := floatToInt64(v, float64(n))
+		if err != nil || rv.OverflowInt(i) {
+			return decodeError{path: path, problem: fmt.Sprintf("number %s does not fit in %s", formatNumber(float64(n)), rt)}
+		}
+		rv.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch tv := v.(type) {
+		case Int:
+			if tv >= 0 && !rv.OverflowUint(uint64(tv)) {
+				rv.SetUint(uint64(tv))
+				return nil
+			}
+		case RawNumber:
+			if u, err := strconv.ParseUint(string(tv), 10, 64); err == nil && !rv.OverflowUint(u) {
+				rv.SetUint(u)
+				return nil
+			}
+		}
+		n, ok := d.number(v)
+		if !ok {
+			return d.mismatch(path, v, rt)
+		}
+		f := float64(n)
+		if f < 0 || f != math.Trunc(f) || f >= math.MaxUint64 || rv.OverflowUint(uint64(f)) {
+			return decodeError{path: path, problem: fmt.Sprintf("number %s does not fit in %s", formatNumber(f), rt)}
+		}
+		rv.SetUint(uint64(f))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		n, ok := d.number(v)
+		if !ok {
+			return d.mismatch(path, v, rt)
+		}
+		f := float64(n)
+		if d.strict && rt.Kind() == reflect.Float32 && float64(float32(f)) != f {
+			return decodeError{path: path, problem: fmt.Sprintf("number %s cannot be stored in %s without losing precision", formatNumber(f), rt)}
+		}
+		rv.SetFloat(f)
+		return nil
+	}
+	return decodeError{path: path, problem: fmt.Sprintf("unsupported type %s", rt), err: ErrUnsupportedKind}
+}
+
+// number returns v as a Number, converting it first in weakly typed mode.
+func (d *decoder) number(v Value) (Number, bool) {
+	if n, ok := numberValue(v); ok {
+		return Number(n), true
+	}
+	if !d.weak {
+		return 0, false
+	}
+	f, err := AsFloat64(v)
+	if err != nil {
+		return 0, false
+	}
+	return Number(f), true
+}
+
+func (d *decoder) decodeStruct(path Path, sv Struct, rv reflect.Value) error {
+	rt := rv.Type()
+	used := make(map[string]bool, len(sv))
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := field.Name
+		ev, ok := sv[key]
+		if !ok {
+			for k := range sv {
+				if strings.EqualFold(k, field.Name) && (!ok || k < key) {
+					key, ev, ok = k, sv[k], true
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+		used[key] = true
+		if err := d.decode(path.Key(key), ev, rv.Field(i)); err != nil {
+			return err
+		}
+	}
+	if d.strict && len(used) != len(sv) {
+		for _, k := range sortedKeys(sv) {
+			if !used[k] {
+				return decodeError{path: path.Key(k), problem: fmt.Sprintf("%s has no field for this key", rt)}
+			}
+		}
+	}
+	return nil
+}
+
+func (d *decoder) decodeMap(path Path, sv Struct, rv reflect.Value) error {
+	rt := rv.Type()
+	if rv.IsNil() {
+		rv.Set(reflect.MakeMapWithSize(rt, len(sv)))
+	}
+	for _, k := range sortedKeys(sv) {
+		key := reflect.New(rt.Key()).Elem()
+		if err := decodeMapKey(k, key); err != nil {
+			de, ok := err.(decodeError)
+			if !ok {
+				de = decodeError{problem: err.Error()}
+			}
+			de.path = path.Key(k)
+			return de
+		}
+		elem := reflect.New(rt.Elem()).Elem()
+		if err := d.decode(path.Key(k), sv[k], elem); err != nil {
+			return err
+		}
+		rv.SetMapIndex(key, elem)
+	}
+	return nil
+}
+
+// decodeMapKey is the inverse of stringify.
+func decodeMapKey(k string, rv reflect.Value) error {
+	rt := rv.Type()
+	switch rt.Kind() {
+	case reflect.String:
+		rv.SetString(k)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(k, 10, rt.Bits())
+		if err != nil {
+			return fmt.Errorf("key %q is not a valid %s", k, rt)
+		}
+		rv.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(k, 10, rt.Bits())
+		if err != nil {
+			return fmt.Errorf("key %q is not a valid %s", k, rt)
+		}
+		rv.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(k, rt.Bits())
+		if err != nil {
+			return fmt.Errorf("key %q is not a valid %s", k, rt)
+		}
+		rv.SetFloat(f)
+		return nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(k)
+		if err != nil {
+			return fmt.Errorf("key %q is not a valid %s", k, rt)
+		}
+		rv.SetBool(b)
+		return nil
+	}
+	return decodeError{problem: fmt.Sprintf("map key type %s is not supported", rt), err: ErrUnstringifiableMapKey}
+}
@@ -0,0 +1,124 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	type address struct {
+		City string
+		Zip  *string
+	}
+	type config struct {
+		Name     string
+		Port     uint16
+		Ratio    float32
+		Debug    bool
+		Tags     []string
+		Limits   map[string]int
+		ByID     map[int]bool
+		Address  *address
+		Extra    any
+		Raw      Value
+		Pair     [2]int
+		internal int
+	}
+
+	input := mustFromJSON(t, `{
+		"name": "svc",
+		"Port": 8080,
+		"Ratio": 0.5,
+		"Debug": true,
+		"Tags": ["a", "b"],
+		"Limits": {"cpu": 2},
+		"ByID": {"7": true},
+		"Address": {"City": "Springfield", "Zip": "12345"},
+		"Extra": {"x": [1]},
+		"Raw": {"y": false},
+		"Pair": [1, 2],
+		"unknown": 1
+	}`)
+
+	var got config
+	require.NoError(t, Decode(input, &got))
+	zip := "12345"
+	require.Equal(t, config{
+		Name:    "svc",
+		Port:    8080,
+		Ratio:   0.5,
+		Debug:   true,
+		Tags:    []string{"a", "b"},
+		Limits:  map[string]int{"cpu": 2},
+		ByID:    map[int]bool{7: true},
+		Address: &address{City: "Springfield", Zip: &zip},
+		Extra:   map[string]any{"x": []any{float64(1)}},
+		Raw:     Struct{"y": Bool(false)},
+		Pair:    [2]int{1, 2},
+	}, got)
+
+	roundtrip, err := FromValue(got)
+	require.NoError(t, err)
+	var again config
+	require.NoError(t, Decode(roundtrip, &again, Strict()))
+	require.Equal(t, got, again)
+
+	for _, tc := range []struct {
+		input string
+		err   string
+	}{
+		{input: `{"Port": 70000}`, err: `cannot decode value at .Port: number 70000 does not fit in uint16`},
+		{input: `{"Port": 1.5}`, err: `cannot decode value at .Port: number 1.5 does not fit in uint16`},
+		{input: `{"Tags": [1]}`, err: `cannot decode value at .Tags[0]: cannot store number in string`},
+		{input: `{"Address": []}`, err: `cannot decode value at .Address: cannot store array in simple.address`},
+		{input: `{"ByID": {"x": true}}`, err: `cannot decode value at .ByID.x: key "x" is not a valid int`},
+	} {
+		require.EqualError(t, Decode(mustFromJSON(t, tc.input), &config{}), tc.err)
+	}
+
+	require.Error(t, Decode(Struct{}, config{}))
+}
+
+func TestDecodeStrict(t *testing.T) {
+	type target struct {
+		A int
+		F float32
+	}
+	for _, tc := range []struct {
+		input string
+		err   string
+	}{
+		{input: `{"A": 1, "b": 2}`, err: `cannot decode value at .b: simple.target has no field for this key`},
+		{input: `{"A": null}`, err: `cannot decode value at .A: cannot store null in int`},
+		{input: `{"F": 0.1}`, err: `cannot decode value at .F: number 0.1 cannot be stored in float32 without losing precision`},
+	} {
+		require.NoError(t, Decode(mustFromJSON(t, tc.input), &target{}))
+		require.EqualError(t, Decode(mustFromJSON(t, tc.input), &target{}, Strict()), tc.err)
+	}
+}
+
+func TestDecodeWeaklyTyped(t *testing.T) {
+	type target struct {
+		Port    int
+		Enabled bool
+		Name    string
+		Ratio   float64
+		Hosts   []string
+	}
+	input := mustFromJSON(t, `{"Port": "8080", "Enabled": "1", "Name": 42, "Ratio": " 0.25 ", "Hosts": "a.example"}`)
+	require.Error(t, Decode(input, &target{}))
+
+	var got target
+	require.NoError(t, Decode(input, &got, WeaklyTyped()))
+	require.Equal(t, target{Port: 8080, Enabled: true, Name: "42", Ratio: 0.25, Hosts: []string{"a.example"}}, got)
+
+	require.EqualError(t,
+		Decode(Struct{"Enabled": Number(2)}, &target{}, WeaklyTyped()),
+		`cannot decode value at .Enabled: cannot coerce number 2 to bool: only 0 and 1 are allowed`,
+	)
+	require.EqualError(t,
+		Decode(Struct{"Port": String("x")}, &target{}, WeaklyTyped()),
+		`cannot decode value at .Port: cannot store string in int`,
+	)
+}
@@ -0,0 +1,101 @@
+package simple
+
+// DefaultsOption changes how [ApplyDefaults] fills in values.
+type DefaultsOption func(*defaulter)
+
+// ArrayPolicy decides what [ApplyDefaults] does when both the value and the
+// defaults have an [Array] at the same location.
+type ArrayPolicy int
+
+const (
+	// ArraysKeep keeps the array from the value and ignores the default.
+	ArraysKeep ArrayPolicy = iota
+	// ArraysByIndex applies defaults element by element, and elements only
+	// present in the default array are appended.
+	ArraysByIndex
+	// ArraysAppend appends the default array to the array from the value.
+	ArraysAppend
+)
+
+// WithArrayPolicy sets the [ArrayPolicy] used by [ApplyDefaults], the default
+// is [ArraysKeep].
+func WithArrayPolicy(p ArrayPolicy) DefaultsOption {
+	return func(d *defaulter) { d.arrays = p }
+}
+
+// FillNulls makes [ApplyDefaults] treat null the same as a missing key.
+func FillNulls() DefaultsOption {
+	return func(d *defaulter) { d.fillNulls = true }
+}
+
+type defaulter struct {
+	arrays    ArrayPolicy
+	fillNulls bool
+}
+
+// ApplyDefaults returns a copy of v where every key that is missing from a
+// [Struct] has been filled in from the same location in defaults. Keys that
+// are present in v are never overwritten, but Structs on both sides are merged
+// recursively. This allows layered configuration to be expressed with Values:
+//
+//	cfg := ApplyDefaults(userConfig, ApplyDefaults(siteConfig, builtinConfig))
+//
+// Neither v nor defaults are modified, and the result does not share any
+// Structs or Arrays with them.
+func ApplyDefaults(v, defaults Value, opts ...DefaultsOption) Value {
+	var d defaulter
+	for _, o := range opts {
+		o(&d)
+	}
+	return d.apply(v, defaults)
+}
+
+func (d *defaulter) apply(v, defaults Value) Value {
+	if v == nil && d.fillNulls {
+		return Clone(defaults)
+	}
+	switch tv := v.(type) {
+	case Struct:
+		ds, ok := defaults.(Struct)
+		if !ok {
+			break
+		}
+		out := make(Struct, len(tv)+len(ds))
+		for k, ev := range tv {
+			if dv, ok := ds[k]; ok {
+				out[k] = d.apply(ev, dv)
+			} else {
+				out[k] = Clone(ev)
+			}
+		}
+		for k, dv := range ds {
+			if _, ok := tv[k]; !ok {
+				out[k] = Clone(dv)
+			}
+		}
+		return out
+	case Array:
+		da, ok := defaults.(Array)
+		if !ok {
+			break
+		}
+		switch d.arrays {
+		case ArraysByIndex:
+			out := make(Array, max(len(tv), len(da)))
+			for i := range out {
+				switch {
+				case i >= len(tv):
+					out[i] = Clone(da[i])
+				case i >= len(da):
+					out[i] = Clone(tv[i])
+				default:
+					out[i] = d.apply(tv[i], da[i])
+				}
+			}
+			return out
+		case ArraysAppend:
+			return Clone(append(tv[:len(tv):len(tv)], da...))
+		}
+	}
+	return Clone(v)
+}
@@ -0,0 +1,47 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyDefaults(t *testing.T) {
+	defaults := mustFromJSON(t, `{
+		"server": {"host": "0.0.0.0", "port": 8080, "tls": {"enabled": false}},
+		"log": null,
+		"plugins": ["core"],
+		"workers": [{"name": "default", "threads": 2}]
+	}`)
+	v := mustFromJSON(t, `{
+		"server": {"port": 9090, "tls": null},
+		"log": {"level": "debug"},
+		"plugins": ["extra"],
+		"workers": [{"name": "a"}, {"name": "b"}]
+	}`)
+
+	require.Equal(t, mustFromJSON(t, `{
+		"server": {"host": "0.0.0.0", "port": 9090, "tls": null},
+		"log": {"level": "debug"},
+		"plugins": ["extra"],
+		"workers": [{"name": "a"}, {"name": "b"}]
+	}`), ApplyDefaults(v, defaults))
+
+	require.Equal(t, mustFromJSON(t, `{
+		"server": {"host": "0.0.0.0", "port": 9090, "tls": {"enabled": false}},
+		"log": {"level": "debug"},
+		"plugins": ["extra"],
+		"workers": [{"name": "a", "threads": 2}, {"name": "b"}]
+	}`), ApplyDefaults(v, defaults, FillNulls(), WithArrayPolicy(ArraysByIndex)))
+
+	got := ApplyDefaults(v, defaults, WithArrayPolicy(ArraysAppend))
+	require.Equal(t, mustFromJSON(t, `["extra", "core"]`), got.(Struct)["plugins"])
+	require.Len(t, v.(Struct)["plugins"], 1, "input must not be modified")
+
+	// the result must not share anything with the defaults
+	got.(Struct)["server"].(Struct)["host"] = String("changed")
+	require.Equal(t, String("0.0.0.0"), defaults.(Struct)["server"].(Struct)["host"])
+
+	require.Equal(t, Number(1), ApplyDefaults(nil, Number(1), FillNulls()))
+	require.Nil(t, ApplyDefaults(nil, Number(1)))
+}
@@ -0,0 +1,60 @@
+package simple
+
+import (
+	"reflect"
+	"strings"
+)
+
+// DiagnoseTypes makes the errors of [FromValue] name the Go type of the value
+// that could not be converted, next to its path:
+//
+//	cannot convert value at .Handlers[2] (func(int)): cannot convert value of kind func to simple value
+//
+// If types is not nil, the Go type of every value that is converted is also
+// recorded in it, keyed by its path written the same way, so types[".ID"]
+// could be "uuid.UUID". The root has the path "". Values inside interfaces
+// are recorded with their concrete type.
+func DiagnoseTypes(types map[string]string) FromValueOption {
+	return func(c *converter) {
+		c.diagnose = true
+		c.types = types
+	}
+}
+
+func (c *converter) fromReflectValue(rv reflect.Value, path []string) (Value, error) {
+	if !c.diagnose || !rv.IsValid() {
+		return c.convert(rv, path)
+	}
+	typ := rv.Type()
+	if rv.Kind() == reflect.Interface && !rv.IsNil() {
+		typ = rv.Elem().Type()
+	}
+	if c.types != nil {
+		c.types[strings.Join(path, "")] = typ.String()
+	}
+	v, err := c.convert(rv, path)
+	// only the innermost value that failed is annotated
+	switch te := err.(type) {
+	case fromValueError:
+		if te.typ == "" {
+			te.typ = typ.String()
+			err = te
+		}
+	case fromValueWrappedError:
+		if te.typ == "" {
+			te.typ = typ.String()
+			err = te
+		}
+	}
+	return v, err
+}
+
+// describeAt writes path for error messages, followed by the Go type when it
+// is known.
+func describeAt(path []string, typ string) string {
+	at := strings.Join(path, "")
+	if typ != "" {
+		at += " (" + typ + ")"
+	}
+	return at
+}
@@ -0,0 +1,35 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnoseTypes(t *testing.T) {
+	type handler struct {
+		Name string
+		Fn   func(int)
+	}
+	input := map[string]any{
+		"handlers": []*handler{{Name: "a"}},
+	}
+
+	_, err := FromValue(input, DiagnoseTypes(nil))
+	require.EqualError(t, err, "cannot convert value at .handlers[0].Fn (func(int)): cannot convert value of kind func to simple value")
+	require.ErrorIs(t, err, ErrUnsupportedKind)
+
+	types := map[string]string{}
+	var errs []error
+	v, err := FromValue(input, DiagnoseTypes(types), CollectErrors(&errs))
+	require.NoError(t, err)
+	require.Equal(t, Struct{"handlers": Array{Struct{"Name": String("a"), "Fn": nil}}}, v)
+	require.Len(t, errs, 1)
+	require.Equal(t, map[string]string{
+		"":                  "map[string]interface {}",
+		".handlers":         "[]*simple.handler",
+		".handlers[0]":      "simple.handler",
+		".handlers[0].Name": "string",
+		".handlers[0].Fn":   "func(int)",
+	}, types)
+}
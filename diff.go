@@ -0,0 +1,104 @@
+package simple
+
+// ChangeKind is the kind of a [Change].
+type ChangeKind int
+
+const (
+	// Added means the value only exists in the new Value.
+	Added ChangeKind = iota
+	// Removed means the value only exists in the old Value.
+	Removed
+	// Modified means the value exists in both, but is different.
+	Modified
+)
+
+// String returns the name of the kind.
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	}
+	return "unknown"
+}
+
+// Change is a single difference found by [Diff]. Old is nil for an added
+// value and New is nil for a removed one.
+type Change struct {
+	Kind ChangeKind
+	Path Path
+	Old  Value
+	New  Value
+}
+
+// Diff returns the differences between a and b, as the smallest values that
+// changed. Structs are compared key by key and Arrays index by index, so an
+// element inserted in the middle of an Array modifies all elements after it.
+// Values of different types are a single modification. Numbers are compared
+// by value, so Int(1) and Number(1) are the same. Changes are returned in the
+// order of their paths, with Struct keys in sorted order.
+func Diff(a, b Value) []Change {
+	var changes []Change
+	diff(&changes, Path{}, a, b, equal)
+	return changes
+}
+
+// diff appends the changes between a and b to changes, comparing scalars with
+// eq.
+func diff(changes *[]Change, path Path, a, b Value, eq func(a, b Value) bool) {
+	switch ta := a.(type) {
+	case Struct:
+		tb, ok := b.(Struct)
+		if !ok {
+			break
+		}
+		keys := make(map[string]struct{}, len(ta)+len(tb))
+		for k := range ta {
+			keys[k] = struct{}{}
+		}
+		for k := range tb {
+			keys[k] = struct{}{}
+		}
+		for _, k := range sortedKeysOf(keys) {
+			av, inA := ta[k]
+			bv, inB := tb[k]
+			switch {
+			case !inB:
+				*changes = append(*changes, Change{Kind: Removed, Path: path.Key(k), Old: av})
+			case !inA:
+				*changes = append(*changes, Change{Kind: Added, Path: path.Key(k), New: bv})
+			default:
+				diff(changes, path.Key(k), av, bv, eq)
+			}
+		}
+		return
+	case Array:
+		tb, ok := b.(Array)
+		if !ok {
+			break
+		}
+		for i := 0; i < max(len(ta), len(tb)); i++ {
+			switch {
+			case i >= len(tb):
+				*changes = append(*changes, Change{Kind: Removed, Path: path.Index(i), Old: ta[i]})
+			case i >= len(ta):
+				*changes = append(*changes, Change{Kind: Added, Path: path.Index(i), New: tb[i]})
+			default:
+				diff(changes, path.Index(i), ta[i], tb[i], eq)
+			}
+		}
+		return
+	}
+	if !eq(a, b) {
+		*changes = append(*changes, Change{Kind: Modified, Path: path, Old: a, New: b})
+	}
+}
+
+// identical reports whether the scalars a and b are the same, down to their
+// representation. Unlike equal, Int(1) and Number(1) are not identical.
+func identical(a, b Value) bool {
+	return a == b
+}
@@ -0,0 +1,35 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	a := Struct{
+		"name": String("ann"),
+		"age":  Int(30),
+		"tags": Array{String("a"), String("b")},
+		"meta": Struct{"x": Bool(true)},
+		"gone": nil,
+	}
+	b := Struct{
+		"name": String("bob"),
+		"age":  Number(30),
+		"tags": Array{String("a"), String("c"), String("d")},
+		"meta": Array{},
+		"new":  nil,
+	}
+	require.Equal(t, []Change{
+		{Kind: Removed, Path: Path{"gone"}},
+		{Kind: Modified, Path: Path{"meta"}, Old: Struct{"x": Bool(true)}, New: Array{}},
+		{Kind: Modified, Path: Path{"name"}, Old: String("ann"), New: String("bob")},
+		{Kind: Added, Path: Path{"new"}},
+		{Kind: Modified, Path: Path{"tags", 1}, Old: String("b"), New: String("c")},
+		{Kind: Added, Path: Path{"tags", 2}, New: String("d")},
+	}, Diff(a, b))
+	require.Empty(t, Diff(a, a))
+	require.Equal(t, []Change{{Kind: Removed, Path: Path{0}, Old: Int(1)}}, Diff(Array{Int(1)}, Array{}))
+	require.Equal(t, "removed", Removed.String())
+}
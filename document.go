@@ -0,0 +1,238 @@
+package simple
+
+import (
+	"maps"
+	"slices"
+)
+
+// Document is a copy-on-write wrapper around a Value, so that a large shared
+// document can be edited cheaply, for example once per request. The wrapped
+// Value is never modified. Instead, the first change below a Struct or Array
+// makes a shallow copy of it that is owned by the Document, and later changes
+// below it modify that copy in place. Everything that is not on the path to a
+// change stays shared with the original.
+//
+// A Document is not safe for concurrent use.
+type Document struct {
+	base Value
+	root Value
+	node *cowNode
+}
+
+// cowNode tracks which Structs and Arrays a Document has copied, and where it
+// made changes. It mirrors the shape of the document, but only along the
+// paths that were changed.
+type cowNode struct {
+	owned    bool
+	changed  bool
+	children map[any]*cowNode
+}
+
+func (n *cowNode) child(e any) *cowNode {
+	if n.children == nil {
+		n.children = map[any]*cowNode{}
+	}
+	c, ok := n.children[e]
+	if !ok {
+		c = &cowNode{}
+		n.children[e] = c
+	}
+	return c
+}
+
+// replaced records that the value at e was replaced with one that the
+// Document does not own.
+func (n *cowNode) replaced(e any) {
+	if n.children == nil {
+		n.children = map[any]*cowNode{}
+	}
+	n.children[e] = &cowNode{changed: true}
+}
+
+// disown forgets about all copies made below n, so they are copied again
+// before the next change.
+func (n *cowNode) disown() {
+	n.owned = false
+	for _, c := range n.children {
+		c.disown()
+	}
+}
+
+func (n *cowNode) dirty() bool {
+	if n.changed {
+		return true
+	}
+	for _, c := range n.children {
+		if c.dirty() {
+			return true
+		}
+	}
+	return false
+}
+
+// NewDocument returns a Document wrapping v, without copying it.
+func NewDocument(v Value) *Document {
+	return &Document{base: v, root: v, node: &cowNode{}}
+}
+
+// Value returns the current state of the document. It shares unchanged
+// parts with the wrapped Value, so it must not be modified.
+func (d *Document) Value() Value {
+	return d.root
+}
+
+// Get returns the value at p, like [Lookup] does. It must not be modified,
+// use Set instead.
+func (d *Document) Get(p Path) (Value, bool) {
+	return Lookup(d.root, p)
+}
+
+// Set puts v at p. Missing Structs along p are created, and an index one past
+// the end of an Array appends to it. Setting a key of something other than a
+// Struct or null, or an index of something other than an Array, is an error,
+// and leaves the document unchanged. v itself is not copied, and is not
+// modified by later changes below p.
+func (d *Document) Set(p Path, v Value) error {
+	if err := checkSetPath(d.root, p); err != nil {
+		return err
+	}
+	if len(p) == 0 {
+		d.root, d.node = v, &cowNode{changed: true}
+		return nil
+	}
+	d.root = d.set(d.node, d.root, p, v)
+	return nil
+}
+
+// set puts v at p below cur, which is tracked by node, and returns the new
+// value of cur. p must not be empty, and must have been checked with
+// checkSetPath.
+func (d *Document) set(node *cowNode, cur Value, p Path, v Value) Value {
+	switch e := p[0].(type) {
+	case string:
+		s, _ := cur.(Struct)
+		if !node.owned {
+			s = maps.Clone(s)
+			if s == nil {
+				s = Struct{}
+			}
+			node.owned = true
+		}
+		if len(p) == 1 {
+			s[e] = v
+			node.replaced(e)
+			return s
+		}
+		s[e] = d.set(node.child(e), s[e], p[1:], v)
+		return s
+	default:
+		i := e.(int)
+		a := cur.(Array)
+		if !node.owned {
+			a = slices.Clone(a)
+			node.owned = true
+		}
+		if i == len(a) {
+			a = append(a, nil)
+		}
+		if len(p) == 1 {
+			a[i] = v
+			node.replaced(i)
+			return a
+		}
+		a[i] = d.set(node.child(i), a[i], p[1:], v)
+		return a
+	}
+}
+
+// Delete removes the value at p. Deleting an element of an Array shifts the
+// elements after it down, which counts as a change of the whole Array.
+// Deleting something that does not exist does nothing.
+func (d *Document) Delete(p Path) {
+	if _, ok := Lookup(d.root, p); !ok {
+		return
+	}
+	if len(p) == 0 {
+		d.root, d.node = nil, &cowNode{changed: true}
+		return
+	}
+	d.root = d.delete(d.node, d.root, p)
+}
+
+func (d *Document) delete(node *cowNode, cur Value, p Path) Value {
+	switch e := p[0].(type) {
+	case string:
+		s := cur.(Struct)
+		if !node.owned {
+			s = maps.Clone(s)
+			node.owned = true
+		}
+		if len(p) == 1 {
+			delete(s, e)
+			node.replaced(e)
+			return s
+		}
+		s[e] = d.delete(node.child(e), s[e], p[1:])
+		return s
+	default:
+		i := e.(int)
+		a := cur.(Array)
+		if !node.owned {
+			a = slices.Clone(a)
+			node.owned = true
+		}
+		if len(p) == 1 {
+			children := map[any]*cowNode{}
+			for k, c := range node.children {
+				if j := k.(int); j < i {
+					children[j] = c
+				} else if j > i {
+					children[j-1] = c
+				}
+			}
+			node.children = children
+			node.changed = true
+			return slices.Delete(a, i, i+1)
+		}
+		a[i] = d.delete(node.child(i), a[i], p[1:])
+		return a
+	}
+}
+
+// Dirty reports whether anything at or below p has been changed, including
+// when one of the Structs or Arrays containing p has been replaced.
+func (d *Document) Dirty(p Path) bool {
+	node := d.node
+	for _, e := range p {
+		if node.changed {
+			return true
+		}
+		node = node.children[e]
+		if node == nil {
+			return false
+		}
+	}
+	return node.dirty()
+}
+
+// Snapshot returns the current state of the document, which later changes do
+// not affect, for example to go back to with Restore if the changes that
+// follow do not validate. Like every change, it is cheap: the document only
+// copies what it changes next.
+func (d *Document) Snapshot() Immutable {
+	d.node.disown()
+	return Immutable{v: d.root}
+}
+
+// Restore puts the document back into the state of a snapshot. Afterwards,
+// the paths where the snapshot differs from the wrapped Value are dirty.
+func (d *Document) Restore(snap Immutable) {
+	d.root, d.node = snap.v, &cowNode{}
+	for _, c := range Diff(d.base, snap.v) {
+		node := d.node
+		for _, e := range c.Path {
+			node = node.child(e)
+		}
+		node.changed = true
+	}
+}
@@ -0,0 +1,83 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument(t *testing.T) {
+	base := Struct{
+		"db":    Struct{"host": String("a"), "ports": Array{Int(1), Int(2), Int(3)}},
+		"cache": Struct{"ttl": Int(60)},
+	}
+	orig := base.String()
+	d := NewDocument(base)
+	require.False(t, d.Dirty(Path{}))
+
+	require.NoError(t, d.Set(MustParsePath(".db.host"), String("b")))
+	require.NoError(t, d.Set(MustParsePath(".db.user"), String("root")))
+	require.NoError(t, d.Set(MustParsePath(".db.ports[3]"), Int(4)))
+	require.NoError(t, d.Set(MustParsePath(".new.nested"), Bool(true)))
+	d.Delete(MustParsePath(".db.ports[0]"))
+	d.Delete(MustParsePath(".missing"))
+	require.Equal(t, orig, base.String())
+	require.Equal(t, `{"cache":{"ttl":60},"db":{"host":"b","ports":[2,3,4],"user":"root"},"new":{"nested":true}}`, d.Value().String())
+
+	v, ok := d.Get(MustParsePath(".db.ports[2]"))
+	require.True(t, ok)
+	require.Equal(t, Int(4), v)
+
+	require.True(t, d.Dirty(Path{}))
+	require.True(t, d.Dirty(MustParsePath(".db")))
+	require.True(t, d.Dirty(MustParsePath(".db.ports[1]")))
+	require.True(t, d.Dirty(MustParsePath(".new.nested.deeper")))
+	require.False(t, d.Dirty(MustParsePath(".cache")))
+	require.False(t, d.Dirty(MustParsePath(".cache.ttl")))
+
+	// unchanged parts are shared, changed ones are copied only once
+	cache := d.Value().(Struct)["cache"].(Struct)
+	cache["probe"] = Bool(true)
+	require.Contains(t, base.String(), "probe")
+	delete(cache, "probe")
+	db := d.Value().(Struct)["db"].(Struct)
+	require.NoError(t, d.Set(MustParsePath(".db.host"), String("c")))
+	require.Equal(t, String("c"), db["host"])
+
+	// values that were set are not modified by later changes below them
+	item := Struct{"a": Int(1)}
+	require.NoError(t, d.Set(MustParsePath(".item"), item))
+	require.NoError(t, d.Set(MustParsePath(".item.b"), Int(2)))
+	require.Equal(t, Struct{"a": Int(1)}, item)
+
+	require.EqualError(t, d.Set(MustParsePath(".db.host.x"), Int(1)), "cannot set value at .db.host: string is not a struct")
+	require.EqualError(t, d.Set(MustParsePath(".db.ports[9]"), Int(1)), "cannot set value at .db.ports: index 9 is out of range")
+
+	d.Delete(Path{})
+	require.Nil(t, d.Value())
+	require.NoError(t, d.Set(Path{}, Array{}))
+	require.NoError(t, d.Set(MustParsePath("[0]"), Int(1)))
+	require.Equal(t, Array{Int(1)}, d.Value())
+}
+
+func TestDocumentSnapshot(t *testing.T) {
+	base := Struct{"a": Struct{"x": Int(1)}, "b": Struct{"y": Int(2)}}
+	d := NewDocument(base)
+	require.NoError(t, d.Set(MustParsePath(".a.x"), Int(10)))
+	snap := d.Snapshot()
+
+	require.NoError(t, d.Set(MustParsePath(".a.x"), Int(20)))
+	require.NoError(t, d.Set(MustParsePath(".b.y"), Int(30)))
+	d.Delete(MustParsePath(".a"))
+	require.Equal(t, `{"a":{"x":10},"b":{"y":2}}`, snap.String())
+	require.Equal(t, `{"b":{"y":30}}`, d.Value().String())
+
+	d.Restore(snap)
+	require.Equal(t, `{"a":{"x":10},"b":{"y":2}}`, d.Value().String())
+	require.True(t, d.Dirty(MustParsePath(".a.x")))
+	require.False(t, d.Dirty(MustParsePath(".b")))
+
+	require.NoError(t, d.Set(MustParsePath(".a.z"), Int(5)))
+	require.Equal(t, `{"a":{"x":10},"b":{"y":2}}`, snap.String())
+	require.Equal(t, `{"a":{"x":1},"b":{"y":2}}`, base.String())
+}
@@ -0,0 +1,199 @@
+package simple
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Encoder writes Values as JSON to an output stream, with more control over
+// the output than encoding/json allows.
+type Encoder struct {
+	w          io.Writer
+	escapeHTML bool
+	asciiOnly  bool
+	keyOrder   func(a, b string) int
+	nonFinite  NonFinitePolicy
+	empty      EmptyPolicy
+	prefix     string
+	indent     string
+	// lenient writes null for everything that cannot be encoded, instead of
+	// failing
+	lenient bool
+}
+
+// NewEncoder returns an Encoder that writes to w. Like encoding/json, it
+// escapes HTML characters by default.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, escapeHTML: true}
+}
+
+// SetEscapeHTML controls whether <, > and & (along with U+2028 and U+2029) are
+// escaped in strings so that the output is safe to embed in HTML. Turning this
+// off keeps URLs and templates readable in human facing output.
+func (e *Encoder) SetEscapeHTML(on bool) {
+	e.escapeHTML = on
+}
+
+// SetASCIIOnly controls whether every character outside of ASCII is written
+// as a \u escape sequence, for destinations that cannot handle UTF-8.
+func (e *Encoder) SetASCIIOnly(on bool) {
+	e.asciiOnly = on
+}
+
+// SetKeyOrder sets the comparison function used to order struct keys, with
+// the same contract as the one taken by [slices.SortFunc]. Output is always
+// deterministic: by default keys are sorted by their bytes, like
+// encoding/json does, and keys that cmp considers equal are ordered by their
+// bytes too. Passing nil restores the default.
+func (e *Encoder) SetKeyOrder(cmp func(a, b string) int) {
+	e.keyOrder = cmp
+}
+
+// EmptyPolicy decides how an [Encoder] writes nil and empty [Struct] and
+// [Array] values.
+type EmptyPolicy int
+
+const (
+	// NilAsEmpty writes nil composites as {} and []. It is the default, and
+	// means consumers never have to expect null in place of a container.
+	NilAsEmpty EmptyPolicy = iota
+	// NilAsNull writes nil composites as null and empty ones as {} and [],
+	// which is what encoding/json does.
+	NilAsNull
+	// EmptyAsNull writes both nil and empty composites as null.
+	EmptyAsNull
+)
+
+// SetEmptyPolicy sets how nil and empty composites are written.
+func (e *Encoder) SetEmptyPolicy(p EmptyPolicy) {
+	e.empty = p
+}
+
+// writesNull reports whether a composite should be written as null.
+func (e *Encoder) writesNull(isNil bool, n int) bool {
+	switch e.empty {
+	case NilAsNull:
+		return isNil
+	case EmptyAsNull:
+		return n == 0
+	}
+	return false
+}
+
+// sortKeys puts keys in the order the Encoder writes them in.
+func (e *Encoder) sortKeys(keys []string) {
+	slices.SortFunc(keys, func(a, b string) int {
+		if e.keyOrder != nil {
+			if c := e.keyOrder(a, b); c != 0 {
+				return c
+			}
+		}
+		return strings.Compare(a, b)
+	})
+}
+
+// Encode writes the JSON encoding of v to the stream, followed by a newline.
+func (e *Encoder) Encode(v Value) error {
+	var buf bytes.Buffer
+	if err := e.encode(&buf, Path{}, v); err != nil {
+		return err
+	}
+	if e.prefix != "" || e.indent != "" {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, buf.Bytes(), e.prefix, e.indent); err != nil {
+			return err
+		}
+		buf = indented
+	}
+	buf.WriteByte('\n')
+	_, err := e.w.Write(buf.Bytes())
+	return err
+}
+
+func (e *Encoder) encode(buf *bytes.Buffer, path Path, v Value) error {
+	switch tv := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case Bool:
+		if tv {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	case Number:
+		f := float64(tv)
+		if !isFinite(f) {
+			switch e.nonFinite {
+			case NonFiniteNull:
+				buf.WriteString("null")
+				return nil
+			case NonFiniteString:
+				buf.WriteString(`"` + nonFiniteName(f) + `"`)
+				return nil
+			}
+			return encodeError{path: path, problem: fmt.Sprintf("%v is not a valid JSON number", f)}
+		}
+		buf.WriteString(formatNumber(f))
+	case Int:
+		buf.WriteString(tv.String())
+	case RawNumber:
+		if !isJSONNumber(string(tv)) {
+			if e.lenient {
+				buf.WriteString("null")
+				return nil
+			}
+			return encodeError{path: path, problem: fmt.Sprintf("%q is not a valid JSON number", string(tv))}
+		}
+		buf.WriteString(string(tv))
+	case String:
+		writeJSONString(buf, string(tv), e.escapeHTML, e.asciiOnly)
+	case Array:
+		if e.writesNull(tv == nil, len(tv)) {
+			buf.WriteString("null")
+			return nil
+		}
+		buf.WriteByte('[')
+		for i, ev := range tv {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := e.encode(buf, path.Index(i), ev); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case Struct:
+		if e.writesNull(tv == nil, len(tv)) {
+			buf.WriteString("null")
+			return nil
+		}
+		keys := make([]string, 0, len(tv))
+		for k := range tv {
+			keys = append(keys, k)
+		}
+		e.sortKeys(keys)
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONString(buf, k, e.escapeHTML, e.asciiOnly)
+			buf.WriteByte(':')
+			if err := e.encode(buf, path.Key(k), tv[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		if e.lenient {
+			buf.WriteString("null")
+			return nil
+		}
+		return encodeError{path: path, problem: fmt.Sprintf("unsupported type %T", v), err: ErrUnsupportedKind}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package simple
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncoder(t *testing.T) {
+	v := Struct{
+		"url":   String("https://example.com/?a=1&b=<2>"),
+		"name":  String("Zoë 😀"),
+		"count": Number(3),
+		"list":  Array{Bool(true), nil, Number(0.5)},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, NewEncoder(&buf).Encode(v))
+	// the defaults match encoding/json
+	jb, err := json.Marshal(ToAny(v))
+	require.NoError(t, err)
+	require.Equal(t, string(jb)+"\n", buf.String())
+
+	buf.Reset()
+	enc := NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	require.NoError(t, enc.Encode(v))
+	require.Equal(t, `{"count":3,"list":[true,null,0.5],"name":"Zoë 😀","url":"https://example.com/?a=1&b=<2>"}`+"\n", buf.String())
+
+	buf.Reset()
+	enc.SetASCIIOnly(true)
+	require.NoError(t, enc.Encode(v["name"]))
+	require.Equal(t, `"Zo\u00eb \ud83d\ude00"`+"\n", buf.String())
+
+	buf.Reset()
+	require.EqualError(t, enc.Encode(Struct{"n": Number(math.Inf(-1))}), `cannot encode value at .n: -Inf is not a valid JSON number`)
+	require.Empty(t, buf.String())
+}
+
+func TestEncoderKeyOrder(t *testing.T) {
+	v := Struct{"b": Number(1), "a": Number(2), "id": Number(3), "C": Struct{"y": nil, "x": nil}}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	require.NoError(t, enc.Encode(v))
+	require.Equal(t, `{"C":{"x":null,"y":null},"a":2,"b":1,"id":3}`+"\n", buf.String())
+	require.Equal(t, v.String()+"\n", buf.String())
+
+	// "id" first, then everything else reverse sorted
+	enc.SetKeyOrder(func(a, b string) int {
+		switch {
+		case a == "id":
+			return -1
+		case b == "id":
+			return 1
+		}
+		return strings.Compare(b, a)
+	})
+	buf.Reset()
+	require.NoError(t, enc.Encode(v))
+	require.Equal(t, `{"id":3,"b":1,"a":2,"C":{"y":null,"x":null}}`+"\n", buf.String())
+
+	// ties fall back to byte order
+	enc.SetKeyOrder(func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	buf.Reset()
+	require.NoError(t, enc.Encode(Struct{"b": nil, "B": nil, "a": nil}))
+	require.Equal(t, `{"a":null,"B":null,"b":null}`+"\n", buf.String())
+}
+
+func TestEncoderEmptyPolicy(t *testing.T) {
+	v := Struct{"nils": Array{Struct(nil), Array(nil)}, "empty": Array{NewStruct(), NewArray()}}
+	for _, tc := range []struct {
+		policy EmptyPolicy
+		output string
+	}{
+		{policy: NilAsEmpty, output: `{"empty":[{},[]],"nils":[{},[]]}`},
+		{policy: NilAsNull, output: `{"empty":[{},[]],"nils":[null,null]}`},
+		{policy: EmptyAsNull, output: `{"empty":[null,null],"nils":[null,null]}`},
+	} {
+		var buf bytes.Buffer
+		enc := NewEncoder(&buf)
+		enc.SetEmptyPolicy(tc.policy)
+		require.NoError(t, enc.Encode(v))
+		require.Equal(t, tc.output+"\n", buf.String())
+	}
+
+	// encoding/json agrees with NilAsNull
+	require.Equal(t, `{"empty":[{},[]],"nils":[null,null]}`, v.String())
+	require.Equal(t, Array{Number(1)}, NewArray(Number(1)))
+}
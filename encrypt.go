@@ -0,0 +1,124 @@
+package simple
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// EncryptedKey is the only key of the [Struct] envelopes that [Encryptor]
+// puts in place of encrypted values.
+const EncryptedKey = "$encrypted"
+
+// Encryptor encrypts selected parts of values, so that sensitive fields can be
+// stored encrypted at rest while the rest of the document stays queryable.
+//
+// Each matched value (which may be a whole subtree) is serialized with
+// [CanonicalJSON], sealed with the AEAD and replaced by an envelope of the
+// form {"$encrypted": "<base64url nonce and ciphertext>"}. The path of the
+// value is used as additional data, so an envelope cannot be moved to another
+// location in the document without failing to decrypt.
+type Encryptor struct {
+	// AEAD seals and opens values, e.g. AES-GCM from crypto/cipher.
+	AEAD cipher.AEAD
+
+	// Paths are path globs, like those of [Redactor], selecting what to
+	// encrypt. Nulls are never encrypted.
+	Paths []string
+}
+
+type encryptError struct {
+	path    Path
+	problem string
+}
+
+func (e encryptError) Error() string {
+	return fmt.Sprintf("cannot decrypt value at %s: %s", e.path, e.problem)
+}
+
+func (e encryptError) Pointer() string { return e.path.Pointer() }
+
+// Encrypt returns a copy of v where every value matched by e.Paths has been
+// replaced with an encrypted envelope.
+func (e Encryptor) Encrypt(v Value) (Value, error) {
+	patterns := make([]pathPattern, len(e.Paths))
+	for i, p := range e.Paths {
+		pp, err := parsePathPattern(p)
+		if err != nil {
+			return nil, err
+		}
+		patterns[i] = pp
+	}
+	return e.encrypt(Path{}, v, patterns)
+}
+
+func (e Encryptor) encrypt(path Path, v Value, patterns []pathPattern) (Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	for _, pp := range patterns {
+		if pp.Match(path) {
+			plaintext, err := CanonicalJSON(v)
+			if err != nil {
+				return nil, err
+			}
+			nonce := make([]byte, e.AEAD.NonceSize(), e.AEAD.NonceSize()+len(plaintext)+e.AEAD.Overhead())
+			if _, err := rand.Read(nonce); err != nil {
+				return nil, err
+			}
+			sealed := e.AEAD.Seal(nonce, nonce, plaintext, []byte(path.String()))
+			return Struct{EncryptedKey: String(base64.RawURLEncoding.EncodeToString(sealed))}, nil
+		}
+	}
+	switch tv := v.(type) {
+	case Struct:
+		out := make(Struct, len(tv))
+		for k, ev := range tv {
+			nv, err := e.encrypt(path.Key(k), ev, patterns)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = nv
+		}
+		return out, nil
+	case Array:
+		out := make(Array, len(tv))
+		for i, ev := range tv {
+			nv, err := e.encrypt(path.Index(i), ev, patterns)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = nv
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// Decrypt returns a copy of v where every encrypted envelope has been
+// replaced by the value it holds. Envelopes are found wherever they are, so
+// e.Paths is not needed to decrypt.
+func (e Encryptor) Decrypt(v Value) (Value, error) {
+	return Transform(v, func(path Path, v Value) (Value, error) {
+		s, ok := v.(Struct)
+		if !ok || len(s) != 1 {
+			return v, nil
+		}
+		encoded, ok := s[EncryptedKey].(String)
+		if !ok {
+			return v, nil
+		}
+		sealed, err := base64.RawURLEncoding.DecodeString(string(encoded))
+		if err != nil || len(sealed) < e.AEAD.NonceSize() {
+			return nil, encryptError{path: path, problem: "malformed envelope"}
+		}
+		nonce, ciphertext := sealed[:e.AEAD.NonceSize()], sealed[e.AEAD.NonceSize():]
+		plaintext, err := e.AEAD.Open(nil, nonce, ciphertext, []byte(path.String()))
+		if err != nil {
+			return nil, encryptError{path: path, problem: err.Error()}
+		}
+		return FromJSON(json.RawMessage(plaintext))
+	})
+}
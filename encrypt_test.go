@@ -0,0 +1,43 @@
+package simple
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptor(t *testing.T) {
+	block, err := aes.NewCipher(make([]byte, 32))
+	require.NoError(t, err)
+	aead, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+
+	e := Encryptor{AEAD: aead, Paths: []string{"users[*].ssn", "**.card"}}
+	v := mustFromJSON(t, `{
+		"users": [
+			{"name": "ann", "ssn": "123-45-6789", "billing": {"card": {"number": "4111", "exp": "01/30"}}},
+			{"name": "bob", "ssn": null}
+		]
+	}`)
+
+	encrypted, err := e.Encrypt(v)
+	require.NoError(t, err)
+	ann := encrypted.(Struct)["users"].(Array)[0].(Struct)
+	require.Equal(t, String("ann"), ann["name"])
+	require.Contains(t, ann["ssn"], EncryptedKey)
+	require.Contains(t, ann["billing"].(Struct)["card"], EncryptedKey)
+	require.Nil(t, encrypted.(Struct)["users"].(Array)[1].(Struct)["ssn"])
+	require.NotContains(t, encrypted.String(), "4111")
+
+	decrypted, err := e.Decrypt(encrypted)
+	require.NoError(t, err)
+	require.Equal(t, v, decrypted)
+
+	// moving an envelope around is detected
+	users := encrypted.(Struct)["users"].(Array)
+	users[1].(Struct)["ssn"] = users[0].(Struct)["ssn"]
+	_, err = e.Decrypt(encrypted)
+	require.EqualError(t, err, `cannot decrypt value at .users[1].ssn: cipher: message authentication failed`)
+}
@@ -0,0 +1,50 @@
+package simple
+
+import (
+	"os"
+	"slices"
+	"strings"
+)
+
+// FromEnviron collects the environment variables that start with prefix into
+// a [Struct], for configuration that comes from the environment. The prefix is
+// removed, the rest of the name is lowercased and split on sep into nested
+// keys, and the values are converted to a [Bool] or [Number] where they look
+// like one. So with a prefix of "APP_" and a sep of "__":
+//
+//	APP_DB__HOST=localhost
+//	APP_DB__PORT=5432
+//	APP_HOSTS__0=a
+//	APP_HOSTS__1=b
+//
+// becomes {"db":{"host":"localhost","port":5432},"hosts":["a","b"]}. Keys that
+// are numbers make arrays, the same way they do for [FromURLValues]. An empty
+// sep does not nest anything. Conflicting variables like APP_DB=x are applied
+// in sorted order, so the nested ones win.
+func FromEnviron(prefix, sep string) Struct {
+	return fromEnviron(os.Environ(), prefix, sep)
+}
+
+func fromEnviron(environ []string, prefix, sep string) Struct {
+	vars := map[string]string{}
+	for _, kv := range environ {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(k, prefix) || k == prefix {
+			continue
+		}
+		vars[strings.ToLower(strings.TrimPrefix(k, prefix))] = v
+	}
+	out := Struct{}
+	for _, k := range sortedKeysOf(vars) {
+		segs := []string{k}
+		if sep != "" {
+			segs = strings.Split(k, strings.ToLower(sep))
+		}
+		if slices.Contains(segs, "") {
+			// APP__X or APP_X__ cannot be placed anywhere sensible
+			continue
+		}
+		out[segs[0]] = insertURLValue(out[segs[0]], segs[1:], Array{inferScalar(vars[k])})
+	}
+	return out
+}
@@ -0,0 +1,25 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromEnviron(t *testing.T) {
+	t.Setenv("SIMPLETEST_DB__HOST", "localhost")
+	t.Setenv("SIMPLETEST_DB__PORT", "5432")
+	t.Setenv("SIMPLETEST_DEBUG", "TRUE")
+	t.Setenv("SIMPLETEST_HOSTS__1", "b")
+	t.Setenv("SIMPLETEST_HOSTS__0", "a")
+	t.Setenv("SIMPLETEST_ZIP", "02134")
+	t.Setenv("SIMPLETEST_BAD__", "x")
+
+	require.Equal(t, `{"db":{"host":"localhost","port":5432},"debug":true,"hosts":["a","b"],"zip":"02134"}`, FromEnviron("SIMPLETEST_", "__").String())
+	require.Equal(t, Struct{"host": String("localhost"), "port": Number(5432)}, FromEnviron("SIMPLETEST_DB__", ""))
+}
+
+func TestFromEnvironConflicts(t *testing.T) {
+	v := fromEnviron([]string{"A_X=1", "A_X_Y=2", "B_X=3", "A_=4", "NOEQUALS"}, "A_", "_")
+	require.Equal(t, Struct{"x": Struct{"y": Number(2)}}, v)
+}
@@ -0,0 +1,48 @@
+package simple
+
+// equal reports whether a and b represent the same value. Unlike
+// reflect.DeepEqual, a nil Struct is equal to an empty one, and the same goes
+// for Arrays. A [RawNumber] or an [Int] is equal to the [Number]
+// it represents.
+func equal(a, b Value) bool {
+	switch ta := a.(type) {
+	case nil:
+		return b == nil
+	case Struct:
+		tb, ok := b.(Struct)
+		if !ok || len(ta) != len(tb) {
+			return false
+		}
+		for k, av := range ta {
+			bv, ok := tb[k]
+			if !ok || !equal(av, bv) {
+				return false
+			}
+		}
+		return true
+	case Array:
+		tb, ok := b.(Array)
+		if !ok || len(ta) != len(tb) {
+			return false
+		}
+		for i := range ta {
+			if !equal(ta[i], tb[i]) {
+				return false
+			}
+		}
+		return true
+	case Number, RawNumber, Int:
+		ia, aInt := a.(Int)
+		ib, bInt := b.(Int)
+		if aInt && bInt {
+			// float64 would round large values
+			return ia == ib
+		}
+		fa, okA := numberValue(a)
+		fb, okB := numberValue(b)
+		if okA && okB {
+			return fa == fb
+		}
+	}
+	return a == b
+}
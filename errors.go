@@ -0,0 +1,36 @@
+package simple
+
+import "errors"
+
+// Errors that failures of this package can be matched against with
+// [errors.Is], to branch on the kind of failure instead of on messages.
+var (
+	// ErrUnsupportedKind means a Go value or type cannot be converted to or
+	// from a [Value], like a channel or a func.
+	ErrUnsupportedKind = errors.New("unsupported kind")
+	// ErrUnstringifiableMapKey means the keys of a Go map cannot be converted
+	// to or from the string keys of a [Struct].
+	ErrUnstringifiableMapKey = errors.New("map key cannot be stringified")
+	// ErrTooDeep means a Value is nested deeper than allowed, see [MaxDepth]
+	// and [ConvertMaxDepth].
+	ErrTooDeep = errors.New("value is too deep")
+)
+
+// ErrorPointer returns where in the value the first error in the chain of err
+// that knows its location happened, as a JSON Pointer (RFC 6901) like
+// "/users/2/name". This is what tools should use to point at the offending
+// field, instead of picking apart error messages. The location of an error
+// at the root is "", and ok is false if no error in the chain has a location.
+//
+// Every error of this package and its subpackages that names a path in its
+// message has a location, like the errors of [FromValue], [Decode], [Encoder],
+// [ToStringMap] and [ApplyPatch], a [LimitError] or a [ValidationError]. An
+// error of [Bind] or [Extract] is located where its pattern has its first
+// wildcard, or at the whole path if it has none.
+func ErrorPointer(err error) (pointer string, ok bool) {
+	var located interface{ Pointer() string }
+	if !errors.As(err, &located) {
+		return "", false
+	}
+	return located.Pointer(), true
+}
@@ -0,0 +1,143 @@
+package simple
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	_, err := FromValue(map[string]any{"c": make(chan int)})
+	require.ErrorIs(t, err, ErrUnsupportedKind)
+	require.EqualError(t, err, "cannot convert value at .c: cannot convert value of kind chan to simple value")
+
+	_, err = FromValue(map[[2]int]string{{1, 2}: "x"})
+	require.ErrorIs(t, err, ErrUnstringifiableMapKey)
+
+	var ch chan int
+	require.ErrorIs(t, Decode(String("x"), &ch), ErrUnsupportedKind)
+	var m map[[2]int]string
+	err = Decode(Struct{"k": String("x")}, &m)
+	require.ErrorIs(t, err, ErrUnstringifiableMapKey)
+	require.EqualError(t, err, "cannot decode value at .k: map key type [2]int is not supported")
+
+	_, err = FromJSON([]byte(`[[[1]]]`), MaxDepth(2))
+	require.ErrorIs(t, err, ErrTooDeep)
+	var le *LimitError
+	require.True(t, errors.As(err, &le))
+	_, err = FromJSON([]byte(`[1,2,3]`), MaxNodes(2))
+	require.False(t, errors.Is(err, ErrTooDeep))
+
+	require.False(t, errors.Is(Decode(Int(1), new(string)), ErrUnsupportedKind))
+}
+
+func TestErrorPointer(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		err     error
+		pointer string
+	}{
+		{
+			name: "FromValue",
+			err: func() error {
+				_, err := FromValue(map[string]any{"a/b": []any{1, make(chan int)}, ".": 1})
+				return err
+			}(),
+			pointer: "/a~1b/1",
+		},
+		{
+			name: "FromValue key",
+			err: func() error {
+				_, err := FromValue(map[string]any{".": []any{func() {}}})
+				return err
+			}(),
+			pointer: "/./0",
+		},
+		{
+			name: "FromJSON",
+			err: func() error {
+				_, err := FromJSON([]byte(`{"a": [[1]]}`), MaxDepth(2))
+				return err
+			}(),
+			pointer: "/a/0",
+		},
+		{
+			name:    "Decode",
+			err:     Decode(Struct{"n": String("x")}, &struct{ N int }{}),
+			pointer: "/n",
+		},
+		{
+			name: "ToHTTPHeader",
+			err: func() error {
+				_, err := ToHTTPHeader(Struct{"x": Array{String("a"), Struct{}}})
+				return err
+			}(),
+			pointer: "/x/1",
+		},
+		{
+			name:    "WriteCSV",
+			err:     WriteCSV(io.Discard, Array{Struct{}, Number(1)}),
+			pointer: "/1",
+		},
+		{
+			name: "ToStringMap",
+			err: func() error {
+				_, err := ToStringMap(Struct{"a": Struct{"b": Number(math.NaN())}})
+				return err
+			}(),
+			pointer: "/a/b",
+		},
+		{
+			name: "ToStringMap collision",
+			err: func() error {
+				_, err := ToStringMap(Struct{"a": Struct{"b": Number(1)}, "a.b": Number(2)})
+				return err
+			}(),
+			pointer: "/a.b",
+		},
+		{
+			name: "ToURLValues",
+			err: func() error {
+				_, err := ToURLValues(Struct{"a": Array{Struct{"b": Number(math.Inf(1))}}})
+				return err
+			}(),
+			pointer: "/a/0/b",
+		},
+		{
+			name: "Bind",
+			err: Bind(Struct{"items": Array{}}, &struct {
+				Name string `simple:"bind=meta.name"`
+			}{}),
+			pointer: "/meta/name",
+		},
+		{
+			name: "Bind wildcard",
+			err: Bind(Struct{"items": Array{Struct{"n": String("x")}}}, &struct {
+				N []int `simple:"bind=items[*].n"`
+			}{}),
+			pointer: "/items",
+		},
+		{
+			name: "wrapped",
+			err: func() error {
+				_, err := NewImmutable(Struct{"x": String("s")}).Set(Path{"x", 0}, nil)
+				return fmt.Errorf("loading config: %w", err)
+			}(),
+			pointer: "/x",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Error(t, tc.err)
+			got, ok := ErrorPointer(tc.err)
+			require.True(t, ok)
+			require.Equal(t, tc.pointer, got)
+		})
+	}
+
+	_, ok := ErrorPointer(errors.New("no location"))
+	require.False(t, ok)
+}
@@ -0,0 +1,138 @@
+package simple
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// EventLogOption changes the behavior of an [EventLog].
+type EventLogOption func(*EventLog)
+
+// SnapshotEvery makes an [EventLog] take a snapshot after every n patches, so
+// that [EventLog.At] never has to replay more than n-1 of them.
+func SnapshotEvery(n int) EventLogOption {
+	return func(l *EventLog) { l.snapshotEvery = n }
+}
+
+// EventLog maintains a Value as the fold of an append-only sequence of
+// [Patch]es over an initial Value, for stores of schema-less records that
+// need a complete audit trail. Every patch gets a sequence number, starting
+// at 1, and the state after any of them can be rebuilt by replaying the log.
+// Snapshots of the state are kept along the way to make that cheap. Like
+// [Immutable]s, they share everything that did not change between them.
+//
+// An EventLog is not safe for concurrent use.
+type EventLog struct {
+	patches       []Patch
+	snapshots     []eventSnapshot // ordered by seq
+	current       Immutable
+	snapshotEvery int
+}
+
+type eventSnapshot struct {
+	seq   int
+	state Immutable
+}
+
+// NewEventLog returns an EventLog without any patches, whose state is a deep
+// copy of initial.
+func NewEventLog(initial Value, opts ...EventLogOption) *EventLog {
+	l := &EventLog{current: NewImmutable(initial)}
+	for _, o := range opts {
+		o(l)
+	}
+	l.snapshots = []eventSnapshot{{seq: 0, state: l.current}}
+	return l
+}
+
+// Replay returns an EventLog that starts at initial and has patches applied,
+// for example to load a log that was stored elsewhere.
+func Replay(initial Value, patches []Patch, opts ...EventLogOption) (*EventLog, error) {
+	l := NewEventLog(initial, opts...)
+	if err := l.Append(patches...); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Append applies patches to the current state with [ApplyPatch] and adds
+// them to the end of the log. Either all of them are applied and logged, or,
+// if one of them fails, none of them are and the error is returned. The
+// paths and values of the patches are copied, so they can be changed
+// afterwards.
+func (l *EventLog) Append(patches ...Patch) error {
+	logged := make([]Patch, len(patches))
+	for i, p := range patches {
+		logged[i] = copyPatch(p)
+	}
+	var snapshots []eventSnapshot
+	state, err := foldPatches(l.current.v, logged, func(i int, state Value) {
+		if seq := len(l.patches) + i + 1; l.snapshotEvery > 0 && seq%l.snapshotEvery == 0 {
+			snapshots = append(snapshots, eventSnapshot{seq: seq, state: Immutable{v: state}})
+		}
+	})
+	if err != nil {
+		return err
+	}
+	l.patches = append(l.patches, logged...)
+	l.snapshots = append(l.snapshots, snapshots...)
+	l.current = Immutable{v: state}
+	return nil
+}
+
+// Len returns the number of patches in the log, which is also the sequence
+// number of the last one.
+func (l *EventLog) Len() int {
+	return len(l.patches)
+}
+
+// State returns the current state, after all patches.
+func (l *EventLog) State() Immutable {
+	return l.current
+}
+
+// Patches returns the patches that come after the sequence number seq, so
+// Patches(0) returns the whole log. The paths of the patches are copies, but
+// their values must not be modified.
+func (l *EventLog) Patches(seq int) []Patch {
+	seq = max(0, min(seq, len(l.patches)))
+	out := make([]Patch, 0, len(l.patches)-seq)
+	for _, p := range l.patches[seq:] {
+		p.Path = slices.Clone(p.Path)
+		p.From = slices.Clone(p.From)
+		out = append(out, p)
+	}
+	return out
+}
+
+// copyPatch returns a copy of p that shares nothing with it.
+func copyPatch(p Patch) Patch {
+	p.Path = slices.Clone(p.Path)
+	p.From = slices.Clone(p.From)
+	p.Value = Clone(p.Value)
+	return p
+}
+
+// Snapshot keeps the current state as a snapshot, so that [EventLog.At] does
+// not need to replay the patches before it.
+func (l *EventLog) Snapshot() {
+	if last := l.snapshots[len(l.snapshots)-1]; last.seq != len(l.patches) {
+		l.snapshots = append(l.snapshots, eventSnapshot{seq: len(l.patches), state: l.current})
+	}
+}
+
+// At returns the state after the patch with sequence number seq, where 0 is
+// the initial state. It replays the patches after the closest snapshot.
+func (l *EventLog) At(seq int) (Immutable, error) {
+	if seq < 0 || seq > len(l.patches) {
+		return Immutable{}, fmt.Errorf("sequence number %d is out of range [0, %d]", seq, len(l.patches))
+	}
+	i := sort.Search(len(l.snapshots), func(i int) bool { return l.snapshots[i].seq > seq }) - 1
+	snap := l.snapshots[i]
+	v, err := foldPatches(snap.state.v, l.patches[snap.seq:seq], nil)
+	if err != nil {
+		return Immutable{}, fmt.Errorf("cannot replay event log up to %d: %w", seq, err)
+	}
+	return Immutable{v: v}, nil
+}
@@ -0,0 +1,79 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventLog(t *testing.T) {
+	l := NewEventLog(Struct{}, SnapshotEvery(2))
+	counter := MustParsePath(".n")
+	require.NoError(t, l.Append(Patch{Op: PatchAdd, Path: counter, Value: Int(0)}))
+	for i := 1; i <= 4; i++ {
+		require.NoError(t, l.Append(Patch{Op: PatchReplace, Path: counter, Value: Int(i)}))
+	}
+	require.Equal(t, 5, l.Len())
+	require.Equal(t, `{"n":4}`, l.State().String())
+	require.Equal(t, []int{0, 2, 4}, snapshotSeqs(l))
+
+	err := l.Append(
+		Patch{Op: PatchAdd, Path: MustParsePath(".m"), Value: Int(1)},
+		Patch{Op: PatchRemove, Path: MustParsePath(".missing")},
+	)
+	require.EqualError(t, err, "cannot apply patch 1 (remove at .missing): path does not exist")
+	require.Equal(t, 5, l.Len())
+	require.Equal(t, `{"n":4}`, l.State().String())
+
+	for seq, want := range []string{`{}`, `{"n":0}`, `{"n":1}`, `{"n":2}`, `{"n":3}`, `{"n":4}`} {
+		state, err := l.At(seq)
+		require.NoError(t, err)
+		require.Equal(t, want, state.String())
+	}
+	_, err = l.At(6)
+	require.EqualError(t, err, "sequence number 6 is out of range [0, 5]")
+
+	l.Snapshot()
+	l.Snapshot()
+	require.Equal(t, []int{0, 2, 4, 5}, snapshotSeqs(l))
+
+	replayed, err := Replay(Struct{}, l.Patches(0))
+	require.NoError(t, err)
+	require.Equal(t, l.State(), replayed.State())
+	require.Len(t, l.Patches(3), 2)
+
+	v := Struct{"x": Int(1)}
+	require.NoError(t, l.Append(Patch{Op: PatchAdd, Path: MustParsePath(".v"), Value: v}))
+	v["x"] = Int(2)
+	require.Equal(t, `{"n":4,"v":{"x":1}}`, l.State().String())
+}
+
+func TestEventLogCopiesPatches(t *testing.T) {
+	l := NewEventLog(Struct{})
+	p := Path{"a"}
+	from := Path{"a"}
+	require.NoError(t, l.Append(
+		Patch{Op: PatchAdd, Path: p, Value: Int(1)},
+		Patch{Op: PatchCopy, From: from, Path: Path{"b"}},
+	))
+	p[0] = "missing"
+	from[0] = "missing"
+
+	state, err := l.At(2)
+	require.NoError(t, err)
+	require.Equal(t, `{"a":1,"b":1}`, state.String())
+
+	patches := l.Patches(0)
+	patches[0].Path[0] = "missing"
+	patches[1].From[0] = "missing"
+	require.Equal(t, Path{"a"}, l.Patches(0)[0].Path)
+	require.Equal(t, Path{"a"}, l.Patches(0)[1].From)
+}
+
+func snapshotSeqs(l *EventLog) []int {
+	var seqs []int
+	for _, s := range l.snapshots {
+		seqs = append(seqs, s.seq)
+	}
+	return seqs
+}
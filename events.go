@@ -0,0 +1,344 @@
+package simple
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// EventHandler receives a value as a sequence of events, SAX style. Objects
+// are reported as BeginObject, then Key followed by the events of the value
+// for every entry, and finally EndObject. Arrays are reported the same way,
+// without keys. Everything else is reported with Scalar, which receives a
+// [Number], [RawNumber], [Int], [String], [Bool] or nil.
+//
+// Returning an error from any method stops the stream and the error is
+// returned to the caller.
+type EventHandler interface {
+	BeginObject() error
+	Key(k string) error
+	EndObject() error
+	BeginArray() error
+	EndArray() error
+	Scalar(v Value) error
+}
+
+// Emit reports v to h as a sequence of events. Struct keys are reported in
+// sorted order.
+func Emit(v Value, h EventHandler) error {
+	switch tv := v.(type) {
+	case Struct:
+		if err := h.BeginObject(); err != nil {
+			return err
+		}
+		for _, k := range sortedKeys(tv) {
+			if err := h.Key(k); err != nil {
+				return err
+			}
+			if err := Emit(tv[k], h); err != nil {
+				return err
+			}
+		}
+		return h.EndObject()
+	case Array:
+		if err := h.BeginArray(); err != nil {
+			return err
+		}
+		for _, ev := range tv {
+			if err := Emit(ev, h); err != nil {
+				return err
+			}
+		}
+		return h.EndArray()
+	}
+	return h.Scalar(v)
+}
+
+// DecodeEvents reads the next JSON value from the stream and reports it to h
+// as a sequence of events, without ever holding the whole value in memory.
+// All of the options of the JSONDecoder apply, except for the ones about
+// duplicate keys. It returns io.EOF when there is nothing left.
+func (d *JSONDecoder) DecodeEvents(h EventHandler) error {
+	if !d.dec.More() {
+		if _, err := d.dec.Token(); err != nil {
+			return err
+		}
+		return fmt.Errorf("unexpected data at offset %d", d.dec.InputOffset())
+	}
+	d.b.nodes = 0
+	if err := d.b.emit(d.dec, Path{}, h); err != nil {
+		return err
+	}
+	if d.b.strict {
+		return checkTrailing(d.dec)
+	}
+	return nil
+}
+
+func (b *jsonBuilder) emit(dec *json.Decoder, path Path, h EventHandler) error {
+	tok, err := dec.Token()
+	if err != nil {
+		if err == io.EOF && len(path) > 0 {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if err := b.node(path, tok); err != nil {
+		return err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		v, err := b.scalar(path, tok)
+		if err != nil {
+			return err
+		}
+		return h.Scalar(v)
+	}
+	switch delim {
+	case '{':
+		if err := h.BeginObject(); err != nil {
+			return err
+		}
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, ok := tok.(string)
+			if !ok {
+				return fmt.Errorf("unexpected token %v at offset %d", tok, dec.InputOffset())
+			}
+			if b.maxString > 0 && len(key) > b.maxString {
+				return &LimitError{Limit: "string length", Max: int64(b.maxString), Path: path.Key(key[:b.maxString] + "...")}
+			}
+			if err := h.Key(key); err != nil {
+				return err
+			}
+			if err := b.emit(dec, path.Key(key), h); err != nil {
+				return err
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+		return h.EndObject()
+	case '[':
+		if err := h.BeginArray(); err != nil {
+			return err
+		}
+		for i := 0; dec.More(); i++ {
+			if err := b.emit(dec, path.Index(i), h); err != nil {
+				return err
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+		return h.EndArray()
+	}
+	return fmt.Errorf("unexpected %q at offset %d", delim, dec.InputOffset())
+}
+
+var errUnbalancedEvents = errors.New("unbalanced events")
+
+// ValueBuilder is an [EventHandler] that builds a [Value] out of the events it
+// receives.
+type ValueBuilder struct {
+	stack []Value
+	keys  []string
+	// pending is set between a Key event and the value that follows it
+	pending bool
+	done    bool
+	value   Value
+}
+
+// Value returns the value that was built. It fails if the events did not
+// describe a complete value.
+func (b *ValueBuilder) Value() (Value, error) {
+	if !b.done {
+		return nil, fmt.Errorf("incomplete value: %w", errUnbalancedEvents)
+	}
+	return b.value, nil
+}
+
+func (b *ValueBuilder) add(v Value) error {
+	if len(b.stack) == 0 {
+		if b.done {
+			return fmt.Errorf("more than one value: %w", errUnbalancedEvents)
+		}
+		b.value, b.done = v, true
+		return nil
+	}
+	switch parent := b.stack[len(b.stack)-1].(type) {
+	case Struct:
+		if !b.pending {
+			return fmt.Errorf("value without a key: %w", errUnbalancedEvents)
+		}
+		parent[b.keys[len(b.keys)-1]] = v
+		b.keys = b.keys[:len(b.keys)-1]
+		b.pending = false
+	case Array:
+		b.stack[len(b.stack)-1] = append(parent, v)
+	}
+	return nil
+}
+
+func (b *ValueBuilder) begin(v Value) error {
+	if b.done {
+		return fmt.Errorf("more than one value: %w", errUnbalancedEvents)
+	}
+	if _, ok := b.top().(Struct); ok && !b.pending {
+		return fmt.Errorf("value without a key: %w", errUnbalancedEvents)
+	}
+	b.stack = append(b.stack, v)
+	b.pending = false
+	return nil
+}
+
+func (b *ValueBuilder) end(isStruct bool) error {
+	v := b.top()
+	if _, ok := v.(Struct); len(b.stack) == 0 || ok != isStruct || b.pending {
+		return fmt.Errorf("unexpected end: %w", errUnbalancedEvents)
+	}
+	b.stack = b.stack[:len(b.stack)-1]
+	// the key of the composite itself was remembered when it began
+	_, b.pending = b.top().(Struct)
+	return b.add(v)
+}
+
+func (b *ValueBuilder) top() Value {
+	if len(b.stack) == 0 {
+		return nil
+	}
+	return b.stack[len(b.stack)-1]
+}
+
+// BeginObject implements [EventHandler].
+func (b *ValueBuilder) BeginObject() error { return b.begin(Struct{}) }
+
+// EndObject implements [EventHandler].
+func (b *ValueBuilder) EndObject() error { return b.end(true) }
+
+// BeginArray implements [EventHandler].
+func (b *ValueBuilder) BeginArray() error { return b.begin(Array{}) }
+
+// EndArray implements [EventHandler].
+func (b *ValueBuilder) EndArray() error { return b.end(false) }
+
+// Key implements [EventHandler].
+func (b *ValueBuilder) Key(k string) error {
+	if _, ok := b.top().(Struct); !ok || b.pending {
+		return fmt.Errorf("unexpected key %q: %w", k, errUnbalancedEvents)
+	}
+	b.keys = append(b.keys, k)
+	b.pending = true
+	return nil
+}
+
+// Scalar implements [EventHandler].
+func (b *ValueBuilder) Scalar(v Value) error {
+	switch v.(type) {
+	case Struct, Array:
+		return fmt.Errorf("%s passed as a scalar: %w", kindName(v), errUnbalancedEvents)
+	}
+	return b.add(v)
+}
+
+// EventWriter is an [EventHandler] that writes the events it receives as
+// compact JSON. Together with [JSONDecoder.DecodeEvents] it allows for
+// streaming filters that never hold the whole document, by wrapping an
+// EventWriter in a handler that drops or changes events.
+//
+// Writes are not buffered, so w should usually be a [bufio.Writer].
+type EventWriter struct {
+	w   io.Writer
+	buf bytes.Buffer
+	// first tracks, for every open composite, whether nothing was written to
+	// it yet
+	first []bool
+	// afterKey is set when a key was just written
+	afterKey bool
+}
+
+// NewEventWriter returns an EventWriter that writes to w. Strings are escaped
+// the same way a default [Encoder] would.
+func NewEventWriter(w io.Writer) *EventWriter {
+	return &EventWriter{w: w}
+}
+
+// separate writes the comma that separates values, if one is needed.
+func (e *EventWriter) separate() {
+	if e.afterKey {
+		e.afterKey = false
+		return
+	}
+	if n := len(e.first); n > 0 {
+		if !e.first[n-1] {
+			e.buf.WriteByte(',')
+		}
+		e.first[n-1] = false
+	}
+}
+
+func (e *EventWriter) flush() error {
+	_, err := e.w.Write(e.buf.Bytes())
+	e.buf.Reset()
+	return err
+}
+
+func (e *EventWriter) begin(c byte) error {
+	e.separate()
+	e.buf.WriteByte(c)
+	e.first = append(e.first, true)
+	return e.flush()
+}
+
+func (e *EventWriter) end(c byte) error {
+	if len(e.first) == 0 {
+		return fmt.Errorf("unexpected end: %w", errUnbalancedEvents)
+	}
+	e.first = e.first[:len(e.first)-1]
+	e.buf.WriteByte(c)
+	if len(e.first) == 0 {
+		// a complete top level value
+		e.buf.WriteByte('\n')
+	}
+	return e.flush()
+}
+
+// BeginObject implements [EventHandler].
+func (e *EventWriter) BeginObject() error { return e.begin('{') }
+
+// EndObject implements [EventHandler].
+func (e *EventWriter) EndObject() error { return e.end('}') }
+
+// BeginArray implements [EventHandler].
+func (e *EventWriter) BeginArray() error { return e.begin('[') }
+
+// EndArray implements [EventHandler].
+func (e *EventWriter) EndArray() error { return e.end(']') }
+
+// Key implements [EventHandler].
+func (e *EventWriter) Key(k string) error {
+	e.separate()
+	writeJSONString(&e.buf, k, true, false)
+	e.buf.WriteByte(':')
+	e.afterKey = true
+	return e.flush()
+}
+
+// Scalar implements [EventHandler].
+func (e *EventWriter) Scalar(v Value) error {
+	e.separate()
+	enc := Encoder{escapeHTML: true}
+	if err := enc.encode(&e.buf, Path{}, v); err != nil {
+		e.buf.Reset()
+		return err
+	}
+	if len(e.first) == 0 {
+		e.buf.WriteByte('\n')
+	}
+	return e.flush()
+}
@@ -0,0 +1,166 @@
+package simple
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// eventLog records events as strings.
+type eventLog []string
+
+func (l *eventLog) BeginObject() error   { *l = append(*l, "{"); return nil }
+func (l *eventLog) Key(k string) error   { *l = append(*l, k+":"); return nil }
+func (l *eventLog) EndObject() error     { *l = append(*l, "}"); return nil }
+func (l *eventLog) BeginArray() error    { *l = append(*l, "["); return nil }
+func (l *eventLog) EndArray() error      { *l = append(*l, "]"); return nil }
+func (l *eventLog) Scalar(v Value) error { *l = append(*l, fmt.Sprint(v)); return nil }
+
+// dropKeys is a streaming filter that removes the given keys.
+type dropKeys struct {
+	EventHandler
+	keys  map[string]bool
+	depth int
+}
+
+func (d *dropKeys) skip(delta int) bool {
+	if d.depth == 0 {
+		return false
+	}
+	d.depth += delta
+	return true
+}
+
+func (d *dropKeys) Key(k string) error {
+	if d.depth > 0 {
+		return nil
+	}
+	if d.keys[k] {
+		d.depth = 1
+		return nil
+	}
+	return d.EventHandler.Key(k)
+}
+
+func (d *dropKeys) BeginObject() error {
+	if d.skip(1) {
+		return nil
+	}
+	return d.EventHandler.BeginObject()
+}
+
+func (d *dropKeys) BeginArray() error {
+	if d.skip(1) {
+		return nil
+	}
+	return d.EventHandler.BeginArray()
+}
+
+func (d *dropKeys) EndObject() error {
+	if d.skip(-1) {
+		if d.depth == 1 {
+			d.depth = 0
+		}
+		return nil
+	}
+	return d.EventHandler.EndObject()
+}
+
+func (d *dropKeys) EndArray() error {
+	if d.skip(-1) {
+		if d.depth == 1 {
+			d.depth = 0
+		}
+		return nil
+	}
+	return d.EventHandler.EndArray()
+}
+
+func (d *dropKeys) Scalar(v Value) error {
+	if d.depth == 1 {
+		d.depth = 0
+		return nil
+	}
+	if d.depth > 0 {
+		return nil
+	}
+	return d.EventHandler.Scalar(v)
+}
+
+func TestEvents(t *testing.T) {
+	v := mustFromJSON(t, `{"b":[1,"x",null],"a":{"c":true}}`)
+
+	var log eventLog
+	require.NoError(t, Emit(v, &log))
+	require.Equal(t, eventLog{"{", "a:", "{", "c:", "true", "}", "b:", "[", "1", `"x"`, "<nil>", "]", "}"}, log)
+
+	var fromJSON eventLog
+	dec := NewJSONDecoder(strings.NewReader(`{"a":{"c":true},"b":[1,"x",null]}`))
+	require.NoError(t, dec.DecodeEvents(&fromJSON))
+	require.Equal(t, log, fromJSON)
+	require.ErrorIs(t, dec.DecodeEvents(&fromJSON), io.EOF)
+
+	var b ValueBuilder
+	require.NoError(t, Emit(v, &b))
+	got, err := b.Value()
+	require.NoError(t, err)
+	require.Equal(t, v, got)
+
+	var buf bytes.Buffer
+	require.NoError(t, Emit(v, NewEventWriter(&buf)))
+	require.NoError(t, Emit(String("<"), NewEventWriter(&buf)))
+	require.Equal(t, `{"a":{"c":true},"b":[1,"x",null]}`+"\n"+`"\u003c"`+"\n", buf.String())
+}
+
+func TestEventsFilter(t *testing.T) {
+	input := `{"id":1,"secret":{"deep":[1,{"x":2}]},"list":[{"secret":"a","ok":true},[]],"token":"t"} {"secret":3}`
+	var buf bytes.Buffer
+	dec := NewJSONDecoder(strings.NewReader(input), MaxDepth(4))
+	filter := &dropKeys{EventHandler: NewEventWriter(&buf), keys: map[string]bool{"secret": true, "token": true}}
+	for {
+		err := dec.DecodeEvents(filter)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+	require.Equal(t, `{"id":1,"list":[{"ok":true},[]]}`+"\n{}\n", buf.String())
+}
+
+func TestValueBuilderErrors(t *testing.T) {
+	for name, events := range map[string]func(b *ValueBuilder) error{
+		"no value": func(b *ValueBuilder) error { return nil },
+		"unclosed": func(b *ValueBuilder) error { return b.BeginArray() },
+		"missing key": func(b *ValueBuilder) error {
+			if err := b.BeginObject(); err != nil {
+				return err
+			}
+			return b.Scalar(nil)
+		},
+		"two values": func(b *ValueBuilder) error {
+			if err := b.Scalar(nil); err != nil {
+				return err
+			}
+			return b.Scalar(nil)
+		},
+		"mismatched end": func(b *ValueBuilder) error {
+			if err := b.BeginObject(); err != nil {
+				return err
+			}
+			return b.EndArray()
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var b ValueBuilder
+			err := events(&b)
+			if err == nil {
+				_, err = b.Value()
+			}
+			require.ErrorIs(t, err, errUnbalancedEvents)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package simple
+
+import (
+	"flag"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FromFlagSet collects the flags that were set on the command line into a
+// [Struct], so they can be merged with configuration from the environment
+// (see [FromEnviron]) and from files. Flags that were not provided are left
+// out, which lets them fall through to the other sources. Flag names are split
+// on sep into nested keys, so with a sep of "." -db.port=5432 becomes
+// {"db":{"port":5432}}. An empty sep does not nest anything.
+//
+// The values of the flags defined with the flag package keep their type:
+// booleans become a [Bool], integers an [Int], floats a [Number] and strings
+// a [String]. Unsigned integers too large for an Int become a [RawNumber].
+// Durations and custom flags are rendered with their String method and then
+// converted to a Bool or Number where they look like one.
+func FromFlagSet(fs *flag.FlagSet, sep string) Struct {
+	out := Struct{}
+	fs.Visit(func(f *flag.Flag) {
+		segs := []string{f.Name}
+		if sep != "" {
+			segs = strings.Split(f.Name, sep)
+		}
+		if slices.Contains(segs, "") {
+			return
+		}
+		out[segs[0]] = insertURLValue(out[segs[0]], segs[1:], Array{flagValue(f.Value)})
+	})
+	return out
+}
+
+func flagValue(fv flag.Value) Value {
+	if g, ok := fv.(flag.Getter); ok {
+		switch tv := g.Get().(type) {
+		case bool:
+			return Bool(tv)
+		case string:
+			return String(tv)
+		case int:
+			return Int(tv)
+		case int64:
+			return Int(tv)
+		case uint:
+			return uintValue(uint64(tv))
+		case uint64:
+			return uintValue(tv)
+		case float64:
+			return Number(tv)
+		}
+	}
+	return inferScalar(fv.String())
+}
+
+// uintValue returns u as an Int, or as a RawNumber when it does not fit in an
+// int64.
+func uintValue(u uint64) Value {
+	if u > math.MaxInt64 {
+		return RawNumber(strconv.FormatUint(u, 10))
+	}
+	return Int(u)
+}
@@ -0,0 +1,42 @@
+package simple
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromFlagSet(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.String("db.host", "localhost", "")
+	fs.Int("db.port", 5432, "")
+	fs.Bool("verbose", false, "")
+	fs.Duration("timeout", time.Second, "")
+	fs.String("name", "", "")
+	fs.Float64("ratio", 0, "")
+	fs.Func("tag", "", func(string) error { return nil })
+	fs.String("unset", "default", "")
+	fs.Int64("big", 0, "")
+	fs.Uint("small", 0, "")
+	fs.Uint64("huge", 0, "")
+
+	require.NoError(t, fs.Parse([]string{"-db.port=6543", "-verbose", "-timeout=1m30s", "-name=123", "-ratio=0.5", "-tag=true", "-big=-9007199254740993", "-small=7", "-huge=18446744073709551615"}))
+
+	require.Equal(t, Struct{
+		"db":      Struct{"port": Int(6543)},
+		"verbose": Bool(true),
+		"timeout": String("1m30s"),
+		"name":    String("123"),
+		"ratio":   Number(0.5),
+		"tag":     String(""),
+		"big":     Int(-9007199254740993),
+		"small":   Int(7),
+		"huge":    RawNumber("18446744073709551615"),
+	}, FromFlagSet(fs, "."))
+
+	require.Equal(t, Int(6543), FromFlagSet(fs, "")["db.port"])
+}
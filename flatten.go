@@ -0,0 +1,175 @@
+package simple
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type flattenCollisionError struct {
+	key  string
+	path Path
+}
+
+func (f flattenCollisionError) Error() string {
+	return fmt.Sprintf("flattened key %q is produced by more than one value", f.key)
+}
+
+func (f flattenCollisionError) Pointer() string { return f.path.Pointer() }
+
+// flattenLeaves calls fn with every leaf of v, its path and the key created by
+// joining the Struct keys and Array indexes leading to it with sep. Empty composites
+// are considered leaves. Keys are visited in sorted order so that errors are
+// deterministic.
+func flattenLeaves(prefix string, path Path, v Value, sep string, fn func(key string, path Path, leaf Value) error) error {
+	join := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + sep + k
+	}
+	switch tv := v.(type) {
+	case Struct:
+		if len(tv) == 0 {
+			break
+		}
+		for _, k := range sortedKeys(tv) {
+			if err := flattenLeaves(join(k), path.Key(k), tv[k], sep, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	case Array:
+		if len(tv) == 0 {
+			break
+		}
+		for i, ev := range tv {
+			if err := flattenLeaves(join(strconv.Itoa(i)), path.Index(i), ev, sep, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fn(prefix, path, v)
+}
+
+// ToStringMap flattens s into a single level map of strings, for exporting to
+// places that only accept flat string maps (HTTP headers, environment
+// variables, label sets, etc.).
+//
+// Nested keys are joined with a ".", and array elements are keyed by their
+// index, so {"a":{"b":[true]}} becomes {"a.b.0":"true"}. Leaves are rendered
+// with [AsString]. Null values and empty composites have no leaves and are
+// omitted. If two different values flatten to the same key (e.g. {"a.b":1} and
+// {"a":{"b":2}}) an error is returned.
+func ToStringMap(s Struct) (map[string]string, error) {
+	out := make(map[string]string, len(s))
+	err := flattenLeaves("", Path{}, s, ".", func(key string, path Path, leaf Value) error {
+		switch leaf.(type) {
+		case nil, Struct, Array:
+			return nil
+		}
+		if _, exists := out[key]; exists {
+			return flattenCollisionError{key: key, path: path}
+		}
+		str, err := AsString(leaf)
+		if err != nil {
+			return encodeError{path: path, problem: err.Error(), err: err}
+		}
+		out[key] = str
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Flatten turns v into a single level [Struct] by joining the keys and array
+// indexes that lead to each leaf with sep, so with a sep of "."
+// {"a":{"b":[true]}} becomes {"a.b.0":true}. Unlike [ToStringMap], leaves keep
+// their type, and nulls and empty composites are kept as leaves so that
+// [Unflatten] can restore them. If more than one leaf flattens to the same key,
+// the one that comes last in sorted key order wins.
+func Flatten(v Value, sep string) Struct {
+	out := Struct{}
+	_ = flattenLeaves("", Path{}, v, sep, func(key string, _ Path, leaf Value) error {
+		out[key] = leaf
+		return nil
+	})
+	return out
+}
+
+type unflattenConflictError struct {
+	key string
+}
+
+func (u unflattenConflictError) Error() string {
+	return fmt.Sprintf("key %q conflicts with another key", u.key)
+}
+
+// unflatNode is an intermediate container built up by Unflatten, it is kept
+// distinct from Struct so that leaves that happen to be Structs are not merged
+// into.
+type unflatNode map[string]any
+
+// Unflatten is the inverse of [Flatten]. Each key of s is split on sep and
+// nested Structs are created along the way. A level whose keys are exactly
+// "0" through "n-1" becomes an [Array]. An error is returned if a key is both a
+// leaf and a parent of other keys, like "a" and "a.b".
+func Unflatten(s Struct, sep string) (Value, error) {
+	if v, ok := s[""]; ok && len(s) == 1 {
+		return v, nil
+	}
+	root := unflatNode{}
+	for _, key := range sortedKeys(s) {
+		parts := strings.Split(key, sep)
+		cur := root
+		for i, part := range parts {
+			if i == len(parts)-1 {
+				if _, exists := cur[part]; exists {
+					return nil, unflattenConflictError{key: key}
+				}
+				cur[part] = s[key]
+				break
+			}
+			next, exists := cur[part]
+			if !exists {
+				next = unflatNode{}
+				cur[part] = next
+			}
+			node, ok := next.(unflatNode)
+			if !ok {
+				return nil, unflattenConflictError{key: key}
+			}
+			cur = node
+		}
+	}
+	return root.value(), nil
+}
+
+func (u unflatNode) value() Value {
+	convert := func(c any) Value {
+		if n, ok := c.(unflatNode); ok {
+			return n.value()
+		}
+		v, _ := c.(Value)
+		return v
+	}
+	isArray := len(u) > 0
+	for i := 0; i < len(u) && isArray; i++ {
+		_, isArray = u[strconv.Itoa(i)]
+	}
+	if isArray {
+		out := make(Array, len(u))
+		for i := range out {
+			out[i] = convert(u[strconv.Itoa(i)])
+		}
+		return out
+	}
+	out := make(Struct, len(u))
+	for k, c := range u {
+		out[k] = convert(c)
+	}
+	return out
+}
@@ -0,0 +1,73 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToStringMap(t *testing.T) {
+	got, err := ToStringMap(Struct{
+		"name":     String("app"),
+		"replicas": Number(3),
+		"labels": Struct{
+			"tier":   String("web"),
+			"public": Bool(true),
+		},
+		"ports":  Array{Number(80), Number(443)},
+		"empty":  Struct{},
+		"absent": nil,
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"name":          "app",
+		"replicas":      "3",
+		"labels.tier":   "web",
+		"labels.public": "true",
+		"ports.0":       "80",
+		"ports.1":       "443",
+	}, got)
+
+	_, err = ToStringMap(Struct{
+		"a.b": Number(1),
+		"a":   Struct{"b": Number(2)},
+	})
+	require.EqualError(t, err, `flattened key "a.b" is produced by more than one value`)
+}
+
+func TestFlatten(t *testing.T) {
+	v := Struct{
+		"db": Struct{
+			"hosts": Array{String("a"), String("b")},
+			"port":  Number(5432),
+		},
+		"debug": Bool(false),
+		"extra": Struct{},
+		"note":  nil,
+	}
+	flat := Flatten(v, "__")
+	require.Equal(t, Struct{
+		"db__hosts__0": String("a"),
+		"db__hosts__1": String("b"),
+		"db__port":     Number(5432),
+		"debug":        Bool(false),
+		"extra":        Struct{},
+		"note":         nil,
+	}, flat)
+
+	back, err := Unflatten(flat, "__")
+	require.NoError(t, err)
+	require.Equal(t, v, back)
+
+	require.Equal(t, Struct{"": Number(1)}, Flatten(Number(1), "."))
+	back, err = Unflatten(Struct{"": Number(1)}, ".")
+	require.NoError(t, err)
+	require.Equal(t, Number(1), back)
+
+	back, err = Unflatten(Struct{"a.0": Number(1), "a.2": Number(2)}, ".")
+	require.NoError(t, err)
+	require.Equal(t, Struct{"a": Struct{"0": Number(1), "2": Number(2)}}, back)
+
+	_, err = Unflatten(Struct{"a": Number(1), "a.b": Number(2)}, ".")
+	require.EqualError(t, err, `key "a.b" conflicts with another key`)
+}
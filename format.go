@@ -0,0 +1,50 @@
+package simple
+
+import (
+	"fmt"
+	"io"
+)
+
+// formatValue implements [fmt.Formatter] for all Value types. %v and %s print
+// v as compact JSON, %+v as indented JSON and %#v as a Go literal. %q quotes
+// the JSON, and every other verb is applied to the underlying Go value, so
+// that %.2f works on a [Number] and %x on a [String].
+func formatValue(f fmt.State, verb rune, v Value, underlying any) {
+	switch verb {
+	case 'v', 's':
+		switch {
+		case verb == 'v' && f.Flag('#'):
+			io.WriteString(f, goString(v))
+		case verb == 'v' && f.Flag('+'):
+			fmt.Fprintf(f, fmt.FormatString(f, 's'), v.StringIndent("", "  "))
+		default:
+			fmt.Fprintf(f, fmt.FormatString(f, 's'), v.String())
+		}
+	case 'q':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), v.String())
+	default:
+		fmt.Fprintf(f, fmt.FormatString(f, verb), underlying)
+	}
+}
+
+// Format implements [fmt.Formatter], so %v prints s as compact JSON, %+v as
+// indented JSON and %#v as a Go literal.
+func (s Struct) Format(f fmt.State, verb rune) { formatValue(f, verb, s, map[string]Value(s)) }
+
+// Format implements [fmt.Formatter], see [Struct.Format].
+func (a Array) Format(f fmt.State, verb rune) { formatValue(f, verb, a, []Value(a)) }
+
+// Format implements [fmt.Formatter], see [Struct.Format].
+func (n Number) Format(f fmt.State, verb rune) { formatValue(f, verb, n, float64(n)) }
+
+// Format implements [fmt.Formatter], see [Struct.Format].
+func (i Int) Format(f fmt.State, verb rune) { formatValue(f, verb, i, int64(i)) }
+
+// Format implements [fmt.Formatter], see [Struct.Format].
+func (r RawNumber) Format(f fmt.State, verb rune) { formatValue(f, verb, r, string(r)) }
+
+// Format implements [fmt.Formatter], see [Struct.Format].
+func (s String) Format(f fmt.State, verb rune) { formatValue(f, verb, s, string(s)) }
+
+// Format implements [fmt.Formatter], see [Struct.Format].
+func (b Bool) Format(f fmt.State, verb rune) { formatValue(f, verb, b, bool(b)) }
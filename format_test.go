@@ -0,0 +1,27 @@
+package simple
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	v := Struct{"a": Array{Int(1), String("x")}}
+	require.Equal(t, `{"a":[1,"x"]}`, fmt.Sprintf("%v", v))
+	require.Equal(t, `{"a":[1,"x"]}`, fmt.Sprintf("%s", v))
+	require.Equal(t, "{\n  \"a\": [\n    1,\n    \"x\"\n  ]\n}", fmt.Sprintf("%+v", v))
+	require.Equal(t, `simple.Struct{"a": simple.Array{simple.Int(1), simple.String("x")}}`, fmt.Sprintf("%#v", v))
+	require.Equal(t, `"{\"a\":[1,\"x\"]}"`, fmt.Sprintf("%q", v))
+
+	require.Equal(t, `"x"`, fmt.Sprintf("%v", String("x")))
+	require.Equal(t, `  "x"|"x"  `, fmt.Sprintf("%5v|%-5s", String("x"), String("x")))
+	require.Equal(t, "78", fmt.Sprintf("%x", String("x")))
+	require.Equal(t, "1.50", fmt.Sprintf("%.2f", Number(1.5)))
+	require.Equal(t, "007", fmt.Sprintf("%03d", Int(7)))
+	require.Equal(t, "true", fmt.Sprintf("%t", Bool(true)))
+	require.Equal(t, "1.0", fmt.Sprintf("%+v", RawNumber("1.0")))
+	require.Equal(t, `simple.Number(2)`, fmt.Sprintf("%#v", Number(2)))
+	require.Equal(t, "value: [true]", fmt.Sprintf("value: %v", Value(Array{Bool(true)})))
+}
@@ -1,6 +1,6 @@
 module code.nkcmr.net/simple
 
-go 1.22
+go 1.23
 
 require github.com/stretchr/testify v1.10.0
 
@@ -0,0 +1,115 @@
+package simple
+
+import (
+	"fmt"
+	"go/format"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GoLiteral renders v as Go source for a composite literal that evaluates to
+// v, so real-world payloads can be captured and pasted into table-driven
+// tests. Types are prefixed with qualifier, which should be "simple." for
+// code outside of this package and "" for code inside of it. The result is
+// formatted with gofmt, with one entry per line.
+func GoLiteral(v Value, qualifier string) string {
+	var sb strings.Builder
+	writeGoLiteral(&sb, v, qualifier, true)
+	src, err := format.Source([]byte("var _ = " + sb.String()))
+	if err != nil {
+		// writeGoLiteral only writes valid Go, but the unformatted code is
+		// still better than nothing
+		return sb.String()
+	}
+	return strings.TrimPrefix(string(src), "var _ = ")
+}
+
+func writeGoLiteral(sb *strings.Builder, v Value, q string, multiline bool) {
+	nl, sep := "", ", "
+	switch tv := v.(type) {
+	case nil:
+		sb.WriteString("nil")
+	case Struct:
+		if len(tv) > 0 && multiline {
+			nl, sep = "\n", ",\n"
+		}
+		sb.WriteString(q + "Struct{" + nl)
+		for i, k := range sortedKeys(tv) {
+			if i > 0 {
+				sb.WriteString(sep)
+			}
+			sb.WriteString(strconv.Quote(k) + ": ")
+			writeGoLiteral(sb, tv[k], q, multiline)
+		}
+		if nl != "" {
+			sb.WriteString(",\n")
+		}
+		sb.WriteString("}")
+	case Array:
+		if len(tv) > 0 && multiline {
+			nl, sep = "\n", ",\n"
+		}
+		sb.WriteString(q + "Array{" + nl)
+		for i, ev := range tv {
+			if i > 0 {
+				sb.WriteString(sep)
+			}
+			writeGoLiteral(sb, ev, q, multiline)
+		}
+		if nl != "" {
+			sb.WriteString(",\n")
+		}
+		sb.WriteString("}")
+	case Number:
+		f := float64(tv)
+		switch {
+		case math.IsNaN(f):
+			sb.WriteString(q + "Number(math.NaN())")
+		case math.IsInf(f, 1):
+			sb.WriteString(q + "Number(math.Inf(1))")
+		case math.IsInf(f, -1):
+			sb.WriteString(q + "Number(math.Inf(-1))")
+		default:
+			sb.WriteString(q + "Number(" + strconv.FormatFloat(f, 'g', -1, 64) + ")")
+		}
+	case Int:
+		sb.WriteString(q + "Int(" + strconv.FormatInt(int64(tv), 10) + ")")
+	case RawNumber:
+		sb.WriteString(q + "RawNumber(" + strconv.Quote(string(tv)) + ")")
+	case String:
+		sb.WriteString(q + "String(" + strconv.Quote(string(tv)) + ")")
+	case Bool:
+		sb.WriteString(q + "Bool(" + strconv.FormatBool(bool(tv)) + ")")
+	default:
+		fmt.Fprintf(sb, "%#v", v)
+	}
+}
+
+func goString(v Value) string {
+	var sb strings.Builder
+	writeGoLiteral(&sb, v, "simple.", false)
+	return sb.String()
+}
+
+// GoString implements [fmt.GoStringer], so that %#v prints s as a Go
+// literal. See [GoLiteral] for a formatted version.
+func (s Struct) GoString() string { return goString(s) }
+
+// GoString implements [fmt.GoStringer].
+func (a Array) GoString() string { return goString(a) }
+
+// GoString implements [fmt.GoStringer].
+func (n Number) GoString() string { return goString(n) }
+
+// GoString implements [fmt.GoStringer].
+func (i Int) GoString() string { return goString(i) }
+
+// GoString implements [fmt.GoStringer].
+func (r RawNumber) GoString() string { return goString(r) }
+
+// GoString implements [fmt.GoStringer].
+func (s String) GoString() string { return goString(s) }
+
+// GoString implements [fmt.GoStringer].
+func (b Bool) GoString() string { return goString(b) }
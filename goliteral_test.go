@@ -0,0 +1,39 @@
+package simple
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoLiteral(t *testing.T) {
+	v := Struct{
+		"name":  String("ann \"a\""),
+		"n":     Number(1.5),
+		"big":   Number(1e21),
+		"count": Int(3),
+		"raw":   RawNumber("1.0"),
+		"tags":  Array{Bool(true), nil, Number(math.Inf(-1))},
+		"empty": Struct{},
+	}
+	require.Equal(t, `simple.Struct{"big": simple.Number(1e+21), "count": simple.Int(3), "empty": simple.Struct{}, "n": simple.Number(1.5), "name": simple.String("ann \"a\""), "raw": simple.RawNumber("1.0"), "tags": simple.Array{simple.Bool(true), nil, simple.Number(math.Inf(-1))}}`, fmt.Sprintf("%#v", v))
+	require.Equal(t, `simple.String("x")`, fmt.Sprintf("%#v", String("x")))
+
+	require.Equal(t, `Struct{
+	"big":   Number(1e+21),
+	"count": Int(3),
+	"empty": Struct{},
+	"n":     Number(1.5),
+	"name":  String("ann \"a\""),
+	"raw":   RawNumber("1.0"),
+	"tags": Array{
+		Bool(true),
+		nil,
+		Number(math.Inf(-1)),
+	},
+}`, GoLiteral(v, ""))
+	require.Equal(t, "simple.Number(math.NaN())", GoLiteral(Number(math.NaN()), "simple."))
+	require.Equal(t, "nil", GoLiteral(nil, "simple."))
+}
@@ -0,0 +1,63 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoTypes(t *testing.T) {
+	samples := []Value{
+		mustFromJSON(t, `{"id": 1, "user_name": "ann", "address": {"city": "x", "zip": null}, "line_items": [{"sku": "a", "qty": 2.5}], "tags": ["a"], "meta": {}, "mixed": 1}`),
+		mustFromJSON(t, `{"id": 2, "user_name": "bob", "line_items": [], "tags": [], "meta": {}, "mixed": "x", "ID": true}`),
+	}
+	src, err := GoTypes("order", InferSchema(samples...), GoPackage("api"))
+	require.NoError(t, err)
+	require.Equal(t, "package api\n\n"+
+		"type Order struct {\n"+
+		"\tID        *bool           `json:\"ID,omitempty\"`\n"+
+		"\tAddress   *OrderAddress   `json:\"address,omitempty\"`\n"+
+		"\tID2       int64           `json:\"id\"`\n"+
+		"\tLineItems []OrderLineItem `json:\"line_items\"`\n"+
+		"\tMeta      map[string]any  `json:\"meta\"`\n"+
+		"\tMixed     any             `json:\"mixed\"`\n"+
+		"\tTags      []string        `json:\"tags\"`\n"+
+		"\tUserName  string          `json:\"user_name\"`\n"+
+		"}\n\n"+
+		"type OrderAddress struct {\n"+
+		"\tCity string `json:\"city\"`\n"+
+		"\tZip  any    `json:\"zip\"`\n"+
+		"}\n\n"+
+		"type OrderLineItem struct {\n"+
+		"\tQty float64 `json:\"qty\"`\n"+
+		"\tSku string  `json:\"sku\"`\n"+
+		"}\n", string(src))
+
+	src, err = GoTypes("Scores", InferSchema(mustFromJSON(t, `[1, 2]`)))
+	require.NoError(t, err)
+	require.Equal(t, "type Scores []int64\n", string(src))
+}
+
+func TestGoIdentifier(t *testing.T) {
+	for k, want := range map[string]string{
+		"user_id":    "UserID",
+		"first-name": "FirstName",
+		"HTTPStatus": "HTTPStatus",
+		"123":        "X123",
+		"":           "X",
+		"ünïcode":    "Ünïcode",
+	} {
+		require.Equal(t, want, goIdentifier(k), k)
+	}
+}
+
+func TestGoTypesGeneratedBy(t *testing.T) {
+	schema := InferSchema(Struct{"a": Bool(true)})
+	src, err := GoTypes("thing", schema, GoPackage("x"), GeneratedBy("gen"))
+	require.NoError(t, err)
+	require.Equal(t, "// Code generated by gen; DO NOT EDIT.\n\npackage x\n\ntype Thing struct {\n\tA bool `json:\"a\"`\n}\n", string(src))
+
+	src, err = GoTypes("thing", schema, GeneratedBy("gen"))
+	require.NoError(t, err)
+	require.Equal(t, "type Thing struct {\n\tA bool `json:\"a\"`\n}\n", string(src))
+}
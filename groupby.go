@@ -0,0 +1,23 @@
+package simple
+
+// GroupBy groups the elements of a by the value at key inside of each of
+// them, for report-style processing. The result maps every distinct value to
+// an [Array] of the elements that have it, in their original order. Values
+// are keyed by their [CanonicalJSON], so strings are quoted and never collide
+// with numbers, booleans or null, and equal numbers like Int(1) and Number(1)
+// share a group. Elements without a value at key are grouped under null.
+func GroupBy(a Array, key Path) Struct {
+	out := Struct{}
+	for _, ev := range a {
+		v, _ := Lookup(ev, key)
+		cj, err := CanonicalJSON(v)
+		if err != nil {
+			// only non-finite numbers and invalid UTF-8 cannot be encoded
+			cj = []byte(shortString(v))
+		}
+		k := string(cj)
+		group, _ := out[k].(Array)
+		out[k] = append(group, ev)
+	}
+	return out
+}
@@ -0,0 +1,36 @@
+package simple
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupBy(t *testing.T) {
+	events := Array{
+		Struct{"type": String("click"), "n": Int(1)},
+		Struct{"type": String("view"), "n": Int(2)},
+		Struct{"type": String("click"), "n": Int(3)},
+		Struct{"type": Number(5)},
+		Struct{"type": Struct{"b": Bool(true), "a": nil}},
+		Struct{"type": nil},
+		Struct{},
+		Struct{"type": Number(math.NaN())},
+		Struct{"type": String("5")},
+		Struct{"type": String("null")},
+	}
+	require.Equal(t, Struct{
+		`"click"`:             Array{events[0], events[2]},
+		`"view"`:              Array{events[1]},
+		"5":                   Array{events[3]},
+		`{"a":null,"b":true}`: Array{events[4]},
+		"null":                Array{events[5], events[6]},
+		"NaN":                 Array{events[7]},
+		`"5"`:                 Array{events[8]},
+		`"null"`:              Array{events[9]},
+	}, GroupBy(events, MustParsePath("type")))
+
+	require.Equal(t, Struct{"1": Array{Int(1), Number(1)}}, GroupBy(Array{Int(1), Number(1)}, nil))
+	require.Equal(t, Struct{}, GroupBy(nil, nil))
+}
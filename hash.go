@@ -0,0 +1,92 @@
+package simple
+
+import (
+	"crypto"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"math"
+)
+
+// Hash computes a deterministic 64-bit hash of v, suitable for deduplication,
+// sharding and change detection. The hash is the same across processes and
+// machines. Struct keys do not affect the hash through their order, while
+// Array elements do. Values that are equal (including nil and empty
+// composites, and negative and positive zero) hash the same.
+//
+// Hash is not cryptographically secure, see [Digest] for that.
+func Hash(v Value) uint64 {
+	h := fnv.New64a()
+	writeHash(h, v)
+	return h.Sum64()
+}
+
+func writeHash(h hash.Hash64, v Value) {
+	var scratch [9]byte
+	writeUint := func(tag byte, u uint64) {
+		scratch[0] = tag
+		binary.BigEndian.PutUint64(scratch[1:], u)
+		h.Write(scratch[:])
+	}
+	switch tv := v.(type) {
+	case nil:
+		h.Write([]byte{'z'})
+	case Bool:
+		if tv {
+			h.Write([]byte{'t'})
+		} else {
+			h.Write([]byte{'f'})
+		}
+	case Number, RawNumber, Int:
+		f, ok := numberValue(tv)
+		if !ok {
+			f = math.NaN()
+		}
+		if f == 0 {
+			f = 0
+		}
+		bits := math.Float64bits(f)
+		if math.IsNaN(f) {
+			bits = 0x7FF8000000000001
+		}
+		writeUint('n', bits)
+	case String:
+		writeUint('s', uint64(len(tv)))
+		h.Write([]byte(tv))
+	case Array:
+		writeUint('a', uint64(len(tv)))
+		for _, ev := range tv {
+			writeHash(h, ev)
+		}
+	case Struct:
+		// entries are hashed on their own and then summed, which makes the
+		// result independent of iteration order.
+		var sum uint64
+		for k, ev := range tv {
+			eh := fnv.New64a()
+			writeHash(eh, String(k))
+			writeHash(eh, ev)
+			sum += eh.Sum64()
+		}
+		writeUint('o', uint64(len(tv)))
+		writeUint('o', sum)
+	}
+}
+
+// Digest computes a cryptographic digest of the [CanonicalJSON] form of v,
+// for integrity checks and content-addressed storage. The hash function must
+// be linked into the binary, e.g. by importing crypto/sha256 for
+// crypto.SHA256.
+func Digest(v Value, h crypto.Hash) ([]byte, error) {
+	if !h.Available() {
+		return nil, fmt.Errorf("simple.Digest: hash function %s is not available", h)
+	}
+	cj, err := CanonicalJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	hasher := h.New()
+	hasher.Write(cj)
+	return hasher.Sum(nil), nil
+}
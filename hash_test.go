@@ -0,0 +1,47 @@
+package simple
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHash(t *testing.T) {
+	a := mustFromJSON(t, `{"a": 1, "b": [true, null, "x"], "c": {"d": 2.5}}`)
+	b := mustFromJSON(t, `{"c": {"d": 2.5}, "b": [true, null, "x"], "a": 1}`)
+	require.Equal(t, Hash(a), Hash(b))
+
+	// the hash is stable across versions and machines
+	require.Equal(t, uint64(0xcde4fd04a32ab6ce), Hash(a))
+
+	for _, other := range []Value{
+		mustFromJSON(t, `{"a": 1, "b": [null, true, "x"], "c": {"d": 2.5}}`),
+		mustFromJSON(t, `{"a": 1, "b": [true, null, "x"], "c": {"d": 2.5}, "e": null}`),
+		mustFromJSON(t, `{"a": "1", "b": [true, null, "x"], "c": {"d": 2.5}}`),
+		mustFromJSON(t, `{"a": 1, "b": [true, null, "x"], "c": {"e": 2.5}}`),
+	} {
+		require.NotEqual(t, Hash(a), Hash(other), other.String())
+	}
+
+	require.Equal(t, Hash(Struct{}), Hash(Struct(nil)))
+	require.Equal(t, Hash(Number(0)), Hash(Number(math.Copysign(0, -1))))
+	require.NotEqual(t, Hash(Array{String("ab")}), Hash(Array{String("a"), String("b")}))
+	require.NotEqual(t, Hash(nil), Hash(Bool(false)))
+}
+
+func TestDigest(t *testing.T) {
+	v := mustFromJSON(t, `{"b": [1, 2], "a": "x"}`)
+	got, err := Digest(v, crypto.SHA256)
+	require.NoError(t, err)
+	sum := sha256.Sum256([]byte(`{"a":"x","b":[1,2]}`))
+	require.Equal(t, hex.EncodeToString(sum[:]), hex.EncodeToString(got))
+
+	_, err = Digest(v, crypto.MD4)
+	require.EqualError(t, err, `simple.Digest: hash function MD4 is not available`)
+	_, err = Digest(Number(math.Inf(1)), crypto.SHA256)
+	require.Error(t, err)
+}
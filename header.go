@@ -0,0 +1,47 @@
+package simple
+
+import (
+	"net/http"
+	"net/textproto"
+)
+
+// FromHTTPHeader converts h to a [Struct] that maps every header name to an
+// [Array] of its values, as strings. Names are kept as they are in h, which
+// normally means in their canonical form.
+func FromHTTPHeader(h http.Header) Struct {
+	out := make(Struct, len(h))
+	for k, vals := range h {
+		out[k] = urlStrings(vals)
+	}
+	return out
+}
+
+// ToHTTPHeader converts s back to an http.Header. Names are canonicalized,
+// and names that only differ in case are merged in sorted order. Values may be
+// scalars or arrays of scalars, which are rendered with [AsString]. Nulls are
+// left out, other values result in an error.
+func ToHTTPHeader(s Struct) (http.Header, error) {
+	out := make(http.Header, len(s))
+	for _, k := range sortedKeys(s) {
+		name := textproto.CanonicalMIMEHeaderKey(k)
+		vals, isArray := s[k].(Array)
+		if !isArray {
+			vals = Array{s[k]}
+		}
+		for i, v := range vals {
+			if v == nil {
+				continue
+			}
+			str, err := AsString(v)
+			if err != nil {
+				path := Path{k}
+				if isArray {
+					path = path.Index(i)
+				}
+				return nil, encodeError{path: path, problem: err.Error(), err: err}
+			}
+			out[name] = append(out[name], str)
+		}
+	}
+	return out, nil
+}
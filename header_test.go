@@ -0,0 +1,37 @@
+package simple
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPHeader(t *testing.T) {
+	h := http.Header{}
+	h.Add("Accept", "text/html")
+	h.Add("Accept", "application/json")
+	h.Set("X-Request-Id", "abc")
+
+	v := FromHTTPHeader(h)
+	require.Equal(t, `{"Accept":["text/html","application/json"],"X-Request-Id":["abc"]}`, v.String())
+
+	back, err := ToHTTPHeader(v)
+	require.NoError(t, err)
+	require.Equal(t, h, back)
+
+	back, err = ToHTTPHeader(Struct{
+		"content-length": Int(12),
+		"x-flag":         Array{Bool(true), nil},
+		"X-FLAG":         String("again"),
+		"x-none":         nil,
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.Header{
+		"Content-Length": {"12"},
+		"X-Flag":         {"again", "true"},
+	}, back)
+
+	_, err = ToHTTPHeader(Struct{"x-nested": Array{Struct{}}})
+	require.EqualError(t, err, `cannot encode value at .x-nested[0]: cannot coerce struct {...} to string`)
+}
@@ -0,0 +1,73 @@
+package simple
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+)
+
+var htmlTemplate = template.Must(template.New("value").Parse(
+	`<div class="simple-value">{{template "node" .}}</div>` +
+		`{{define "node"}}` +
+		`{{if .Children}}` +
+		`<details class="simple-{{.Kind}}"{{if .Open}} open{{end}}><summary>` +
+		`{{with .Label}}<span class="simple-key">{{.}}</span>: {{end}}` +
+		`<span class="simple-size">{{.Text}}</span></summary><ul>` +
+		`{{range .Children}}<li>{{template "node" .}}</li>{{end}}` +
+		`</ul></details>` +
+		`{{else}}` +
+		`{{with .Label}}<span class="simple-key">{{.}}</span>: {{end}}` +
+		`<span class="simple-{{.Kind}}">{{.Text}}</span>` +
+		`{{end}}` +
+		`{{end}}`,
+))
+
+type htmlNode struct {
+	Label    string
+	Kind     string
+	Text     string
+	Open     bool
+	Children []htmlNode
+}
+
+// RenderHTML writes v to w as an HTML fragment for admin and debug pages.
+// Every non-empty Struct and Array is a collapsible <details> element with a
+// list of its children, and every element has a "simple-" class named after
+// the kind of its value, like "simple-string", for styling. Keys are listed
+// in sorted order and all text is escaped.
+//
+// Everything is expanded, unless [FoldDepth] is given, in which case the
+// composites nested that many levels deep or more start out collapsed. Other
+// options are ignored.
+func RenderHTML(w io.Writer, v Value, opts ...RenderOption) error {
+	var r renderer
+	for _, o := range opts {
+		o(&r)
+	}
+	return htmlTemplate.Execute(w, r.htmlNode("", v, 0))
+}
+
+func (r *renderer) htmlNode(label string, v Value, depth int) htmlNode {
+	n := htmlNode{Label: label, Kind: kindName(v), Open: !r.folds(depth)}
+	switch tv := v.(type) {
+	case Struct:
+		if len(tv) > 0 {
+			n.Text = fmt.Sprintf("%d %s", len(tv), plural(len(tv), "key", "keys"))
+			for _, k := range sortedKeys(tv) {
+				n.Children = append(n.Children, r.htmlNode(k, tv[k], depth+1))
+			}
+			return n
+		}
+	case Array:
+		if len(tv) > 0 {
+			n.Text = fmt.Sprintf("%d %s", len(tv), plural(len(tv), "element", "elements"))
+			for i, ev := range tv {
+				n.Children = append(n.Children, r.htmlNode("["+strconv.Itoa(i)+"]", ev, depth+1))
+			}
+			return n
+		}
+	}
+	n.Text = scalarText(v)
+	return n
+}
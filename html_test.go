@@ -0,0 +1,31 @@
+package simple
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHTML(t *testing.T) {
+	v := Struct{
+		"name": String("<b>ann</b>"),
+		"tags": Array{Int(1), nil},
+		"none": Struct{},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, RenderHTML(&buf, v, FoldDepth(1)))
+	require.Equal(t, `<div class="simple-value">`+
+		`<details class="simple-struct" open><summary><span class="simple-size">3 keys</span></summary><ul>`+
+		`<li><span class="simple-key">name</span>: <span class="simple-string">&#34;&lt;b&gt;ann&lt;/b&gt;&#34;</span></li>`+
+		`<li><span class="simple-key">none</span>: <span class="simple-struct">{}</span></li>`+
+		`<li><details class="simple-array"><summary><span class="simple-key">tags</span>: <span class="simple-size">2 elements</span></summary><ul>`+
+		`<li><span class="simple-key">[0]</span>: <span class="simple-number">1</span></li>`+
+		`<li><span class="simple-key">[1]</span>: <span class="simple-null">null</span></li>`+
+		`</ul></details></li>`+
+		`</ul></details></div>`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, RenderHTML(&buf, Bool(true)))
+	require.Equal(t, `<div class="simple-value"><span class="simple-bool">true</span></div>`, buf.String())
+}
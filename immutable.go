@@ -0,0 +1,212 @@
+package simple
+
+import (
+	"fmt"
+	"maps"
+)
+
+type setError struct {
+	path    Path
+	problem string
+}
+
+func (s setError) Error() string {
+	return fmt.Sprintf("cannot set value at %s: %s", s.path, s.problem)
+}
+
+func (s setError) Pointer() string { return s.path.Pointer() }
+
+// Immutable holds a [Value] that cannot be changed. Set and Delete return a new
+// Immutable instead, which shares everything that did not change with the
+// original: only the Structs and Arrays on the path to the change are copied,
+// not their other children. This makes Immutables safe to read from many
+// goroutines at once, and makes snapshots of large documents, like the
+// configuration of a service, cheap.
+//
+// The zero Immutable holds null.
+type Immutable struct {
+	v Value
+}
+
+// NewImmutable returns an Immutable holding a deep copy of v, so changes to v
+// do not affect it.
+func NewImmutable(v Value) Immutable {
+	return Immutable{v: Clone(v)}
+}
+
+// Freeze returns an Immutable holding v itself, to protect documents that are
+// handed to plugins or user callbacks. Unlike [NewImmutable] it does not copy
+// v, so use [Clone] first if v is still going to change.
+//
+// Freeze returns an Immutable rather than a [Value]: a Value would hand out
+// its Structs and Arrays, which cannot be made read-only, and a new kind of
+// Value would be rejected by everything that switches on kinds. Nothing can
+// change the Immutable; Set and Delete return a changed copy instead.
+func Freeze(v Value) Immutable {
+	return Immutable{v: v}
+}
+
+// Value returns a deep copy of the held Value, which the caller is free to
+// modify.
+func (im Immutable) Value() Value {
+	return Clone(im.v)
+}
+
+// String returns the held Value as compact JSON.
+func (im Immutable) String() string {
+	if im.v == nil {
+		return "null"
+	}
+	return im.v.String()
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (im Immutable) MarshalJSON() ([]byte, error) {
+	return []byte(im.String()), nil
+}
+
+// Len returns the number of keys of a held Struct or elements of a held
+// Array, and 0 for everything else.
+func (im Immutable) Len() int {
+	switch tv := im.v.(type) {
+	case Struct:
+		return len(tv)
+	case Array:
+		return len(tv)
+	}
+	return 0
+}
+
+// Keys returns the keys of a held Struct in sorted order, and nil for
+// everything else.
+func (im Immutable) Keys() []string {
+	if s, ok := im.v.(Struct); ok {
+		return sortedKeys(s)
+	}
+	return nil
+}
+
+// Lookup returns the part of im at p, like [Lookup] does. It does not copy
+// anything.
+func (im Immutable) Lookup(p Path) (Immutable, bool) {
+	v, ok := Lookup(im.v, p)
+	return Immutable{v: v}, ok
+}
+
+// Set returns a copy of im where the value at p is a deep copy of v. Missing
+// Structs along p are created, and an index one past the end of an Array
+// appends to it. Setting a key of something other than a Struct or null, or
+// an index of something other than an Array, is an error.
+func (im Immutable) Set(p Path, v Value) (Immutable, error) {
+	if err := checkSetPath(im.v, p); err != nil {
+		return im, err
+	}
+	return Immutable{v: setPath(im.v, p, Clone(v))}, nil
+}
+
+// Delete returns a copy of im without the value at p. Deleting an element of
+// an Array shifts the elements after it down. Deleting something that does
+// not exist returns im unchanged.
+func (im Immutable) Delete(p Path) Immutable {
+	if _, ok := Lookup(im.v, p); !ok {
+		return im
+	}
+	return Immutable{v: deletePath(im.v, p)}
+}
+
+// checkSetPath reports the error that setting a value at p inside of root
+// would run into.
+func checkSetPath(root Value, p Path) error {
+	cur := root
+	for i, e := range p {
+		switch te := e.(type) {
+		case string:
+			s, ok := cur.(Struct)
+			if !ok && cur != nil {
+				return setError{path: p[:i], problem: fmt.Sprintf("%s is not a struct", kindName(cur))}
+			}
+			cur = s[te]
+		case int:
+			a, ok := cur.(Array)
+			if !ok {
+				return setError{path: p[:i], problem: fmt.Sprintf("%s is not an array", kindName(cur))}
+			}
+			if te < 0 || te > len(a) {
+				return setError{path: p[:i], problem: fmt.Sprintf("index %d is out of range", te)}
+			}
+			cur = nil
+			if te < len(a) {
+				cur = a[te]
+			}
+		default:
+			return setError{path: p[:i], problem: fmt.Sprintf("invalid path element %#v", e)}
+		}
+	}
+	return nil
+}
+
+// setPath returns a copy of root with v at p, copying only the Structs and
+// Arrays along p. p must have been checked with checkSetPath.
+func setPath(root Value, p Path, v Value) Value {
+	if len(p) == 0 {
+		return v
+	}
+	switch e := p[0].(type) {
+	case string:
+		s, _ := root.(Struct)
+		out := make(Struct, len(s)+1)
+		maps.Copy(out, s)
+		out[e] = setPath(s[e], p[1:], v)
+		return out
+	default:
+		i := e.(int)
+		a := root.(Array)
+		out := make(Array, len(a), max(len(a), i+1))
+		copy(out, a)
+		if i == len(a) {
+			return append(out, setPath(nil, p[1:], v))
+		}
+		out[i] = setPath(a[i], p[1:], v)
+		return out
+	}
+}
+
+// deletePath returns a copy of root without the value at p, copying only the
+// Structs and Arrays along p. root itself is returned if nothing is at p.
+func deletePath(root Value, p Path) Value {
+	if len(p) == 0 {
+		return nil
+	}
+	switch e := p[0].(type) {
+	case string:
+		s, ok := root.(Struct)
+		if !ok {
+			return root
+		}
+		child, ok := s[e]
+		if !ok {
+			return root
+		}
+		out := maps.Clone(s)
+		if len(p) == 1 {
+			delete(out, e)
+		} else {
+			out[e] = deletePath(child, p[1:])
+		}
+		return out
+	case int:
+		a, ok := root.(Array)
+		if !ok || e < 0 || e >= len(a) {
+			return root
+		}
+		if len(p) == 1 {
+			out := make(Array, 0, len(a)-1)
+			return append(append(out, a[:e]...), a[e+1:]...)
+		}
+		out := make(Array, len(a))
+		copy(out, a)
+		out[e] = deletePath(a[e], p[1:])
+		return out
+	}
+	return root
+}
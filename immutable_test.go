@@ -0,0 +1,84 @@
+package simple
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImmutable(t *testing.T) {
+	src := Struct{
+		"db":    Struct{"host": String("a"), "ports": Array{Int(1), Int(2)}},
+		"cache": Struct{"ttl": Int(60)},
+	}
+	im := NewImmutable(src)
+	src["db"].(Struct)["host"] = String("changed")
+	v, ok := im.Lookup(MustParsePath(".db.host"))
+	require.True(t, ok)
+	require.Equal(t, `"a"`, v.String())
+
+	next, err := im.Set(MustParsePath(".db.ports[2]"), Int(3))
+	require.NoError(t, err)
+	next, err = next.Set(MustParsePath(".new.nested"), Bool(true))
+	require.NoError(t, err)
+	require.Equal(t, `{"cache":{"ttl":60},"db":{"host":"a","ports":[1,2]}}`, im.String())
+	require.Equal(t, `{"cache":{"ttl":60},"db":{"host":"a","ports":[1,2,3]},"new":{"nested":true}}`, next.String())
+
+	// unchanged subtrees are shared
+	require.Equal(t, im.v.(Struct)["cache"], next.v.(Struct)["cache"])
+	im.v.(Struct)["cache"].(Struct)["shared"] = Bool(true)
+	require.Contains(t, next.String(), "shared")
+	delete(im.v.(Struct)["cache"].(Struct), "shared")
+
+	_, err = im.Set(MustParsePath(".db.host.x"), Int(1))
+	require.EqualError(t, err, "cannot set value at .db.host: string is not a struct")
+	_, err = im.Set(MustParsePath(".db.ports[5]"), Int(1))
+	require.EqualError(t, err, "cannot set value at .db.ports: index 5 is out of range")
+	_, err = im.Set(MustParsePath(".cache[0]"), Int(1))
+	require.EqualError(t, err, "cannot set value at .cache: struct is not an array")
+
+	deleted := next.Delete(MustParsePath(".db.ports[0]")).Delete(MustParsePath(".new"))
+	require.Equal(t, `{"cache":{"ttl":60},"db":{"host":"a","ports":[2,3]}}`, deleted.String())
+	require.Equal(t, next, next.Delete(MustParsePath(".missing.key")))
+	require.Equal(t, "null", im.Delete(Path{}).String())
+
+	out := next.Value()
+	out.(Struct)["db"].(Struct)["host"] = String("x")
+	require.Contains(t, next.String(), `"host":"a"`)
+
+	var zero Immutable
+	zero, err = zero.Set(MustParsePath(".a"), Int(1))
+	require.NoError(t, err)
+	require.Equal(t, `{"a":1}`, zero.String())
+}
+
+func TestFreeze(t *testing.T) {
+	v := Struct{"user": Struct{"name": String("ann"), "roles": Array{String("admin")}}}
+	f := Freeze(v)
+	require.Equal(t, []string{"user"}, f.Keys())
+	require.Equal(t, 1, f.Len())
+
+	user, ok := f.Lookup(MustParsePath(".user"))
+	require.True(t, ok)
+	require.Equal(t, []string{"name", "roles"}, user.Keys())
+	roles, _ := user.Lookup(MustParsePath(".roles"))
+	require.Equal(t, 1, roles.Len())
+	require.Nil(t, roles.Keys())
+
+	cp := user.Value().(Struct)
+	cp["name"] = String("mallory")
+	require.Equal(t, String("ann"), v["user"].(Struct)["name"])
+
+	changed, err := f.Set(MustParsePath(".user.name"), String("x"))
+	require.NoError(t, err)
+	require.Equal(t, `{"user":{"name":"x","roles":["admin"]}}`, changed.String())
+	require.Equal(t, String("ann"), v["user"].(Struct)["name"])
+	require.Equal(t, 0, f.Delete(MustParsePath(".user")).Len())
+	require.Equal(t, 1, f.Len())
+
+	jb, err := json.Marshal(map[string]any{"frozen": f})
+	require.NoError(t, err)
+	require.Equal(t, `{"frozen":{"user":{"name":"ann","roles":["admin"]}}}`, string(jb))
+	require.Equal(t, "null", Freeze(nil).String())
+}
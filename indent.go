@@ -0,0 +1,47 @@
+package simple
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// SetIndent makes the Encoder indent its output like [json.MarshalIndent]:
+// every element of a Struct or Array starts on a new line that begins with
+// prefix, followed by one copy of indent per level of nesting. Passing two
+// empty strings turns indentation off again.
+func (e *Encoder) SetIndent(prefix, indent string) {
+	e.prefix, e.indent = prefix, indent
+}
+
+// StringIndent implements [Value].
+func (s Struct) StringIndent(prefix, indent string) string {
+	return indentString(s.String(), prefix, indent)
+}
+
+// StringIndent implements [Value].
+func (a Array) StringIndent(prefix, indent string) string {
+	return indentString(a.String(), prefix, indent)
+}
+
+// StringIndent implements [Value]. Scalars are the same as their String.
+func (n Number) StringIndent(prefix, indent string) string { return n.String() }
+
+// StringIndent implements [Value]. Scalars are the same as their String.
+func (i Int) StringIndent(prefix, indent string) string { return i.String() }
+
+// StringIndent implements [Value]. Scalars are the same as their String.
+func (r RawNumber) StringIndent(prefix, indent string) string { return r.String() }
+
+// StringIndent implements [Value]. Scalars are the same as their String.
+func (s String) StringIndent(prefix, indent string) string { return s.String() }
+
+// StringIndent implements [Value]. Scalars are the same as their String.
+func (b Bool) StringIndent(prefix, indent string) string { return b.String() }
+
+func indentString(s, prefix, indent string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(s), prefix, indent); err != nil {
+		return s
+	}
+	return buf.String()
+}
@@ -0,0 +1,31 @@
+package simple
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringIndent(t *testing.T) {
+	v := Struct{
+		"b": Array{Int(1), String("<x>")},
+		"a": Struct{},
+	}
+	require.Equal(t, "{\n\t\"a\": {},\n\t\"b\": [\n\t\t1,\n\t\t\"\\u003cx\\u003e\"\n\t]\n}", v.StringIndent("", "\t"))
+	require.Equal(t, "[\n> 1\n>]", Array{Number(1)}.StringIndent(">", " "))
+	require.Equal(t, `"x"`, String("x").StringIndent("", "  "))
+	require.Equal(t, "1e999", RawNumber("1e999").StringIndent("", "  "))
+	require.Equal(t, "[]", Array{}.StringIndent("", "  "))
+}
+
+func TestEncoderSetIndent(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	require.NoError(t, enc.Encode(Struct{"a": Array{String("<x>")}}))
+	enc.SetIndent("", "")
+	require.NoError(t, enc.Encode(Array{Bool(true)}))
+	require.Equal(t, "{\n  \"a\": [\n    \"<x>\"\n  ]\n}\n[true]\n", buf.String())
+}
@@ -0,0 +1,190 @@
+package simple
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type iniSyntaxError struct {
+	line    int
+	problem string
+}
+
+func (i iniSyntaxError) Error() string {
+	return fmt.Sprintf("invalid INI at line %d: %s", i.line, i.problem)
+}
+
+// FromINI reads an INI file into a [Struct], so legacy configuration can go
+// through the same pipeline as JSON. Keys before the first section are at the
+// top level, and every section is a nested Struct. Dotted section names like
+// [db.replica] nest further. A key that is repeated within a section becomes
+// an [Array] of its values.
+//
+// Lines starting with ';' or '#' are comments. Values are converted to a
+// [Bool] or a [Number] where they look like one, the same way [FromEnviron]
+// does it, unless they are in double quotes, which are unquoted with
+// [strconv.Unquote].
+func FromINI(r io.Reader) (Struct, error) {
+	out := Struct{}
+	section := out
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if text == "" || text[0] == ';' || text[0] == '#' {
+			continue
+		}
+		if text[0] == '[' {
+			if text[len(text)-1] != ']' {
+				return nil, iniSyntaxError{line: line, problem: "section header is missing a closing ]"}
+			}
+			name := strings.TrimSpace(text[1 : len(text)-1])
+			section = out
+			for _, seg := range strings.Split(name, ".") {
+				seg = strings.TrimSpace(seg)
+				if seg == "" {
+					return nil, iniSyntaxError{line: line, problem: fmt.Sprintf("invalid section name %q", name)}
+				}
+				child, ok := section[seg].(Struct)
+				if !ok {
+					if _, exists := section[seg]; exists {
+						return nil, iniSyntaxError{line: line, problem: fmt.Sprintf("section %q conflicts with a key", name)}
+					}
+					child = Struct{}
+					section[seg] = child
+				}
+				section = child
+			}
+			continue
+		}
+		k, raw, ok := strings.Cut(text, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, iniSyntaxError{line: line, problem: "expected a key = value pair"}
+		}
+		raw = strings.TrimSpace(raw)
+		var v Value
+		if strings.HasPrefix(raw, `"`) {
+			s, err := strconv.Unquote(raw)
+			if err != nil {
+				return nil, iniSyntaxError{line: line, problem: fmt.Sprintf("invalid quoted value %s", raw)}
+			}
+			v = String(s)
+		} else {
+			v = inferScalar(raw)
+		}
+		switch existing := section[k].(type) {
+		case nil:
+			section[k] = v
+		case Array:
+			section[k] = append(existing, v)
+		case Struct:
+			return nil, iniSyntaxError{line: line, problem: fmt.Sprintf("key %q conflicts with a section", k)}
+		default:
+			section[k] = Array{existing, v}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ToINI writes s as an INI file that [FromINI] reads back. Scalars are
+// written as keys, Arrays of scalars as repeated keys and nested Structs as
+// sections with dotted names. Strings that would otherwise be read back as
+// something else are quoted. Arrays that contain composites cannot be
+// represented, which is an error.
+//
+// Some values do not survive the round trip: nulls and empty Arrays are left
+// out, and an Array of one element is written as a single key, which
+// [FromINI] reads back as the element itself.
+func ToINI(w io.Writer, s Struct) error {
+	iw := iniWriter{w: bufio.NewWriter(w)}
+	if err := iw.section(Path{}, "", s); err != nil {
+		return err
+	}
+	return iw.w.Flush()
+}
+
+type iniWriter struct {
+	w       *bufio.Writer
+	started bool
+}
+
+func (iw *iniWriter) section(path Path, name string, s Struct) error {
+	if name != "" {
+		if iw.started {
+			iw.w.WriteString("\n")
+		}
+		iw.w.WriteString("[" + name + "]\n")
+		iw.started = true
+	}
+	var sections []string
+	for _, k := range sortedKeys(s) {
+		if !validINIKey(k) {
+			return encodeError{path: path.Key(k), problem: fmt.Sprintf("%q cannot be used as an INI key", k)}
+		}
+		switch tv := s[k].(type) {
+		case nil:
+		case Struct:
+			if strings.Contains(k, ".") {
+				return encodeError{path: path.Key(k), problem: fmt.Sprintf("%q cannot be used as an INI section name", k)}
+			}
+			sections = append(sections, k)
+		case Array:
+			for i, ev := range tv {
+				if err := iw.value(path.Key(k).Index(i), k, ev); err != nil {
+					return err
+				}
+			}
+		default:
+			if err := iw.value(path.Key(k), k, tv); err != nil {
+				return err
+			}
+		}
+	}
+	for _, k := range sections {
+		full := k
+		if name != "" {
+			full = name + "." + k
+		}
+		if err := iw.section(path.Key(k), full, s[k].(Struct)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (iw *iniWriter) value(path Path, k string, v Value) error {
+	if v == nil {
+		return nil
+	}
+	switch v.(type) {
+	case Struct, Array:
+		return encodeError{path: path, problem: "INI values must be scalars"}
+	}
+	str, err := AsString(v)
+	if err != nil {
+		return encodeError{path: path, problem: err.Error()}
+	}
+	if _, isString := v.(String); isString {
+		if _, inferred := inferScalar(str).(String); !inferred || str != strings.TrimSpace(str) || strings.HasPrefix(str, `"`) || strings.ContainsAny(str, "\r\n") {
+			str = strconv.Quote(str)
+		}
+	}
+	iw.w.WriteString(k + " = " + str + "\n")
+	iw.started = true
+	return nil
+}
+
+func validINIKey(k string) bool {
+	return k != "" && k == strings.TrimSpace(k) && !strings.ContainsAny(k, "=[]\r\n") && k[0] != ';' && k[0] != '#'
+}
@@ -0,0 +1,93 @@
+package simple
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromINI(t *testing.T) {
+	s, err := FromINI(strings.NewReader(`; global settings
+name = app
+debug = true
+
+[db]
+host = localhost
+port = 5432
+password = "  secret; # "
+
+[db.replica]
+host = a
+host = b
+# trailing comment
+`))
+	require.NoError(t, err)
+	require.Equal(t, Struct{
+		"name":  String("app"),
+		"debug": Bool(true),
+		"db": Struct{
+			"host":     String("localhost"),
+			"port":     Number(5432),
+			"password": String("  secret; # "),
+			"replica":  Struct{"host": Array{String("a"), String("b")}},
+		},
+	}, s)
+
+	for in, msg := range map[string]string{
+		"[db":             "invalid INI at line 1: section header is missing a closing ]",
+		"a = 1\n[a]":      `invalid INI at line 2: section "a" conflicts with a key`,
+		"[a]\n[..]":       `invalid INI at line 2: invalid section name ".."`,
+		"novalue":         "invalid INI at line 1: expected a key = value pair",
+		`a = "unfinished`: `invalid INI at line 1: invalid quoted value "unfinished`,
+	} {
+		_, err := FromINI(strings.NewReader(in))
+		require.EqualError(t, err, msg)
+	}
+}
+
+func TestToINI(t *testing.T) {
+	s := Struct{
+		"name": String("app"),
+		"port": String("8080"),
+		"db": Struct{
+			"hosts":   Array{String("a"), String("b")},
+			"timeout": Number(1.5),
+			"replica": Struct{"lag": Int(3)},
+		},
+		"empty": Struct{},
+		"skip":  nil,
+	}
+	var buf bytes.Buffer
+	require.NoError(t, ToINI(&buf, s))
+	require.Equal(t, `name = app
+port = "8080"
+
+[db]
+hosts = a
+hosts = b
+timeout = 1.5
+
+[db.replica]
+lag = 3
+
+[empty]
+`, buf.String())
+
+	back, err := FromINI(&buf)
+	require.NoError(t, err)
+	delete(s, "skip")
+	require.True(t, equal(s, back), "%s", back)
+
+	// lossy cases
+	buf.Reset()
+	require.NoError(t, ToINI(&buf, Struct{"tags": Array{String("a")}, "none": Array{}, "null": nil}))
+	require.Equal(t, "tags = a\n", buf.String())
+	back, err = FromINI(&buf)
+	require.NoError(t, err)
+	require.Equal(t, Struct{"tags": String("a")}, back)
+
+	require.EqualError(t, ToINI(&buf, Struct{"a": Array{Struct{}}}), "cannot encode value at .a[0]: INI values must be scalars")
+	require.EqualError(t, ToINI(&buf, Struct{"a.b": Struct{}}), `cannot encode value at ["a.b"]: "a.b" cannot be used as an INI section name`)
+}
@@ -0,0 +1,52 @@
+package simple
+
+import (
+	"iter"
+)
+
+// All iterates over the keys and values of s in no particular order. Use
+// [Struct.Sorted] when the order matters.
+func (s Struct) All() iter.Seq2[string, Value] {
+	return func(yield func(string, Value) bool) {
+		for k, v := range s {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Sorted iterates over the keys and values of s in sorted key order.
+func (s Struct) Sorted() iter.Seq2[string, Value] {
+	return func(yield func(string, Value) bool) {
+		for _, k := range sortedKeys(s) {
+			if !yield(k, s[k]) {
+				return
+			}
+		}
+	}
+}
+
+// All iterates over the indexes and values of a.
+func (a Array) All() iter.Seq2[int, Value] {
+	return func(yield func(int, Value) bool) {
+		for i, v := range a {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+// Nodes iterates over v and everything nested inside of it, in the same order
+// as [Walk].
+func Nodes(v Value) iter.Seq2[Path, Value] {
+	return func(yield func(Path, Value) bool) {
+		_ = Walk(v, func(path Path, v Value) (bool, error) {
+			if !yield(path, v) {
+				return false, SkipAll
+			}
+			return true, nil
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterators(t *testing.T) {
+	s := Struct{"b": Number(2), "a": Number(1), "c": Array{Bool(true), nil}}
+
+	t.Run("Struct.All", func(t *testing.T) {
+		got := Struct{}
+		for k, v := range s.All() {
+			got[k] = v
+		}
+		require.Equal(t, s, got)
+	})
+	t.Run("Struct.Sorted", func(t *testing.T) {
+		var keys []string
+		for k := range s.Sorted() {
+			keys = append(keys, k)
+			if k == "b" {
+				break
+			}
+		}
+		require.Equal(t, []string{"a", "b"}, keys)
+	})
+	t.Run("Array.All", func(t *testing.T) {
+		var idxs []int
+		for i, v := range s["c"].(Array).All() {
+			idxs = append(idxs, i)
+			require.Equal(t, s["c"].(Array)[i], v)
+		}
+		require.Equal(t, []int{0, 1}, idxs)
+	})
+	t.Run("Nodes", func(t *testing.T) {
+		var paths []string
+		for p := range Nodes(s) {
+			paths = append(paths, p.String())
+			if p.String() == ".c[0]" {
+				break
+			}
+		}
+		require.Equal(t, []string{"", ".a", ".b", ".c", ".c[0]"}, paths)
+	})
+}
@@ -0,0 +1,467 @@
+package simple
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// JSONOption changes how JSON is turned into a [Value] by [FromJSON].
+type JSONOption func(*jsonBuilder)
+
+// DuplicateKey describes an object key that appeared more than once in the
+// same JSON object.
+type DuplicateKey struct {
+	// Path is the location of the duplicated key.
+	Path Path
+	// Offset is the byte offset of the input just after the duplicated key.
+	Offset int64
+}
+
+// DuplicateKeyError is returned by [FromJSON] when [RejectDuplicateKeys] is
+// used and a duplicate key is found.
+type DuplicateKeyError struct {
+	DuplicateKey
+}
+
+func (d DuplicateKeyError) Error() string {
+	return fmt.Sprintf("duplicate key %q at %s (offset %d)", d.Path[len(d.Path)-1], d.Path, d.Offset)
+}
+
+// Pointer returns Path as a JSON Pointer, see [ErrorPointer].
+func (d DuplicateKeyError) Pointer() string {
+	return d.Path.Pointer()
+}
+
+// RejectDuplicateKeys makes [FromJSON] fail with a [DuplicateKeyError] when an
+// object contains the same key twice. By default, like encoding/json, the last
+// value silently wins, which can be abused to smuggle values past validators
+// that see the first one.
+func RejectDuplicateKeys() JSONOption {
+	return func(b *jsonBuilder) { b.rejectDuplicates = true }
+}
+
+// CollectDuplicateKeys makes [FromJSON] append every duplicate key it finds to
+// dst. The last value still wins.
+func CollectDuplicateKeys(dst *[]DuplicateKey) JSONOption {
+	return func(b *jsonBuilder) { b.duplicates = dst }
+}
+
+// SyntaxError is returned by [FromJSON] when the input is not valid JSON. It
+// pinpoints where the problem is, so tools can point users at the exact
+// location in a config file.
+type SyntaxError struct {
+	// Msg describes the problem.
+	Msg string
+	// Offset is the number of bytes read before the problem was found.
+	Offset int64
+	// Line and Column locate the problem in the input, both start at 1.
+	// Column counts characters, not bytes.
+	Line, Column int
+
+	err error
+}
+
+func (s *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid JSON at line %d, column %d: %s", s.Line, s.Column, s.Msg)
+}
+
+// Unwrap returns the underlying error, typically a *json.SyntaxError.
+func (s *SyntaxError) Unwrap() error { return s.err }
+
+// wrapSyntaxError turns JSON syntax errors of encoding/json into a
+// *SyntaxError, other errors are returned as-is.
+func wrapSyntaxError(input []byte, err error) error {
+	var offset int64
+	msg := ""
+	switch te := err.(type) {
+	case *json.SyntaxError:
+		offset, msg = te.Offset, te.Error()
+	default:
+		if err != io.ErrUnexpectedEOF {
+			return err
+		}
+		offset, msg = int64(len(input)), "unexpected end of JSON input"
+	}
+	line, column := lineColumn(input, offset)
+	return &SyntaxError{Msg: msg, Offset: offset, Line: line, Column: column, err: err}
+}
+
+// lineColumn finds the line and column of the byte that was being read when
+// offset bytes of input had been consumed.
+func lineColumn(input []byte, offset int64) (line, column int) {
+	if offset > int64(len(input)) {
+		offset = int64(len(input))
+	}
+	pos := max(offset-1, 0)
+	before := input[:pos]
+	line = bytes.Count(before, []byte{'\n'}) + 1
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	column = utf8.RuneCount(before[lineStart:]) + 1
+	return line, column
+}
+
+// LimitError is returned when JSON input exceeds one of the limits set with
+// [MaxInputBytes], [MaxDepth], [MaxNodes] or [MaxStringLength].
+type LimitError struct {
+	// Limit is the name of the limit that was exceeded, like "depth".
+	Limit string
+	// Max is the configured limit.
+	Max int64
+	// Path is where the limit was exceeded, it is empty for the input size.
+	Path Path
+}
+
+// Is makes a depth LimitError match [ErrTooDeep].
+func (l *LimitError) Is(target error) bool {
+	return target == ErrTooDeep && l.Limit == "depth"
+}
+
+func (l *LimitError) Error() string {
+	msg := fmt.Sprintf("JSON input exceeds the maximum %s of %d", l.Limit, l.Max)
+	if len(l.Path) > 0 {
+		msg += " at " + l.Path.String()
+	}
+	return msg
+}
+
+// Pointer returns Path as a JSON Pointer, see [ErrorPointer]. The input size
+// is reported at the root.
+func (l *LimitError) Pointer() string {
+	return l.Path.Pointer()
+}
+
+// MaxInputBytes limits how many bytes of input will be read. For a
+// [JSONDecoder] the limit applies to the whole stream.
+func MaxInputBytes(n int64) JSONOption {
+	return func(b *jsonBuilder) { b.maxBytes = n }
+}
+
+// MaxDepth limits how deeply objects and arrays may be nested. A top-level
+// object or array is at depth 1.
+func MaxDepth(n int) JSONOption {
+	return func(b *jsonBuilder) { b.maxDepth = n }
+}
+
+// MaxNodes limits the total number of values (including every object, array
+// and scalar) in a single decoded value.
+func MaxNodes(n int) JSONOption {
+	return func(b *jsonBuilder) { b.maxNodes = n }
+}
+
+// MaxStringLength limits the length in bytes of strings, including object
+// keys.
+func MaxStringLength(n int) JSONOption {
+	return func(b *jsonBuilder) { b.maxString = n }
+}
+
+// jsonBuilder builds Values from a stream of JSON tokens, which allows for
+// things that json.Unmarshal cannot do, like noticing duplicate keys.
+type jsonBuilder struct {
+	rejectDuplicates bool
+	duplicates       *[]DuplicateKey
+
+	maxBytes  int64
+	maxDepth  int
+	maxNodes  int
+	maxString int
+
+	utf8            UTF8Policy
+	preserveNumbers bool
+	useInt          bool
+	pool            *Pool
+	strict          bool
+
+	// nodes counts the values built so far
+	nodes int
+}
+
+// node is called for every value that gets built and enforces the limits
+// that apply to it.
+func (b *jsonBuilder) node(path Path, tok json.Token) error {
+	b.nodes++
+	if b.maxNodes > 0 && b.nodes > b.maxNodes {
+		return &LimitError{Limit: "number of nodes", Max: int64(b.maxNodes), Path: path}
+	}
+	switch tt := tok.(type) {
+	case json.Delim:
+		if b.maxDepth > 0 && len(path)+1 > b.maxDepth {
+			return &LimitError{Limit: "depth", Max: int64(b.maxDepth), Path: path}
+		}
+	case string:
+		if b.maxString > 0 && len(tt) > b.maxString {
+			return &LimitError{Limit: "string length", Max: int64(b.maxString), Path: path}
+		}
+	}
+	return nil
+}
+
+// limitedReader is like io.LimitedReader, but fails instead of stopping
+// early when there is more input than allowed.
+type limitedReader struct {
+	r   io.Reader
+	n   int64
+	max int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		// only fail if there really is more input
+		var probe [1]byte
+		n, err := l.r.Read(probe[:])
+		if n > 0 {
+			return 0, &LimitError{Limit: "input size in bytes", Max: l.max}
+		}
+		return 0, err
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
+
+// JSONDecoder reads a stream of JSON values and turns them into Values. It
+// is the streaming counterpart of [FromJSON], and the place to enforce
+// resource limits when reading untrusted input:
+//
+//	dec := simple.NewJSONDecoder(r, simple.MaxInputBytes(1<<20), simple.MaxDepth(64))
+//	v, err := dec.Decode()
+type JSONDecoder struct {
+	dec *json.Decoder
+	b   jsonBuilder
+}
+
+// NewJSONDecoder returns a JSONDecoder that reads from r.
+func NewJSONDecoder(r io.Reader, opts ...JSONOption) *JSONDecoder {
+	var b jsonBuilder
+	for _, o := range opts {
+		o(&b)
+	}
+	if b.maxBytes > 0 {
+		r = &limitedReader{r: r, n: b.maxBytes, max: b.maxBytes}
+	}
+	if b.utf8 == UTF8Reject {
+		r = &utf8Reader{r: r}
+	}
+	dec := json.NewDecoder(r)
+	if b.preserveNumbers || b.useInt || b.strict {
+		dec.UseNumber()
+	}
+	return &JSONDecoder{dec: dec, b: b}
+}
+
+// More reports whether there is another value to decode.
+func (d *JSONDecoder) More() bool {
+	return d.dec.More()
+}
+
+// Decode reads the next JSON value from the stream. It returns io.EOF when
+// there is nothing left.
+func (d *JSONDecoder) Decode() (Value, error) {
+	if !d.dec.More() {
+		// let the decoder tell apart a clean EOF from garbage
+		if _, err := d.dec.Token(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("unexpected data at offset %d", d.dec.InputOffset())
+	}
+	d.b.nodes = 0
+	v, err := d.b.build(d.dec, Path{})
+	if err != nil {
+		return nil, err
+	}
+	if d.b.strict {
+		if err := checkTrailing(d.dec); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// FromJSONDecoder builds a Value from the next JSON value read from dec. This
+// allows simple to be used in the middle of a larger stream that is already
+// being read with dec, like a single element of a huge array:
+//
+//	dec.Token() // [
+//	for dec.More() {
+//		v, err := simple.FromJSONDecoder(dec)
+//		...
+//	}
+//
+// The settings of dec that change how tokens are read, like UseNumber, are
+// respected. Settings that only apply to decoding into Go types, like
+// DisallowUnknownFields, have nothing to act on. [MaxInputBytes] has no effect
+// since dec owns the reader. FromJSONDecoder returns io.EOF if dec has no more
+// input.
+func FromJSONDecoder(dec *json.Decoder, opts ...JSONOption) (Value, error) {
+	var b jsonBuilder
+	for _, o := range opts {
+		o(&b)
+	}
+	return b.build(dec, Path{})
+}
+
+// FromJSON will instantiate a Value based on JSON. Without any options, the
+// only possible failure is JSON syntax errors, which are reported as a
+// *[SyntaxError].
+func FromJSON(jb json.RawMessage, opts ...JSONOption) (Value, error) {
+	if len(opts) == 0 {
+		var anyv any
+		if err := json.Unmarshal(jb, &anyv); err != nil {
+			return nil, wrapSyntaxError(jb, err)
+		}
+		return fastFromValue(anyv)
+	}
+	var b jsonBuilder
+	for _, o := range opts {
+		o(&b)
+	}
+	if b.maxBytes > 0 && int64(len(jb)) > b.maxBytes {
+		return nil, &LimitError{Limit: "input size in bytes", Max: b.maxBytes}
+	}
+	if b.utf8 == UTF8Reject {
+		if i := invalidUTF8Offset(jb); i >= 0 {
+			offset := int64(i) + 1
+			line, column := lineColumn(jb, offset)
+			return nil, &SyntaxError{Msg: ErrInvalidUTF8.Error(), Offset: offset, Line: line, Column: column, err: ErrInvalidUTF8}
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(jb))
+	if b.preserveNumbers || b.useInt || b.strict {
+		dec.UseNumber()
+	}
+	v, err := b.build(dec, Path{})
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, wrapSyntaxError(jb, err)
+	}
+	// match json.Unmarshal, which does not allow anything after the value
+	rest := bytes.TrimLeft(jb[dec.InputOffset():], " \t\r\n")
+	if len(rest) > 0 {
+		offset := int64(len(jb)-len(rest)) + 1
+		line, column := lineColumn(jb, offset)
+		return nil, &SyntaxError{
+			Msg:    "invalid character " + strconv.QuoteRune(rune(rest[0])) + " after top-level value",
+			Offset: offset,
+			Line:   line,
+			Column: column,
+		}
+	}
+	return v, nil
+}
+
+func (b *jsonBuilder) build(dec *json.Decoder, path Path) (Value, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		if err == io.EOF && len(path) > 0 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if err := b.node(path, tok); err != nil {
+		return nil, err
+	}
+	switch tt := tok.(type) {
+	case json.Delim:
+		switch tt {
+		case '{':
+			return b.buildObject(dec, path)
+		case '[':
+			return b.buildArray(dec, path)
+		}
+		return nil, fmt.Errorf("unexpected %q at offset %d", tt, dec.InputOffset())
+	}
+	return b.scalar(path, tok)
+}
+
+// scalar converts a token that is not a delimiter.
+func (b *jsonBuilder) scalar(path Path, tok json.Token) (Value, error) {
+	switch tt := tok.(type) {
+	case nil:
+		return nil, nil
+	case bool:
+		return Bool(tt), nil
+	case float64:
+		return Number(tt), nil
+	case json.Number:
+		if b.strict {
+			if err := b.checkNumber(path, string(tt)); err != nil {
+				return nil, err
+			}
+		}
+		if b.useInt {
+			if i, ok := intLiteral(string(tt)); ok {
+				return i, nil
+			}
+		}
+		if b.preserveNumbers {
+			return RawNumber(tt), nil
+		}
+		f, err := tt.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return Number(f), nil
+	case string:
+		return String(tt), nil
+	}
+	return nil, fmt.Errorf("unexpected token %T", tok)
+}
+
+func (b *jsonBuilder) buildObject(dec *json.Decoder, path Path) (Value, error) {
+	out := b.newStruct()
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected token %v at offset %d", tok, dec.InputOffset())
+		}
+		if b.maxString > 0 && len(key) > b.maxString {
+			return nil, &LimitError{Limit: "string length", Max: int64(b.maxString), Path: path.Key(key[:b.maxString] + "...")}
+		}
+		if _, exists := out[key]; exists {
+			dup := DuplicateKey{Path: path.Key(key), Offset: dec.InputOffset()}
+			if b.rejectDuplicates {
+				return nil, DuplicateKeyError{dup}
+			}
+			if b.duplicates != nil {
+				*b.duplicates = append(*b.duplicates, dup)
+			}
+		}
+		v, err := b.build(dec, path.Key(key))
+		if err != nil {
+			return nil, err
+		}
+		out[key] = v
+	}
+	// consume the closing delimiter
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (b *jsonBuilder) buildArray(dec *json.Decoder, path Path) (Value, error) {
+	out := b.newArray()
+	for dec.More() {
+		v, err := b.build(dec, path.Index(len(out)))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
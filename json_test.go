@@ -0,0 +1,177 @@
+package simple
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromJSONDuplicateKeys(t *testing.T) {
+	input := json.RawMessage(`{"role": "user", "nested": [{"a": 1, "a": 2}], "role": "admin"}`)
+
+	v, err := FromJSON(input)
+	require.NoError(t, err)
+	require.Equal(t, String("admin"), v.(Struct)["role"])
+
+	_, err = FromJSON(input, RejectDuplicateKeys())
+	var dupErr DuplicateKeyError
+	require.ErrorAs(t, err, &dupErr)
+	require.Equal(t, Path{"nested", 0, "a"}, dupErr.Path)
+	require.EqualError(t, err, `duplicate key "a" at .nested[0].a (offset 40)`)
+
+	var dups []DuplicateKey
+	v, err = FromJSON(input, CollectDuplicateKeys(&dups))
+	require.NoError(t, err)
+	require.Equal(t, mustFromJSON(t, `{"role": "admin", "nested": [{"a": 2}]}`), v)
+	require.Equal(t, []DuplicateKey{
+		{Path: Path{"nested", 0, "a"}, Offset: 40},
+		{Path: Path{"role"}, Offset: 53},
+	}, dups)
+}
+
+func TestFromJSONWithOptionsMatchesUnmarshal(t *testing.T) {
+	for _, input := range []string{
+		`{"alpha":["beta", 1, {"x": null}], "b": {}}`,
+		`[]`,
+		`"str"`,
+		` 1e3 `,
+	} {
+		expected, err := FromJSON(json.RawMessage(input))
+		require.NoError(t, err)
+		got, err := FromJSON(json.RawMessage(input), RejectDuplicateKeys())
+		require.NoError(t, err)
+		require.Equal(t, expected, got)
+	}
+	for _, input := range []string{`{"a":}`, `[1,]`, `1 2`, ``, `{"a":1`} {
+		_, err := FromJSON(json.RawMessage(input))
+		require.Error(t, err, input)
+		_, err = FromJSON(json.RawMessage(input), RejectDuplicateKeys())
+		require.Error(t, err, input)
+	}
+}
+
+func TestFromJSONSyntaxError(t *testing.T) {
+	for _, tc := range []struct {
+		input  string
+		line   int
+		column int
+		offset int64
+		msg    string
+	}{
+		{
+			input: "{\n  \"a\": 1,\n  \"b\": tru\n}",
+			line:  3, column: 11, offset: 23,
+			msg: "invalid JSON at line 3, column 11: invalid character '\\n' in literal true (expecting 'e')",
+		},
+		{
+			input: "[\"é\", x]",
+			line:  1, column: 7, offset: 8,
+			msg: "invalid JSON at line 1, column 7: invalid character 'x' looking for beginning of value",
+		},
+		{
+			input: "{\"a\": [1, 2",
+			line:  1, column: 11, offset: 11,
+			msg: "invalid JSON at line 1, column 11: unexpected end of JSON input",
+		},
+	} {
+		for _, opts := range [][]JSONOption{nil, {RejectDuplicateKeys()}} {
+			_, err := FromJSON(json.RawMessage(tc.input), opts...)
+			var se *SyntaxError
+			require.ErrorAs(t, err, &se, tc.input)
+			require.Equal(t, tc.line, se.Line, tc.input)
+			require.Equal(t, tc.column, se.Column, tc.input)
+			require.Equal(t, tc.offset, se.Offset, tc.input)
+			require.EqualError(t, err, tc.msg)
+		}
+	}
+
+	_, err := FromJSON(json.RawMessage("{}\n  {}"), RejectDuplicateKeys())
+	require.EqualError(t, err, `invalid JSON at line 2, column 3: invalid character '{' after top-level value`)
+	_, err = FromJSON(json.RawMessage("{}\n  {}"))
+	require.EqualError(t, err, `invalid JSON at line 2, column 3: invalid character '{' after top-level value`)
+}
+
+func TestJSONLimits(t *testing.T) {
+	input := `{"a": [1, 2, {"b": "hello"}], "c": "this is long"}`
+	for _, tc := range []struct {
+		opt JSONOption
+		err string
+	}{
+		{opt: MaxInputBytes(10), err: `JSON input exceeds the maximum input size in bytes of 10`},
+		{opt: MaxDepth(2), err: `JSON input exceeds the maximum depth of 2 at .a[2]`},
+		{opt: MaxNodes(4), err: `JSON input exceeds the maximum number of nodes of 4 at .a[2]`},
+		{opt: MaxStringLength(8), err: `JSON input exceeds the maximum string length of 8 at .c`},
+	} {
+		_, err := FromJSON(json.RawMessage(input), tc.opt)
+		var le *LimitError
+		require.ErrorAs(t, err, &le)
+		require.EqualError(t, err, tc.err)
+
+		_, err = NewJSONDecoder(strings.NewReader(input), tc.opt).Decode()
+		require.EqualError(t, err, tc.err)
+	}
+
+	_, err := FromJSON(json.RawMessage(`{"a long key": 1}`), MaxStringLength(3))
+	require.EqualError(t, err, `JSON input exceeds the maximum string length of 3 at ["a l..."]`)
+
+	for _, opt := range []JSONOption{MaxInputBytes(int64(len(input))), MaxDepth(3), MaxNodes(7), MaxStringLength(12)} {
+		v, err := FromJSON(json.RawMessage(input), opt)
+		require.NoError(t, err)
+		require.Equal(t, mustFromJSON(t, input), v)
+	}
+}
+
+func TestJSONDecoder(t *testing.T) {
+	dec := NewJSONDecoder(strings.NewReader(`{"a": 1} [true] "x"`+"\n"), MaxInputBytes(22), MaxNodes(2))
+	var got []Value
+	for dec.More() {
+		v, err := dec.Decode()
+		require.NoError(t, err)
+		got = append(got, v)
+	}
+	require.Equal(t, []Value{Struct{"a": Number(1)}, Array{Bool(true)}, String("x")}, got)
+	_, err := dec.Decode()
+	require.ErrorIs(t, err, io.EOF)
+
+	dec = NewJSONDecoder(strings.NewReader(`{"a": 1} {"b": 2}`), MaxInputBytes(12))
+	_, err = dec.Decode()
+	require.NoError(t, err)
+	_, err = dec.Decode()
+	require.EqualError(t, err, `JSON input exceeds the maximum input size in bytes of 12`)
+}
+
+func TestFromJSONDecoder(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`{"items": [{"n": 12345678901234567890}, [1.5], null]}`))
+	dec.UseNumber()
+	for _, want := range []json.Token{json.Delim('{'), "items", json.Delim('[')} {
+		tok, err := dec.Token()
+		require.NoError(t, err)
+		require.Equal(t, want, tok)
+	}
+	var got []Value
+	for dec.More() {
+		v, err := FromJSONDecoder(dec, MaxDepth(2))
+		require.NoError(t, err)
+		got = append(got, v)
+	}
+	require.Equal(t, []Value{
+		Struct{"n": Number(12345678901234567890)},
+		Array{Number(1.5)},
+		nil,
+	}, got)
+	tok, err := dec.Token()
+	require.NoError(t, err)
+	require.Equal(t, json.Delim(']'), tok)
+
+	dec = json.NewDecoder(strings.NewReader(`[[1]]`))
+	_, err = FromJSONDecoder(dec, MaxDepth(1))
+	require.EqualError(t, err, `JSON input exceeds the maximum depth of 1 at [0]`)
+
+	_, err = FromJSONDecoder(json.NewDecoder(strings.NewReader(" ")))
+	require.ErrorIs(t, err, io.EOF)
+	_, err = FromJSONDecoder(json.NewDecoder(strings.NewReader(`{"a": `)))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,140 @@
+package simple
+
+import "fmt"
+
+// Placeholders used by [Truncate] in place of composites that are too deep.
+const (
+	TruncatedStruct = String("{...}")
+	TruncatedArray  = String("[...]")
+)
+
+// Truncate returns a copy of v where any non-empty [Struct] or [Array] nested
+// maxDepth or more levels deep is replaced by [TruncatedStruct] or
+// [TruncatedArray]. The root of v is at depth 0, so a maxDepth of 1 keeps the
+// root and its scalar fields but summarizes all of its composite children.
+// This makes arbitrarily deep documents safe to render in logs and UIs.
+func Truncate(v Value, maxDepth int) Value {
+	return truncate(v, 0, maxDepth)
+}
+
+func truncate(v Value, depth, maxDepth int) Value {
+	switch tv := v.(type) {
+	case Struct:
+		if len(tv) == 0 {
+			return Struct{}
+		}
+		if depth >= maxDepth {
+			return TruncatedStruct
+		}
+		out := make(Struct, len(tv))
+		for k, ev := range tv {
+			out[k] = truncate(ev, depth+1, maxDepth)
+		}
+		return out
+	case Array:
+		if len(tv) == 0 {
+			return Array{}
+		}
+		if depth >= maxDepth {
+			return TruncatedArray
+		}
+		out := make(Array, len(tv))
+		for i, ev := range tv {
+			out[i] = truncate(ev, depth+1, maxDepth)
+		}
+		return out
+	}
+	return v
+}
+
+// TruncatedMarker is used by [Limit] to show where content was removed.
+const TruncatedMarker = "…truncated"
+
+// LimitOption sets one of the bounds enforced by [Limit].
+type LimitOption func(*limiter)
+
+type limiter struct {
+	stringLimit int
+	arrayLimit  int
+	nodeLimit   int
+	nodes       int
+}
+
+// StringLimit caps strings to n runes.
+func StringLimit(n int) LimitOption {
+	return func(l *limiter) { l.stringLimit = n }
+}
+
+// ArrayLimit caps arrays to n elements.
+func ArrayLimit(n int) LimitOption {
+	return func(l *limiter) { l.arrayLimit = n }
+}
+
+// NodeLimit caps the total number of values in the tree to n.
+func NodeLimit(n int) LimitOption {
+	return func(l *limiter) { l.nodeLimit = n }
+}
+
+// Limit returns a copy of v that is bounded in size, so that it can be safely
+// attached to logs and traces. Without any options nothing is changed.
+//
+// Where something was cut, a marker starting with [TruncatedMarker] is left
+// behind: strings get the marker appended, arrays get a final String element
+// like "…truncated 2 more elements", and structs that ran out of nodes get a
+// [TruncatedMarker] key holding a String like "…truncated 2 more keys". Struct
+// keys are processed in sorted order. Markers do not count against
+// [NodeLimit]. A Struct that has a key named TruncatedMarker itself loses its
+// value to the marker when some of its keys are dropped.
+func Limit(v Value, opts ...LimitOption) Value {
+	var l limiter
+	for _, o := range opts {
+		o(&l)
+	}
+	return l.limit(v)
+}
+
+func (l *limiter) exhausted() bool {
+	return l.nodeLimit > 0 && l.nodes >= l.nodeLimit
+}
+
+func (l *limiter) limit(v Value) Value {
+	l.nodes++
+	switch tv := v.(type) {
+	case String:
+		if l.stringLimit <= 0 {
+			return v
+		}
+		n := 0
+		for i := range tv {
+			if n == l.stringLimit {
+				return tv[:i] + TruncatedMarker
+			}
+			n++
+		}
+		return v
+	case Struct:
+		out := make(Struct, len(tv))
+		keys := sortedKeys(tv)
+		for i, k := range keys {
+			if l.exhausted() {
+				n := len(keys) - i
+				out[TruncatedMarker] = String(fmt.Sprintf("%s %d more %s", TruncatedMarker, n, plural(n, "key", "keys")))
+				break
+			}
+			out[k] = l.limit(tv[k])
+		}
+		return out
+	case Array:
+		out := make(Array, 0, len(tv))
+		for i, ev := range tv {
+			if l.exhausted() || (l.arrayLimit > 0 && i == l.arrayLimit) {
+				n := len(tv) - i
+				out = append(out, String(fmt.Sprintf("%s %d more %s", TruncatedMarker, n, plural(n, "element", "elements"))))
+				break
+			}
+			out = append(out, l.limit(ev))
+		}
+		return out
+	}
+	return v
+}
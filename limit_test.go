@@ -0,0 +1,60 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncate(t *testing.T) {
+	v := Struct{
+		"id": Number(1),
+		"user": Struct{
+			"name":  String("ann"),
+			"roles": Array{String("admin")},
+			"prefs": Struct{},
+		},
+		"items": Array{Struct{"sku": String("a")}},
+	}
+	require.Equal(t, TruncatedStruct, Truncate(v, 0))
+	require.Equal(t, Struct{
+		"id":    Number(1),
+		"user":  TruncatedStruct,
+		"items": TruncatedArray,
+	}, Truncate(v, 1))
+	require.Equal(t, Struct{
+		"id": Number(1),
+		"user": Struct{
+			"name":  String("ann"),
+			"roles": TruncatedArray,
+			"prefs": Struct{},
+		},
+		"items": Array{TruncatedStruct},
+	}, Truncate(v, 2))
+	require.Equal(t, v, Truncate(v, 3))
+}
+
+func TestLimit(t *testing.T) {
+	v := Struct{
+		"msg":  String("héllo world"),
+		"ids":  Array{Number(1), Number(2), Number(3), Number(4)},
+		"deep": Struct{"a": Number(1), "b": Number(2), "c": Number(3)},
+	}
+	require.Equal(t, v, Limit(v))
+	require.Equal(t, Struct{
+		"msg":  String("héllo…truncated"),
+		"ids":  Array{Number(1), Number(2), String("…truncated 2 more elements")},
+		"deep": Struct{"a": Number(1), "b": Number(2), "c": Number(3)},
+	}, Limit(v, StringLimit(5), ArrayLimit(2)))
+	require.Equal(t, Struct{
+		"deep":       Struct{"a": Number(1), "b": Number(2), "…truncated": String("…truncated 1 more key")},
+		"…truncated": String("…truncated 2 more keys"),
+	}, Limit(v, NodeLimit(4)))
+
+	// a real key named TruncatedMarker is replaced by the marker
+	require.Equal(t, Struct{
+		"a":             Number(1),
+		TruncatedMarker: String("…truncated 2 more keys"),
+	}, Limit(Struct{"a": Number(1), "b": Number(2), TruncatedMarker: String("real")}, NodeLimit(2)))
+	require.Equal(t, Array{String("…truncated 1 more element")}, Limit(Array{Int(1)}, NodeLimit(1)))
+}
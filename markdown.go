@@ -0,0 +1,139 @@
+package simple
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// RenderMarkdown writes v to w as Markdown, for generating documentation and
+// chat-ops messages from captured payloads. Structs become lists of bold
+// keys, Arrays of Structs become tables with a column for every key, and
+// other Arrays become plain lists. Strings are written as escaped text and
+// all other scalars as code. Values nested inside of a table cell are written
+// as compact JSON. Keys are listed in sorted order.
+func RenderMarkdown(w io.Writer, v Value) error {
+	bw := bufio.NewWriter(w)
+	writeMarkdown(bw, "", v)
+	return bw.Flush()
+}
+
+func writeMarkdown(bw *bufio.Writer, indent string, v Value) {
+	if text, ok := markdownInline(v); ok {
+		bw.WriteString(indent + text + "\n")
+		return
+	}
+	switch tv := v.(type) {
+	case Struct:
+		for _, k := range sortedKeys(tv) {
+			label := "**" + markdownEscape(k) + "**:"
+			if text, ok := markdownInline(tv[k]); ok {
+				bw.WriteString(indent + "- " + label + " " + text + "\n")
+				continue
+			}
+			bw.WriteString(indent + "- " + label + "\n")
+			if _, isTable := markdownTable(tv[k]); isTable {
+				bw.WriteString("\n")
+			}
+			writeMarkdown(bw, indent+"  ", tv[k])
+		}
+	case Array:
+		if rows, ok := markdownTable(tv); ok {
+			writeMarkdownTable(bw, indent, rows)
+			return
+		}
+		for _, ev := range tv {
+			if text, ok := markdownInline(ev); ok {
+				bw.WriteString(indent + "- " + text + "\n")
+				continue
+			}
+			bw.WriteString(indent + "-\n")
+			writeMarkdown(bw, indent+"  ", ev)
+		}
+	}
+}
+
+// markdownTable returns the rows of v if it is written as a table.
+func markdownTable(v Value) ([]Struct, bool) {
+	a, ok := v.(Array)
+	if !ok || len(a) == 0 {
+		return nil, false
+	}
+	rows := make([]Struct, len(a))
+	for i, ev := range a {
+		if rows[i], ok = ev.(Struct); !ok {
+			return nil, false
+		}
+	}
+	return rows, true
+}
+
+func writeMarkdownTable(bw *bufio.Writer, indent string, rows []Struct) {
+	columns := map[string]struct{}{}
+	for _, row := range rows {
+		for k := range row {
+			columns[k] = struct{}{}
+		}
+	}
+	keys := sortedKeysOf(columns)
+	line := func(cells func(k string) string) {
+		bw.WriteString(indent + "|")
+		for _, k := range keys {
+			bw.WriteString(" " + cells(k) + " |")
+		}
+		bw.WriteString("\n")
+	}
+	line(markdownEscape)
+	line(func(string) string { return "---" })
+	for _, row := range rows {
+		line(func(k string) string {
+			ev, ok := row[k]
+			if !ok {
+				return ""
+			}
+			if text, ok := markdownInline(ev); ok {
+				return text
+			}
+			return markdownCode(ev.String())
+		})
+	}
+}
+
+// markdownInline renders scalars and empty composites, which fit on a line.
+func markdownInline(v Value) (string, bool) {
+	switch tv := v.(type) {
+	case String:
+		if tv == "" {
+			return markdownCode(`""`), true
+		}
+		return markdownEscape(string(tv)), true
+	case Struct:
+		if len(tv) > 0 {
+			return "", false
+		}
+	case Array:
+		if len(tv) > 0 {
+			return "", false
+		}
+	}
+	return markdownCode(scalarText(v)), true
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+	"\r\n", "<br>", "\n", "<br>", "\r", "<br>",
+)
+
+func markdownEscape(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// markdownCode writes s as a code span that can be used in a table.
+func markdownCode(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+	return "`" + s + "`"
+}
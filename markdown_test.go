@@ -0,0 +1,48 @@
+package simple
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	v := Struct{
+		"name":  String("ann_b"),
+		"empty": String(""),
+		"count": Int(2),
+		"tags":  Array{String("a"), Array{Bool(true)}},
+		"users": Array{
+			Struct{"id": Int(1), "name": String("x|y")},
+			Struct{"id": Int(2), "roles": Array{String("admin")}},
+		},
+		"meta": Struct{"note": String("line 1\nline 2"), "none": nil},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, RenderMarkdown(&buf, v))
+	require.Equal(t, "- **count**: `2`\n"+
+		"- **empty**: `\"\"`\n"+
+		"- **meta**:\n"+
+		"  - **none**: `null`\n"+
+		"  - **note**: line 1<br>line 2\n"+
+		"- **name**: ann\\_b\n"+
+		"- **tags**:\n"+
+		"  - a\n"+
+		"  -\n"+
+		"    - `true`\n"+
+		"- **users**:\n"+
+		"\n"+
+		"  | id | name | roles |\n"+
+		"  | --- | --- | --- |\n"+
+		"  | `1` | x\\|y |  |\n"+
+		"  | `2` |  | `[\"admin\"]` |\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, RenderMarkdown(&buf, Array{Struct{"a": String("`")}}))
+	require.Equal(t, "| a |\n| --- |\n| \\` |\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, RenderMarkdown(&buf, Number(1.5)))
+	require.Equal(t, "`1.5`\n", buf.String())
+}
@@ -0,0 +1,45 @@
+package simple
+
+import "bytes"
+
+// marshalJSON encodes v like json.Marshal would: with sorted keys, HTML
+// characters escaped and nil Structs and Arrays as null. NaN and infinite
+// numbers and invalid raw numbers are an error.
+func marshalJSON(v Value) ([]byte, error) {
+	var buf bytes.Buffer
+	e := Encoder{escapeHTML: true, empty: NilAsNull}
+	if err := e.encode(&buf, Path{}, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// stringJSON encodes v like marshalJSON, except that everything that cannot
+// be encoded is written as null, like JavaScript's JSON.stringify does, so it
+// cannot fail.
+func stringJSON(v Value) string {
+	var buf bytes.Buffer
+	e := Encoder{escapeHTML: true, empty: NilAsNull, nonFinite: NonFiniteNull, lenient: true}
+	// lenient encoders do not fail
+	_ = e.encode(&buf, Path{}, v)
+	return buf.String()
+}
+
+// MarshalJSON implements [json.Marshaler]. Unlike String, it fails if s
+// contains a NaN or infinite [Number] or an invalid [RawNumber].
+func (s Struct) MarshalJSON() ([]byte, error) { return marshalJSON(s) }
+
+// MarshalJSON implements [json.Marshaler], see [Struct.MarshalJSON].
+func (a Array) MarshalJSON() ([]byte, error) { return marshalJSON(a) }
+
+// MarshalJSON implements [json.Marshaler], see [Struct.MarshalJSON].
+func (n Number) MarshalJSON() ([]byte, error) { return marshalJSON(n) }
+
+// MarshalJSON implements [json.Marshaler].
+func (i Int) MarshalJSON() ([]byte, error) { return marshalJSON(i) }
+
+// MarshalJSON implements [json.Marshaler].
+func (s String) MarshalJSON() ([]byte, error) { return marshalJSON(s) }
+
+// MarshalJSON implements [json.Marshaler].
+func (b Bool) MarshalJSON() ([]byte, error) { return marshalJSON(b) }
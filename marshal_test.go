@@ -0,0 +1,38 @@
+package simple
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	v := Struct{
+		"b":    Array{Int(1), Number(1.5), Bool(false), nil, String("<&>")},
+		"a":    Struct(nil),
+		"list": Array(nil),
+		"raw":  RawNumber("1.0"),
+	}
+	jb, err := json.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, `{"a":null,"b":[1,1.5,false,null,"\u003c\u0026\u003e"],"list":null,"raw":1.0}`, string(jb))
+	require.Equal(t, string(jb), v.String())
+
+	bad := Struct{"n": Array{Number(math.NaN())}, "raw": RawNumber("x")}
+	_, err = json.Marshal(bad)
+	require.Error(t, err)
+	_, err = bad.MarshalJSON()
+	require.EqualError(t, err, "cannot encode value at .n[0]: NaN is not a valid JSON number")
+	require.Equal(t, `{"n":[null],"raw":null}`, bad.String())
+	require.Equal(t, "null", Number(math.Inf(1)).String())
+
+	for _, v := range []Value{Int(-3), Number(1e21), String("é "), Bool(true)} {
+		want, err := json.Marshal(ToAny(v))
+		require.NoError(t, err)
+		got, err := json.Marshal(v)
+		require.NoError(t, err)
+		require.Equal(t, string(want), string(got))
+	}
+}
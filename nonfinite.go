@@ -0,0 +1,61 @@
+package simple
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// NonFinitePolicy decides what happens to NaN and infinite numbers, which are
+// valid float64 values but cannot be written as JSON.
+type NonFinitePolicy int
+
+const (
+	// NonFiniteError fails. It is the default.
+	NonFiniteError NonFinitePolicy = iota
+	// NonFiniteNull replaces the number with null, like JavaScript's
+	// JSON.stringify does.
+	NonFiniteNull
+	// NonFiniteString replaces the number with one of the strings "NaN",
+	// "Infinity" or "-Infinity".
+	NonFiniteString
+)
+
+// ConvertNonFinite sets how [FromValue] deals with NaN and infinite numbers.
+func ConvertNonFinite(p NonFinitePolicy) FromValueOption {
+	return func(c *converter) { c.nonFinite = p }
+}
+
+// SetNonFinite sets how NaN and infinite numbers are written.
+func (e *Encoder) SetNonFinite(p NonFinitePolicy) {
+	e.nonFinite = p
+}
+
+// number converts f according to the non-finite policy of the converter.
+func (c *converter) number(f float64, path []string) (Value, error) {
+	if isFinite(f) {
+		return Number(f), nil
+	}
+	switch c.nonFinite {
+	case NonFiniteNull:
+		return nil, nil
+	case NonFiniteString:
+		return String(nonFiniteName(f)), nil
+	}
+	return nil, fromValueError{path: slices.Clone(path), problem: fmt.Sprintf("%s is not a valid number", nonFiniteName(f))}
+}
+
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// nonFiniteName returns the JavaScript name of a non-finite number.
+func nonFiniteName(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case f > 0:
+		return "Infinity"
+	}
+	return "-Infinity"
+}
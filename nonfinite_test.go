@@ -0,0 +1,41 @@
+package simple
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonFinite(t *testing.T) {
+	input := map[string]any{"nan": math.NaN(), "inf": []float32{float32(math.Inf(1)), 1}, "ninf": math.Inf(-1)}
+
+	_, err := FromValue(input)
+	require.Error(t, err)
+	_, err = FromValue(map[string]any{"a": []float64{1, math.Inf(-1)}})
+	require.EqualError(t, err, "cannot convert value at .a[1]: -Infinity is not a valid number")
+
+	v, err := FromValue(input, ConvertNonFinite(NonFiniteNull))
+	require.NoError(t, err)
+	require.Equal(t, Struct{"nan": nil, "inf": Array{nil, Number(1)}, "ninf": nil}, v)
+
+	v, err = FromValue(input, ConvertNonFinite(NonFiniteString))
+	require.NoError(t, err)
+	require.Equal(t, Struct{"nan": String("NaN"), "inf": Array{String("Infinity"), Number(1)}, "ninf": String("-Infinity")}, v)
+
+	v = Struct{"nan": Number(math.NaN()), "list": Array{Number(math.Inf(1)), Number(2)}}
+	require.NotPanics(t, func() {
+		require.Equal(t, `{"list":[null,2],"nan":null}`, v.String())
+		require.Equal(t, `null`, Number(math.Inf(-1)).String())
+	})
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	require.EqualError(t, enc.Encode(v), "cannot encode value at .list[0]: +Inf is not a valid JSON number")
+	enc.SetNonFinite(NonFiniteNull)
+	require.NoError(t, enc.Encode(v))
+	enc.SetNonFinite(NonFiniteString)
+	require.NoError(t, enc.Encode(v))
+	require.Equal(t, `{"list":[null,2],"nan":null}`+"\n"+`{"list":["Infinity",2],"nan":"NaN"}`+"\n", buf.String())
+}
@@ -0,0 +1,115 @@
+package simple
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RawNumber is a number that keeps the exact text it was written with in
+// JSON, like "0.1000000000000000055" or "12345678901234567890". It is produced
+// by [FromJSON] with the [PreserveNumbers] option and written back out
+// verbatim, so a proxy can inspect a payload without altering it. Everywhere
+// else a RawNumber behaves like the [Number] it represents, for example
+// RawNumber("1.0") and Number(1) are considered equal.
+type RawNumber string
+
+func (RawNumber) xIsValue() {}
+
+// String implements [Value] and returns the number exactly as it was written.
+// Like in a Struct or Array, a RawNumber that is not a valid JSON number is
+// rendered as null.
+func (r RawNumber) String() string {
+	return stringJSON(r)
+}
+
+// Float64 returns the number as a float64. Numbers that are too large for a
+// float64 result in an error.
+func (r RawNumber) Float64() (float64, error) {
+	if !isJSONNumber(string(r)) {
+		return 0, fmt.Errorf("%q is not a valid JSON number", string(r))
+	}
+	return strconv.ParseFloat(string(r), 64)
+}
+
+// MarshalJSON implements [json.Marshaler] and writes the number verbatim.
+func (r RawNumber) MarshalJSON() ([]byte, error) {
+	if !isJSONNumber(string(r)) {
+		return nil, fmt.Errorf("%q is not a valid JSON number", string(r))
+	}
+	return []byte(r), nil
+}
+
+// PreserveNumbers makes [FromJSON] and [JSONDecoder] produce a [RawNumber]
+// for every number instead of a [Number]. For [FromJSONDecoder] this only
+// works if UseNumber was called on the json.Decoder, otherwise the text of the
+// number is already lost.
+func PreserveNumbers() JSONOption {
+	return func(b *jsonBuilder) { b.preserveNumbers = true }
+}
+
+// isJSONNumber reports whether s is a JSON number literal.
+func isJSONNumber(s string) bool {
+	if s == "" || (s[0] != '-' && (s[0] < '0' || s[0] > '9')) {
+		return false
+	}
+	return json.Valid([]byte(s))
+}
+
+// numberValue returns the numeric value of a [Number], an [Int] or a valid
+// [RawNumber]. A RawNumber that is too large for a float64 is reported as
+// infinite, and large Ints are rounded.
+func numberValue(v Value) (float64, bool) {
+	switch tv := v.(type) {
+	case Number:
+		return float64(tv), true
+	case Int:
+		return float64(tv), true
+	case RawNumber:
+		if !isJSONNumber(string(tv)) {
+			return 0, false
+		}
+		// the only possible error is a range error, which still results in
+		// the closest float64 (infinity)
+		f, _ := strconv.ParseFloat(string(tv), 64)
+		return f, true
+	}
+	return 0, false
+}
+
+// Int is an integer that fits in an int64. It is produced by [FromJSON] with
+// the [UseInt] option for numbers written without a fraction or exponent, so
+// that code can tell integers apart from other numbers. Everywhere else an
+// Int behaves like the [Number] it represents, for example Int(1) and
+// Number(1) are considered equal, and an Int is a "number" to [Match].
+type Int int64
+
+func (Int) xIsValue() {}
+
+// String implements [Value]
+func (i Int) String() string {
+	return strconv.FormatInt(int64(i), 10)
+}
+
+// UseInt makes [FromJSON] and [JSONDecoder] produce an [Int] for numbers
+// written without a fraction or exponent that fit in an int64, and a [Number]
+// for all others. When combined with [PreserveNumbers], only the numbers that
+// are not an Int become a [RawNumber]. For [FromJSONDecoder] this only works
+// if UseNumber was called on the json.Decoder.
+func UseInt() JSONOption {
+	return func(b *jsonBuilder) { b.useInt = true }
+}
+
+// intLiteral returns the Int written as s, if s is an integer literal that fits
+// in an int64.
+func intLiteral(s string) (Int, bool) {
+	if strings.ContainsAny(s, ".eE") {
+		return 0, false
+	}
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return Int(i), true
+}
@@ -0,0 +1,119 @@
+package simple
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawNumber(t *testing.T) {
+	input := `{"big":12345678901234567890,"list":[0.1000000000000000055,1.0,-2e3]}`
+	v, err := FromJSON(json.RawMessage(input), PreserveNumbers())
+	require.NoError(t, err)
+	require.Equal(t, Struct{
+		"big":  RawNumber("12345678901234567890"),
+		"list": Array{RawNumber("0.1000000000000000055"), RawNumber("1.0"), RawNumber("-2e3")},
+	}, v)
+
+	// written back verbatim
+	require.Equal(t, input, v.String())
+	var buf bytes.Buffer
+	require.NoError(t, NewEncoder(&buf).Encode(v))
+	require.Equal(t, input+"\n", buf.String())
+	jb, err := json.Marshal(ToAny(v))
+	require.NoError(t, err)
+	require.Equal(t, input, string(jb))
+
+	dec := NewJSONDecoder(strings.NewReader(`[1.50]`), PreserveNumbers())
+	v, err = dec.Decode()
+	require.NoError(t, err)
+	require.Equal(t, Array{RawNumber("1.50")}, v)
+
+	jd := json.NewDecoder(strings.NewReader(`1.50`))
+	jd.UseNumber()
+	v, err = FromJSONDecoder(jd, PreserveNumbers())
+	require.NoError(t, err)
+	require.Equal(t, RawNumber("1.50"), v)
+
+	// otherwise they behave like numbers
+	require.True(t, equal(RawNumber("1.0"), Number(1)))
+	require.Equal(t, Hash(Number(1)), Hash(RawNumber("1.0")))
+	canonical, err := CanonicalJSON(Array{RawNumber("1.0"), RawNumber("-2e3")})
+	require.NoError(t, err)
+	require.Equal(t, `[1,-2000]`, string(canonical))
+
+	i, err := AsInt64(RawNumber("9007199254740993"))
+	require.NoError(t, err)
+	require.Equal(t, int64(9007199254740993), i)
+	s, err := AsString(RawNumber("1.0"))
+	require.NoError(t, err)
+	require.Equal(t, "1.0", s)
+
+	var target struct {
+		Big uint64
+		F   float64
+	}
+	require.NoError(t, Decode(Struct{"Big": RawNumber("12345678901234567890"), "F": RawNumber("0.5")}, &target))
+	require.Equal(t, uint64(12345678901234567890), target.Big)
+	require.Equal(t, 0.5, target.F)
+
+	require.Empty(t, Validate(RawNumber("3.0"), Struct{"type": String("integer"), "maximum": Number(3)}))
+
+	// bogus raw numbers never make it into JSON
+	require.EqualError(t, NewEncoder(&buf).Encode(RawNumber("1x")), `cannot encode value at : "1x" is not a valid JSON number`)
+	require.Equal(t, `[null]`, Array{RawNumber("NaN")}.String())
+	require.Equal(t, "null", RawNumber("NaN").String())
+	require.Equal(t, "-2e3", RawNumber("-2e3").String())
+}
+
+func TestInt(t *testing.T) {
+	input := `{"i":9007199254740993,"f":1.0,"e":1e2,"big":12345678901234567890,"neg":-7}`
+	v, err := FromJSON(json.RawMessage(input), UseInt())
+	require.NoError(t, err)
+	require.Equal(t, Struct{
+		"i":   Int(9007199254740993),
+		"f":   Number(1),
+		"e":   Number(100),
+		"big": Number(12345678901234567890),
+		"neg": Int(-7),
+	}, v)
+	require.Equal(t, `{"big":12345678901234567000,"e":100,"f":1,"i":9007199254740993,"neg":-7}`, v.String())
+
+	v, err = FromJSON(json.RawMessage(input), UseInt(), PreserveNumbers())
+	require.NoError(t, err)
+	require.Equal(t, Struct{
+		"i":   Int(9007199254740993),
+		"f":   RawNumber("1.0"),
+		"e":   RawNumber("1e2"),
+		"big": RawNumber("12345678901234567890"),
+		"neg": Int(-7),
+	}, v)
+
+	dec := NewJSONDecoder(strings.NewReader(`3 3.5`), UseInt())
+	for _, want := range []Value{Int(3), Number(3.5)} {
+		v, err = dec.Decode()
+		require.NoError(t, err)
+		require.Equal(t, want, v)
+	}
+
+	require.True(t, equal(Int(1), Number(1)))
+	require.False(t, equal(Int(9007199254740993), Int(9007199254740992)))
+	require.Equal(t, Hash(Number(1)), Hash(Int(1)))
+	require.Empty(t, Match(Int(1), Num))
+	require.Equal(t, int64(9007199254740993), ToAny(Int(9007199254740993)))
+
+	i, err := AsInt64(Int(9007199254740993))
+	require.NoError(t, err)
+	require.Equal(t, int64(9007199254740993), i)
+
+	var target struct{ I int64 }
+	require.NoError(t, Decode(Struct{"I": Int(9007199254740993)}, &target))
+	require.Equal(t, int64(9007199254740993), target.I)
+
+	var buf bytes.Buffer
+	require.NoError(t, NewEncoder(&buf).Encode(Array{Int(-9007199254740993)}))
+	require.Equal(t, "[-9007199254740993]\n", buf.String())
+}
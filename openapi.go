@@ -0,0 +1,73 @@
+package simple
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ValidateOpenAPI checks a request or response body against an OpenAPI 3.x
+// schema object. It supports everything [Validate] does, plus:
+//
+//   - "nullable": true from OpenAPI 3.0, which allows null in addition to the
+//     declared type.
+//   - "format" is enforced for the common string formats: date-time, date,
+//     time, email, uuid, uri, hostname, ipv4, ipv6 and byte. Other formats are
+//     ignored.
+//
+// As with [Validate], $ref is not resolved, so references should be inlined
+// before validating.
+func ValidateOpenAPI(v Value, schema Value) []ValidationError {
+	sv := schemaValidator{openapi: true}
+	sv.validate(Path{}, v, schema)
+	return sv.errs
+}
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnamePattern = regexp.MustCompile(`^(?i:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?)(?:\.(?i:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?))*$`)
+	base64Pattern   = regexp.MustCompile(`^(?:[A-Za-z0-9+/]{4})*(?:[A-Za-z0-9+/]{2}==|[A-Za-z0-9+/]{3}=)?$`)
+)
+
+// stringFormats checks the values of the "format" keyword.
+var stringFormats = map[string]func(string) bool{
+	"date-time": func(s string) bool {
+		_, err := time.Parse(time.RFC3339Nano, s)
+		return err == nil
+	},
+	"date": func(s string) bool {
+		_, err := time.Parse(time.DateOnly, s)
+		return err == nil
+	},
+	"time": func(s string) bool {
+		for _, layout := range []string{"15:04:05Z07:00", "15:04:05.999999999Z07:00"} {
+			if _, err := time.Parse(layout, s); err == nil {
+				return true
+			}
+		}
+		return false
+	},
+	"email": func(s string) bool {
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	},
+	"uuid": uuidPattern.MatchString,
+	"uri": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	},
+	"hostname": func(s string) bool {
+		return len(s) <= 253 && hostnamePattern.MatchString(s)
+	},
+	"ipv4": func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	},
+	"ipv6": func(s string) bool {
+		return net.ParseIP(s) != nil && strings.Contains(s, ":")
+	},
+	"byte": base64Pattern.MatchString,
+}
@@ -0,0 +1,63 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateOpenAPI(t *testing.T) {
+	schema := mustFromJSON(t, `{
+		"type": "object",
+		"required": ["id", "pet"],
+		"properties": {
+			"id": {"type": "string", "format": "uuid"},
+			"email": {"type": "string", "format": "email", "nullable": true},
+			"created": {"type": "string", "format": "date-time"},
+			"ip": {"type": "string", "format": "ipv4"},
+			"pet": {
+				"oneOf": [
+					{"type": "object", "required": ["bark"], "properties": {"bark": {"type": "boolean"}}},
+					{"type": "object", "required": ["meow"], "properties": {"meow": {"type": "boolean"}}}
+				]
+			},
+			"size": {"anyOf": [{"type": "integer"}, {"type": "string", "enum": ["small", "large"]}]}
+		}
+	}`)
+
+	valid := mustFromJSON(t, `{
+		"id": "0b6f1c6e-8f0e-4d4b-9f6e-2b7a3c1e5d9a",
+		"email": null,
+		"created": "2024-02-29T12:00:00Z",
+		"ip": "10.0.0.1",
+		"pet": {"bark": true},
+		"size": "small"
+	}`)
+	require.Empty(t, ValidateOpenAPI(valid, schema))
+	// plain JSON Schema does not know about nullable, nor assert formats
+	require.Len(t, Validate(valid, schema), 1)
+
+	errs := ValidateOpenAPI(mustFromJSON(t, `{
+		"id": "nope",
+		"email": "not an email",
+		"created": "yesterday",
+		"ip": "::1",
+		"pet": {"bark": true, "meow": true},
+		"size": "medium"
+	}`), schema)
+	var msgs []string
+	for _, e := range errs {
+		msgs = append(msgs, e.Error())
+	}
+	require.Equal(t, []string{
+		`.created: string is not a valid date-time`,
+		`.email: string is not a valid email`,
+		`.id: string is not a valid uuid`,
+		`.ip: string is not a valid ipv4`,
+		`.pet: value matches 2 of the schemas, expected exactly 1`,
+		`.size: value does not match any of the allowed schemas`,
+	}, msgs)
+
+	require.Len(t, Validate(Number(1), mustFromJSON(t, `{"not": {"type": "number"}}`)), 1)
+	require.Len(t, Validate(Number(1), mustFromJSON(t, `{"allOf": [{"minimum": 2}, {"maximum": 0}]}`)), 2)
+}
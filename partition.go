@@ -0,0 +1,57 @@
+package simple
+
+// Partition splits v in two, for example to separate public from internal
+// fields before responding to clients. pred is called with every value,
+// starting at the root. When it returns true the value, including everything
+// inside of it, goes to match. When it returns false for a [Struct] or an
+// [Array], its children are partitioned the same way, and every other value
+// goes to rest.
+//
+// Both results keep the Structs and Arrays that lead to the values they
+// contain, and leave out the ones that would only be there because of the
+// other result. Like with [Filter], array elements shift down to fill the
+// gaps, and the paths given to pred are always the paths in the original v. A
+// result that does not contain anything is nil.
+func Partition(v Value, pred func(path Path, v Value) bool) (match, rest Value) {
+	match, _, rest, _ = partition(Path{}, v, pred)
+	return match, rest
+}
+
+func partition(path Path, v Value, pred func(Path, Value) bool) (match Value, hasMatch bool, rest Value, hasRest bool) {
+	if pred(path, v) {
+		return v, true, nil, false
+	}
+	switch tv := v.(type) {
+	case Struct:
+		if len(tv) == 0 {
+			break
+		}
+		m, r := Struct{}, Struct{}
+		for k, ev := range tv {
+			em, okM, er, okR := partition(path.Key(k), ev, pred)
+			if okM {
+				m[k] = em
+			}
+			if okR {
+				r[k] = er
+			}
+		}
+		return m, len(m) > 0, r, len(r) > 0
+	case Array:
+		if len(tv) == 0 {
+			break
+		}
+		m, r := Array{}, Array{}
+		for i, ev := range tv {
+			em, okM, er, okR := partition(path.Index(i), ev, pred)
+			if okM {
+				m = append(m, em)
+			}
+			if okR {
+				r = append(r, er)
+			}
+		}
+		return m, len(m) > 0, r, len(r) > 0
+	}
+	return nil, false, v, true
+}
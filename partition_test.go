@@ -0,0 +1,49 @@
+package simple
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartition(t *testing.T) {
+	internal := func(path Path, v Value) bool {
+		if len(path) == 0 {
+			return false
+		}
+		k, ok := path[len(path)-1].(string)
+		return ok && strings.HasPrefix(k, "_")
+	}
+	match, rest := Partition(Struct{
+		"id":    Int(1),
+		"_rev":  String("3"),
+		"empty": Struct{},
+		"owner": Struct{"_token": String("x")},
+		"items": Array{
+			Struct{"name": String("a"), "_cost": Int(2)},
+			Struct{"_all": Bool(true)},
+		},
+	}, internal)
+	require.Equal(t, Struct{
+		"_rev":  String("3"),
+		"owner": Struct{"_token": String("x")},
+		"items": Array{
+			Struct{"_cost": Int(2)},
+			Struct{"_all": Bool(true)},
+		},
+	}, match)
+	require.Equal(t, Struct{
+		"id":    Int(1),
+		"empty": Struct{},
+		"items": Array{Struct{"name": String("a")}},
+	}, rest)
+
+	match, rest = Partition(String("x"), func(Path, Value) bool { return false })
+	require.Nil(t, match)
+	require.Equal(t, String("x"), rest)
+
+	match, rest = Partition(Array{Int(1)}, func(Path, Value) bool { return true })
+	require.Equal(t, Array{Int(1)}, match)
+	require.Nil(t, rest)
+}
@@ -0,0 +1,233 @@
+package simple
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Operations of a [Patch], as defined by RFC 6902.
+const (
+	PatchAdd     = "add"
+	PatchRemove  = "remove"
+	PatchReplace = "replace"
+	PatchMove    = "move"
+	PatchCopy    = "copy"
+	PatchTest    = "test"
+)
+
+// Patch is a single operation of a JSON Patch (RFC 6902), with paths given as
+// a [Path] instead of a JSON Pointer. An index one past the end of an Array
+// takes the place of "-", to add a value to its end.
+type Patch struct {
+	Op    string
+	Path  Path
+	From  Path  // for PatchMove and PatchCopy
+	Value Value // for PatchAdd, PatchReplace and PatchTest
+}
+
+type patchError struct {
+	index   int
+	op      string
+	path    Path
+	problem string
+}
+
+func (p patchError) Error() string {
+	return fmt.Sprintf("cannot apply patch %d (%s at %s): %s", p.index, p.op, p.path, p.problem)
+}
+
+func (p patchError) Pointer() string { return p.path.Pointer() }
+
+// ApplyPatch applies patches to v in order, as RFC 6902 describes, and returns
+// the result. It is all or nothing: if any of the patches fails, an error is
+// returned and so is v. v itself is never modified, and the result shares
+// everything that was not changed with it, like [Immutable] does.
+func ApplyPatch(v Value, patches ...Patch) (Value, error) {
+	return foldPatches(v, patches, nil)
+}
+
+// foldPatches applies patches to v like ApplyPatch, calling step, if it is
+// not nil, with the state after each of them.
+func foldPatches(v Value, patches []Patch, step func(i int, state Value)) (Value, error) {
+	out := v
+	for i, p := range patches {
+		next, problem := applyPatch(out, p)
+		if problem != "" {
+			return v, patchError{index: i, op: p.Op, path: p.Path, problem: problem}
+		}
+		out = next
+		if step != nil {
+			step(i, out)
+		}
+	}
+	return out, nil
+}
+
+func applyPatch(v Value, p Patch) (Value, string) {
+	switch p.Op {
+	case PatchAdd:
+		return patchAt(v, p.Path, func(parent Value, e any) (Value, string) { return addAt(parent, e, p.Value) })
+	case PatchRemove:
+		return patchAt(v, p.Path, removeAt)
+	case PatchReplace:
+		if _, ok := Lookup(v, p.Path); !ok {
+			return nil, "path does not exist"
+		}
+		return setPath(v, p.Path, p.Value), ""
+	case PatchMove, PatchCopy:
+		from, ok := Lookup(v, p.From)
+		if !ok {
+			return nil, fmt.Sprintf("from path %s does not exist", p.From)
+		}
+		if p.Op == PatchMove {
+			if len(p.From) < len(p.Path) && slices.Equal(p.From, p.Path[:len(p.From)]) {
+				return nil, "cannot move a value into itself"
+			}
+			v, _ = patchAt(v, p.From, removeAt)
+		}
+		return patchAt(v, p.Path, func(parent Value, e any) (Value, string) { return addAt(parent, e, from) })
+	case PatchTest:
+		cur, ok := Lookup(v, p.Path)
+		if !ok {
+			return nil, "path does not exist"
+		}
+		if !equal(cur, p.Value) {
+			return nil, fmt.Sprintf("test failed, value is %s", shortString(cur))
+		}
+		return v, ""
+	}
+	return nil, fmt.Sprintf("unknown operation %q", p.Op)
+}
+
+// patchAt calls leaf with the container of the last element of p and that
+// element, and returns a copy of v where the container is replaced by the
+// result. Only the Structs and Arrays along p are copied. An empty p is the
+// root, for which leaf is called with a nil element.
+func patchAt(v Value, p Path, leaf func(parent Value, e any) (Value, string)) (Value, string) {
+	switch len(p) {
+	case 0:
+		return leaf(nil, nil)
+	case 1:
+		return leaf(v, p[0])
+	}
+	switch e := p[0].(type) {
+	case string:
+		s, ok := v.(Struct)
+		if !ok {
+			return nil, "path does not exist"
+		}
+		child, ok := s[e]
+		if !ok {
+			return nil, "path does not exist"
+		}
+		nv, problem := patchAt(child, p[1:], leaf)
+		if problem != "" {
+			return nil, problem
+		}
+		out := maps.Clone(s)
+		out[e] = nv
+		return out, ""
+	case int:
+		a, ok := v.(Array)
+		if !ok || e < 0 || e >= len(a) {
+			return nil, "path does not exist"
+		}
+		nv, problem := patchAt(a[e], p[1:], leaf)
+		if problem != "" {
+			return nil, problem
+		}
+		out := slices.Clone(a)
+		out[e] = nv
+		return out, ""
+	}
+	return nil, fmt.Sprintf("invalid path element %#v", p[0])
+}
+
+func addAt(parent Value, e any, v Value) (Value, string) {
+	switch te := e.(type) {
+	case nil:
+		return v, ""
+	case string:
+		s, ok := parent.(Struct)
+		if !ok {
+			return nil, fmt.Sprintf("cannot add a key to %s", kindName(parent))
+		}
+		out := make(Struct, len(s)+1)
+		maps.Copy(out, s)
+		out[te] = v
+		return out, ""
+	case int:
+		a, ok := parent.(Array)
+		if !ok {
+			return nil, fmt.Sprintf("cannot add an element to %s", kindName(parent))
+		}
+		if te < 0 || te > len(a) {
+			return nil, fmt.Sprintf("index %d is out of range", te)
+		}
+		return slices.Insert(slices.Clip(a), te, v), ""
+	}
+	return nil, fmt.Sprintf("invalid path element %#v", e)
+}
+
+func removeAt(parent Value, e any) (Value, string) {
+	switch te := e.(type) {
+	case nil:
+		return nil, ""
+	case string:
+		s, ok := parent.(Struct)
+		if !ok {
+			return nil, "path does not exist"
+		}
+		if _, ok := s[te]; !ok {
+			return nil, "path does not exist"
+		}
+		out := maps.Clone(s)
+		delete(out, te)
+		return out, ""
+	case int:
+		a, ok := parent.(Array)
+		if !ok || te < 0 || te >= len(a) {
+			return nil, "path does not exist"
+		}
+		return slices.Delete(slices.Clone(a), te, te+1), ""
+	}
+	return nil, fmt.Sprintf("invalid path element %#v", e)
+}
+
+// DiffPatches returns the patches that turn a into b when given to
+// [ApplyPatch], made from the changes found by [Diff].
+func DiffPatches(a, b Value) []Patch {
+	return changePatches(Diff(a, b))
+}
+
+// changePatches turns changes found by [Diff] into patches.
+func changePatches(changes []Change) []Patch {
+	patches := make([]Patch, 0, len(changes))
+	for i := 0; i < len(changes); i++ {
+		c := changes[i]
+		switch c.Kind {
+		case Added:
+			patches = append(patches, Patch{Op: PatchAdd, Path: c.Path, Value: c.New})
+		case Modified:
+			patches = append(patches, Patch{Op: PatchReplace, Path: c.Path, Value: c.New})
+		case Removed:
+			if _, ok := c.Path[len(c.Path)-1].(int); !ok {
+				patches = append(patches, Patch{Op: PatchRemove, Path: c.Path})
+				continue
+			}
+			// elements removed from the end of an Array come in increasing
+			// order, they have to be removed from the last one down
+			parent := c.Path[:len(c.Path)-1]
+			j := i
+			for j+1 < len(changes) && changes[j+1].Kind == Removed && slices.Equal(changes[j+1].Path[:len(changes[j+1].Path)-1], parent) {
+				j++
+			}
+			for k := j; k >= i; k-- {
+				patches = append(patches, Patch{Op: PatchRemove, Path: changes[k].Path})
+			}
+			i = j
+		}
+	}
+	return patches
+}
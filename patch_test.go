@@ -0,0 +1,72 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPatch(t *testing.T) {
+	v := Struct{
+		"name": String("ann"),
+		"tags": Array{String("a"), String("b")},
+		"meta": Struct{"x": Int(1)},
+	}
+	orig := v.String()
+	out, err := ApplyPatch(v,
+		Patch{Op: PatchTest, Path: MustParsePath(".name"), Value: String("ann")},
+		Patch{Op: PatchAdd, Path: MustParsePath(".tags[1]"), Value: String("z")},
+		Patch{Op: PatchAdd, Path: MustParsePath(".tags[3]"), Value: String("end")},
+		Patch{Op: PatchRemove, Path: MustParsePath(".tags[0]")},
+		Patch{Op: PatchReplace, Path: MustParsePath(".name"), Value: String("bob")},
+		Patch{Op: PatchMove, From: MustParsePath(".meta.x"), Path: MustParsePath(".x")},
+		Patch{Op: PatchCopy, From: MustParsePath(".x"), Path: MustParsePath(".meta.y")},
+	)
+	require.NoError(t, err)
+	require.Equal(t, `{"meta":{"y":1},"name":"bob","tags":["z","b","end"],"x":1}`, out.String())
+	require.Equal(t, orig, v.String())
+
+	for _, tc := range []struct {
+		patch Patch
+		err   string
+	}{
+		{Patch{Op: PatchRemove, Path: MustParsePath(".missing")}, "cannot apply patch 0 (remove at .missing): path does not exist"},
+		{Patch{Op: PatchReplace, Path: MustParsePath(".tags[2]")}, "cannot apply patch 0 (replace at .tags[2]): path does not exist"},
+		{Patch{Op: PatchAdd, Path: MustParsePath(".tags[3]")}, "cannot apply patch 0 (add at .tags[3]): index 3 is out of range"},
+		{Patch{Op: PatchAdd, Path: MustParsePath(".name.x")}, "cannot apply patch 0 (add at .name.x): cannot add a key to string"},
+		{Patch{Op: PatchAdd, Path: MustParsePath(".a.b")}, "cannot apply patch 0 (add at .a.b): path does not exist"},
+		{Patch{Op: PatchMove, From: MustParsePath(".meta"), Path: MustParsePath(".meta.x.y")}, "cannot apply patch 0 (move at .meta.x.y): cannot move a value into itself"},
+		{Patch{Op: PatchCopy, From: MustParsePath(".nope"), Path: MustParsePath(".x")}, "cannot apply patch 0 (copy at .x): from path .nope does not exist"},
+		{Patch{Op: PatchTest, Path: MustParsePath(".name"), Value: String("bob")}, `cannot apply patch 0 (test at .name): test failed, value is "ann"`},
+		{Patch{Op: "frob", Path: Path{}}, `cannot apply patch 0 (frob at ): unknown operation "frob"`},
+	} {
+		out, err := ApplyPatch(v, tc.patch)
+		require.EqualError(t, err, tc.err)
+		require.Equal(t, v, out)
+	}
+
+	out, err = ApplyPatch(v, Patch{Op: PatchAdd, Path: MustParsePath(".n"), Value: Int(1)}, Patch{Op: PatchRemove, Path: MustParsePath(".nope")})
+	require.EqualError(t, err, "cannot apply patch 1 (remove at .nope): path does not exist")
+	require.Equal(t, v, out)
+
+	out, err = ApplyPatch(v, Patch{Op: PatchReplace, Path: Path{}, Value: Array{}})
+	require.NoError(t, err)
+	require.Equal(t, Array{}, out)
+}
+
+func TestDiffPatches(t *testing.T) {
+	a := Struct{"list": Array{Int(1), Int(2), Int(3), Int(4)}, "gone": Bool(true), "same": Int(1)}
+	b := Struct{"list": Array{Int(1), Int(5)}, "new": Struct{}, "same": Int(1)}
+	patches := DiffPatches(a, b)
+	require.Equal(t, []Patch{
+		{Op: PatchRemove, Path: MustParsePath(".gone")},
+		{Op: PatchReplace, Path: MustParsePath(".list[1]"), Value: Int(5)},
+		{Op: PatchRemove, Path: MustParsePath(".list[3]")},
+		{Op: PatchRemove, Path: MustParsePath(".list[2]")},
+		{Op: PatchAdd, Path: MustParsePath(".new"), Value: Struct{}},
+	}, patches)
+	out, err := ApplyPatch(a, patches...)
+	require.NoError(t, err)
+	require.Equal(t, b, out)
+	require.Empty(t, DiffPatches(a, a))
+}
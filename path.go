@@ -0,0 +1,375 @@
+package simple
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Path is the location of a [Value] nested inside of another [Value]. Each
+// element of a Path is either a string, which is a key of a [Struct], or an
+// int, which is an index of an [Array]. The empty Path refers to the root
+// value.
+type Path []any
+
+// Key returns a new Path with the struct key k appended to it.
+func (p Path) Key(k string) Path {
+	return p.append(k)
+}
+
+// Index returns a new Path with the array index i appended to it.
+func (p Path) Index(i int) Path {
+	return p.append(i)
+}
+
+func (p Path) append(e any) Path {
+	out := make(Path, len(p), len(p)+1)
+	copy(out, p)
+	return append(out, e)
+}
+
+// String renders the path the same way conversion errors do, for example:
+// `.users[2].name`. Keys that would be ambiguous in that form are quoted, like
+// `["first.name"]`. The root path renders as an empty string.
+func (p Path) String() string {
+	var sb strings.Builder
+	for _, e := range p {
+		switch te := e.(type) {
+		case string:
+			if isPlainPathKey(te) {
+				sb.WriteString(".")
+				sb.WriteString(te)
+			} else {
+				sb.WriteString("[")
+				sb.WriteString(strconv.Quote(te))
+				sb.WriteString("]")
+			}
+		case int:
+			sb.WriteString("[")
+			sb.WriteString(strconv.Itoa(te))
+			sb.WriteString("]")
+		default:
+			fmt.Fprintf(&sb, "[!%T]", e)
+		}
+	}
+	return sb.String()
+}
+
+// pointerEscaper escapes the reference tokens of a JSON Pointer.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// Pointer renders the path as a JSON Pointer (RFC 6901), for example
+// `/users/2/name`, which tools outside of Go understand. The root path
+// renders as an empty string.
+func (p Path) Pointer() string {
+	var sb strings.Builder
+	for _, e := range p {
+		sb.WriteString("/")
+		switch te := e.(type) {
+		case string:
+			sb.WriteString(pointerEscaper.Replace(te))
+		case int:
+			sb.WriteString(strconv.Itoa(te))
+		default:
+			fmt.Fprintf(&sb, "!%T", e)
+		}
+	}
+	return sb.String()
+}
+
+func isPlainPathKey(k string) bool {
+	if k == "" {
+		return false
+	}
+	return !strings.ContainsAny(k, `.[]"\ `)
+}
+
+type pathSyntaxError struct {
+	path   string
+	offset int
+	msg    string
+}
+
+func (p pathSyntaxError) Error() string {
+	return fmt.Sprintf("invalid path %q at offset %d: %s", p.path, p.offset, p.msg)
+}
+
+// ParsePath parses a path in the form produced by [Path.String]. For
+// convenience the leading "." may be omitted, so "users[2].name" and
+// ".users[2].name" are equivalent.
+func ParsePath(s string) (Path, error) {
+	out := Path{}
+	i := 0
+	readKey := func(start int) error {
+		j := start
+		for j < len(s) && s[j] != '.' && s[j] != '[' {
+			j++
+		}
+		if j == start {
+			return pathSyntaxError{path: s, offset: start, msg: "empty key"}
+		}
+		out = append(out, s[start:j])
+		i = j
+		return nil
+	}
+	if s != "" && s[0] != '.' && s[0] != '[' {
+		if err := readKey(0); err != nil {
+			return nil, err
+		}
+	}
+	for i < len(s) {
+		switch s[i] {
+		case '.':
+			if err := readKey(i + 1); err != nil {
+				return nil, err
+			}
+		case '[':
+			end := strings.IndexByte(s[i:], ']')
+			if i+1 < len(s) && s[i+1] == '"' {
+				// quoted keys may contain "]", so find the closing quote first
+				q, err := strconv.QuotedPrefix(s[i+1:])
+				if err != nil {
+					return nil, pathSyntaxError{path: s, offset: i + 1, msg: "bad quoted key"}
+				}
+				if i+1+len(q) >= len(s) || s[i+1+len(q)] != ']' {
+					return nil, pathSyntaxError{path: s, offset: i + 1 + len(q), msg: `expected "]"`}
+				}
+				k, _ := strconv.Unquote(q)
+				out = append(out, k)
+				i += len(q) + 2
+				continue
+			}
+			if end < 0 {
+				return nil, pathSyntaxError{path: s, offset: i, msg: `missing "]"`}
+			}
+			idx, err := strconv.Atoi(s[i+1 : i+end])
+			if err != nil || idx < 0 {
+				return nil, pathSyntaxError{path: s, offset: i + 1, msg: "bad array index"}
+			}
+			out = append(out, idx)
+			i += end + 1
+		default:
+			return nil, pathSyntaxError{path: s, offset: i, msg: fmt.Sprintf("unexpected %q", s[i])}
+		}
+	}
+	return out, nil
+}
+
+// MustParsePath is like [ParsePath] but panics if the path cannot be parsed.
+func MustParsePath(s string) Path {
+	p, err := ParsePath(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// GetFold looks up key in s, falling back to a case-insensitive match when
+// there is no exact match. This mirrors how encoding/json matches object keys
+// to struct fields. If more than one key matches case-insensitively, the
+// lexically smallest key wins so that the result is deterministic.
+func (s Struct) GetFold(key string) (Value, bool) {
+	if v, ok := s[key]; ok {
+		return v, true
+	}
+	var matches []string
+	for k := range s {
+		if strings.EqualFold(k, key) {
+			matches = append(matches, k)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, false
+	}
+	sort.Strings(matches)
+	return s[matches[0]], true
+}
+
+// LookupOption changes the behavior of [Lookup].
+type LookupOption func(*lookupOptions)
+
+type lookupOptions struct {
+	foldKeys bool
+}
+
+// FoldKeys makes [Lookup] match struct keys case-insensitively, using
+// [Struct.GetFold].
+func FoldKeys() LookupOption {
+	return func(lo *lookupOptions) { lo.foldKeys = true }
+}
+
+// Lookup finds the value located at p inside of v. The second return value
+// reports whether anything exists at that location, which distinguishes a
+// present null from a missing key.
+func Lookup(v Value, p Path, opts ...LookupOption) (Value, bool) {
+	var lo lookupOptions
+	for _, o := range opts {
+		o(&lo)
+	}
+	cur := v
+	for _, e := range p {
+		switch te := e.(type) {
+		case string:
+			s, ok := cur.(Struct)
+			if !ok {
+				return nil, false
+			}
+			if lo.foldKeys {
+				cur, ok = s.GetFold(te)
+			} else {
+				cur, ok = s[te]
+			}
+			if !ok {
+				return nil, false
+			}
+		case int:
+			a, ok := cur.(Array)
+			if !ok || te < 0 || te >= len(a) {
+				return nil, false
+			}
+			cur = a[te]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// pathPattern is a parsed path glob. Path globs are written like paths, with a
+// few additions:
+//
+//   - a key of * matches any single struct key, and [*] matches any single
+//     array index.
+//   - a key of ** matches any number (including zero) of keys or indexes.
+//   - other keys may contain the wildcards understood by [path.Match], like
+//     .*_token. Use a quoted key like ["*"] to match a literal "*".
+type pathPattern []patternElem
+
+type patternElem struct {
+	kind  patternKind
+	key   string
+	index int
+}
+
+type patternKind int
+
+const (
+	patternKey patternKind = iota
+	patternKeyGlob
+	patternIndex
+	patternAnyIndex
+	patternDeep
+)
+
+func parsePathPattern(s string) (pathPattern, error) {
+	// wildcard indexes and quoted keys are handled here, and everything in
+	// between them is handed to ParsePath.
+	var out pathPattern
+	var rest strings.Builder
+	flush := func() error {
+		if rest.Len() == 0 {
+			return nil
+		}
+		p, err := ParsePath(rest.String())
+		if err != nil {
+			return err
+		}
+		rest.Reset()
+		for _, e := range p {
+			switch te := e.(type) {
+			case string:
+				out = append(out, keyPatternElem(te))
+			case int:
+				out = append(out, patternElem{kind: patternIndex, index: te})
+			}
+		}
+		return nil
+	}
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], "[*]") {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			out = append(out, patternElem{kind: patternAnyIndex})
+			i += 3
+			continue
+		}
+		if s[i] == '[' && i+1 < len(s) && s[i+1] == '"' {
+			// quoted keys are always literal
+			q, err := strconv.QuotedPrefix(s[i+1:])
+			if err == nil && i+1+len(q) < len(s) && s[i+1+len(q)] == ']' {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+				k, _ := strconv.Unquote(q)
+				out = append(out, patternElem{kind: patternKey, key: k})
+				i += len(q) + 2
+				continue
+			}
+		}
+		rest.WriteByte(s[i])
+		i++
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	for _, e := range out {
+		if e.kind == patternKeyGlob {
+			if _, err := path.Match(e.key, ""); err != nil {
+				return nil, fmt.Errorf("invalid path pattern %q: %w", s, err)
+			}
+		}
+	}
+	return out, nil
+}
+
+func keyPatternElem(k string) patternElem {
+	if k == "**" {
+		return patternElem{kind: patternDeep}
+	}
+	if strings.ContainsAny(k, `*?[\`) {
+		return patternElem{kind: patternKeyGlob, key: k}
+	}
+	return patternElem{kind: patternKey, key: k}
+}
+
+// Match reports whether p matches the pattern.
+func (pp pathPattern) Match(p Path) bool {
+	if len(pp) == 0 {
+		return len(p) == 0
+	}
+	if pp[0].kind == patternDeep {
+		for i := 0; i <= len(p); i++ {
+			if pp[1:].Match(p[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(p) == 0 || !pp[0].matchElem(p[0]) {
+		return false
+	}
+	return pp[1:].Match(p[1:])
+}
+
+func (e patternElem) matchElem(pe any) bool {
+	switch e.kind {
+	case patternKey:
+		return pe == e.key
+	case patternKeyGlob:
+		k, ok := pe.(string)
+		if !ok {
+			return false
+		}
+		m, _ := path.Match(e.key, k)
+		return m
+	case patternIndex:
+		return pe == e.index
+	case patternAnyIndex:
+		_, ok := pe.(int)
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPath(t *testing.T) {
+	t.Run("String and ParsePath", func(t *testing.T) {
+		for _, tc := range []struct {
+			path Path
+			str  string
+		}{
+			{path: Path{}, str: ""},
+			{path: Path{"users", 2, "name"}, str: ".users[2].name"},
+			{path: Path{0, 1}, str: "[0][1]"},
+			{path: Path{"first.name", `we"ird]`, ""}, str: `["first.name"]["we\"ird]"][""]`},
+		} {
+			require.Equal(t, tc.str, tc.path.String())
+			got, err := ParsePath(tc.str)
+			require.NoError(t, err)
+			require.Equal(t, tc.path, got)
+		}
+
+		p, err := ParsePath("users[2].name")
+		require.NoError(t, err)
+		require.Equal(t, Path{"users", 2, "name"}, p)
+
+		_, err = ParsePath(".users..name")
+		require.EqualError(t, err, `invalid path ".users..name" at offset 7: empty key`)
+		_, err = ParsePath(".users[x]")
+		require.EqualError(t, err, `invalid path ".users[x]" at offset 7: bad array index`)
+	})
+	t.Run("Pointer", func(t *testing.T) {
+		require.Equal(t, "", Path{}.Pointer())
+		require.Equal(t, "/users/2/name", Path{"users", 2, "name"}.Pointer())
+		require.Equal(t, "/a~1b/m~0n/", Path{"a/b", "m~n", ""}.Pointer())
+	})
+	t.Run("Key does not alias", func(t *testing.T) {
+		base := make(Path, 0, 10)
+		base = append(base, "a")
+		b := base.Key("b")
+		c := base.Key("c")
+		require.Equal(t, Path{"a", "b"}, b)
+		require.Equal(t, Path{"a", "c"}, c)
+	})
+}
+
+func TestLookup(t *testing.T) {
+	v := Struct{
+		"Users": Array{
+			Struct{"Name": String("ann"), "email": nil},
+		},
+	}
+	got, ok := Lookup(v, MustParsePath(".Users[0].Name"))
+	require.True(t, ok)
+	require.Equal(t, String("ann"), got)
+
+	got, ok = Lookup(v, MustParsePath(".Users[0].email"))
+	require.True(t, ok)
+	require.Nil(t, got)
+
+	_, ok = Lookup(v, MustParsePath(".users[0].name"))
+	require.False(t, ok)
+	_, ok = Lookup(v, MustParsePath(".Users[1]"))
+	require.False(t, ok)
+
+	got, ok = Lookup(v, MustParsePath(".users[0].NAME"), FoldKeys())
+	require.True(t, ok)
+	require.Equal(t, String("ann"), got)
+}
+
+func TestGetFold(t *testing.T) {
+	s := Struct{"ID": Number(1), "id": Number(2), "Iд": Number(3), "Name": String("x")}
+	v, ok := s.GetFold("id")
+	require.True(t, ok)
+	require.Equal(t, Number(2), v)
+	v, ok = s.GetFold("Id")
+	require.True(t, ok)
+	require.Equal(t, Number(1), v)
+	v, ok = s.GetFold("NAME")
+	require.True(t, ok)
+	require.Equal(t, String("x"), v)
+	_, ok = s.GetFold("missing")
+	require.False(t, ok)
+}
+
+func TestPathPattern(t *testing.T) {
+	for _, tc := range []struct {
+		pattern string
+		path    Path
+		match   bool
+	}{
+		{pattern: ".a.b", path: Path{"a", "b"}, match: true},
+		{pattern: ".a[*].b", path: Path{"a", 3, "b"}, match: true},
+		{pattern: ".a[*].b", path: Path{"a", "x", "b"}, match: false},
+		{pattern: ".a.*", path: Path{"a", "x"}, match: true},
+		{pattern: ".a.*", path: Path{"a", 0}, match: false},
+		{pattern: "**.b", path: Path{"b"}, match: true},
+		{pattern: "**.b", path: Path{"a", 1, "c", "b"}, match: true},
+		{pattern: ".a.**", path: Path{"a"}, match: true},
+		{pattern: `.*_token`, path: Path{"api_token"}, match: true},
+		{pattern: `["*"]`, path: Path{"api_token"}, match: false},
+		{pattern: `["*"]`, path: Path{"*"}, match: true},
+		{pattern: ".a[1]", path: Path{"a", 1}, match: true},
+	} {
+		pp, err := parsePathPattern(tc.pattern)
+		require.NoError(t, err)
+		require.Equal(t, tc.match, pp.Match(tc.path), "%s ~ %s", tc.pattern, tc.path)
+	}
+}
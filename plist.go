@@ -0,0 +1,728 @@
+package simple
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// PlistFormat selects the encoding written by [ToPlist].
+type PlistFormat int
+
+const (
+	// PlistXML is the XML property list format.
+	PlistXML PlistFormat = iota
+	// PlistBinary is the "bplist00" binary property list format.
+	PlistBinary
+)
+
+// plistEpoch is the reference date of binary plist dates.
+var plistEpoch = time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+var errPlistNull = errors.New("property lists cannot represent null")
+
+// FromPlist reads an Apple property list in either the XML or the binary
+// format, which is detected automatically. Dictionaries become [Struct]s,
+// arrays (and binary sets) become [Array]s, integers become [Int]s and reals
+// become [Number]s. Dates become [String]s in RFC 3339 format, in UTC, and
+// data becomes a base64 encoded [String]. Binary UIDs, as used by
+// NSKeyedArchiver, are read as [Int]s.
+func FromPlist(data []byte) (Value, error) {
+	if bytes.HasPrefix(data, []byte("bplist00")) {
+		return fromBinaryPlist(data)
+	}
+	return fromXMLPlist(data)
+}
+
+// ToPlist writes v as a property list in the given format. Since a plist has
+// no way of saying which strings are dates or data, every [String] is written
+// as a string. Whole numbers are only written as integers if they are an
+// [Int], or a [RawNumber] without a fraction or exponent, everything else is a
+// real. Property lists have no null, so nil values cannot be written.
+func ToPlist(w io.Writer, v Value, format PlistFormat) error {
+	switch format {
+	case PlistXML:
+		return toXMLPlist(w, v)
+	case PlistBinary:
+		return toBinaryPlist(w, v)
+	}
+	return fmt.Errorf("unknown plist format %d", format)
+}
+
+// plistInteger reports whether v should be written as a plist integer.
+func plistInteger(v Value) (int64, bool) {
+	switch tv := v.(type) {
+	case Int:
+		return int64(tv), true
+	case RawNumber:
+		if i, err := strconv.ParseInt(string(tv), 10, 64); err == nil {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func fromXMLPlist(data []byte) (Value, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = true
+	var out Value
+	found := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XML plist: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local == "plist" {
+			continue
+		}
+		if found {
+			return nil, fmt.Errorf("invalid XML plist: more than one top-level value")
+		}
+		if out, err = readXMLPlistValue(dec, start, 0); err != nil {
+			return nil, fmt.Errorf("invalid XML plist: %w", err)
+		}
+		found = true
+	}
+	if !found {
+		return nil, fmt.Errorf("invalid XML plist: no value")
+	}
+	return out, nil
+}
+
+// maxPlistDepth limits the nesting of plists, which is also what keeps
+// reference cycles in binary plists from recursing forever.
+const maxPlistDepth = 1000
+
+func readXMLPlistValue(dec *xml.Decoder, start xml.StartElement, depth int) (Value, error) {
+	if depth > maxPlistDepth {
+		return nil, fmt.Errorf("plist is nested too deeply")
+	}
+	switch start.Name.Local {
+	case "dict":
+		out := Struct{}
+		for {
+			tok, err := nextXMLPlistElement(dec)
+			if err != nil {
+				return nil, err
+			}
+			if tok == nil {
+				return out, nil
+			}
+			if tok.Name.Local != "key" {
+				return nil, fmt.Errorf("expected <key> in <dict>, found <%s>", tok.Name.Local)
+			}
+			var k string
+			if err := dec.DecodeElement(&k, tok); err != nil {
+				return nil, err
+			}
+			vtok, err := nextXMLPlistElement(dec)
+			if err != nil {
+				return nil, err
+			}
+			if vtok == nil {
+				return nil, fmt.Errorf("key %q has no value", k)
+			}
+			if out[k], err = readXMLPlistValue(dec, *vtok, depth+1); err != nil {
+				return nil, err
+			}
+		}
+	case "array":
+		out := Array{}
+		for {
+			tok, err := nextXMLPlistElement(dec)
+			if err != nil {
+				return nil, err
+			}
+			if tok == nil {
+				return out, nil
+			}
+			ev, err := readXMLPlistValue(dec, *tok, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, ev)
+		}
+	case "true", "false":
+		if err := dec.Skip(); err != nil {
+			return nil, err
+		}
+		return Bool(start.Name.Local == "true"), nil
+	}
+	var text string
+	if err := dec.DecodeElement(&text, &start); err != nil {
+		return nil, err
+	}
+	switch start.Name.Local {
+	case "string":
+		return String(text), nil
+	case "integer":
+		text = strings.TrimSpace(text)
+		if i, err := strconv.ParseInt(text, 0, 64); err == nil {
+			return Int(i), nil
+		}
+		u, err := strconv.ParseUint(text, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q", text)
+		}
+		return Number(float64(u)), nil
+	case "real":
+		switch text = strings.TrimSpace(text); strings.ToLower(text) {
+		case "nan":
+			return Number(math.NaN()), nil
+		case "+infinity", "infinity", "inf":
+			return Number(math.Inf(1)), nil
+		case "-infinity", "-inf":
+			return Number(math.Inf(-1)), nil
+		}
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid real %q", text)
+		}
+		return Number(f), nil
+	case "date":
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q", text)
+		}
+		return String(t.UTC().Format(time.RFC3339Nano)), nil
+	case "data":
+		b, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(text), ""))
+		if err != nil {
+			return nil, fmt.Errorf("invalid data: %w", err)
+		}
+		return String(base64.StdEncoding.EncodeToString(b)), nil
+	}
+	return nil, fmt.Errorf("unknown element <%s>", start.Name.Local)
+}
+
+// nextXMLPlistElement returns the next start element, or nil at the end of
+// the enclosing element.
+func nextXMLPlistElement(dec *xml.Decoder) (*xml.StartElement, error) {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		switch tt := tok.(type) {
+		case xml.StartElement:
+			return &tt, nil
+		case xml.EndElement:
+			return nil, nil
+		case xml.CharData:
+			if len(bytes.TrimSpace(tt)) > 0 {
+				return nil, fmt.Errorf("unexpected text %q", shortString(String(tt)))
+			}
+		}
+	}
+}
+
+func toXMLPlist(w io.Writer, v Value) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xml.Header)
+	bw.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	bw.WriteString(`<plist version="1.0">` + "\n")
+	if err := writeXMLPlistValue(bw, Path{}, v, ""); err != nil {
+		return err
+	}
+	bw.WriteString("</plist>\n")
+	return bw.Flush()
+}
+
+func writeXMLPlistValue(w *bufio.Writer, path Path, v Value, indent string) error {
+	w.WriteString(indent)
+	switch tv := v.(type) {
+	case nil:
+		return encodeError{path: path, problem: errPlistNull.Error()}
+	case Struct:
+		if len(tv) == 0 {
+			w.WriteString("<dict/>\n")
+			return nil
+		}
+		w.WriteString("<dict>\n")
+		for _, k := range sortedKeys(tv) {
+			w.WriteString(indent + "\t<key>")
+			xml.EscapeText(w, []byte(k))
+			w.WriteString("</key>\n")
+			if err := writeXMLPlistValue(w, path.Key(k), tv[k], indent+"\t"); err != nil {
+				return err
+			}
+		}
+		w.WriteString(indent + "</dict>\n")
+	case Array:
+		if len(tv) == 0 {
+			w.WriteString("<array/>\n")
+			return nil
+		}
+		w.WriteString("<array>\n")
+		for i, ev := range tv {
+			if err := writeXMLPlistValue(w, path.Index(i), ev, indent+"\t"); err != nil {
+				return err
+			}
+		}
+		w.WriteString(indent + "</array>\n")
+	case String:
+		w.WriteString("<string>")
+		xml.EscapeText(w, []byte(tv))
+		w.WriteString("</string>\n")
+	case Bool:
+		if tv {
+			w.WriteString("<true/>\n")
+		} else {
+			w.WriteString("<false/>\n")
+		}
+	case Number, Int, RawNumber:
+		if i, ok := plistInteger(v); ok {
+			w.WriteString("<integer>" + strconv.FormatInt(i, 10) + "</integer>\n")
+			return nil
+		}
+		f, ok := numberValue(v)
+		if !ok {
+			return encodeError{path: path, problem: fmt.Sprintf("%q is not a valid number", v.String())}
+		}
+		var text string
+		switch {
+		case math.IsNaN(f):
+			text = "nan"
+		case math.IsInf(f, 1):
+			text = "+infinity"
+		case math.IsInf(f, -1):
+			text = "-infinity"
+		default:
+			text = strconv.FormatFloat(f, 'g', -1, 64)
+		}
+		w.WriteString("<real>" + text + "</real>\n")
+	default:
+		return encodeError{path: path, problem: fmt.Sprintf("unsupported value type %T", v), err: ErrUnsupportedKind}
+	}
+	return nil
+}
+
+type binaryPlistDecoder struct {
+	data     []byte
+	offsets  []uint64
+	refSize  int
+	visiting map[uint64]bool
+	// decoded counts the objects decoded so far, objects referenced from
+	// more than one place are decoded more than once
+	decoded int
+}
+
+func fromBinaryPlist(data []byte) (Value, error) {
+	if len(data) < 8+32 {
+		return nil, fmt.Errorf("invalid binary plist: too short")
+	}
+	trailer := data[len(data)-32:]
+	offsetSize := int(trailer[6])
+	refSize := int(trailer[7])
+	numObjects := binary.BigEndian.Uint64(trailer[8:])
+	top := binary.BigEndian.Uint64(trailer[16:])
+	tableOffset := binary.BigEndian.Uint64(trailer[24:])
+	body := uint64(len(data) - 32)
+	if offsetSize < 1 || offsetSize > 8 || refSize < 1 || refSize > 8 || top >= numObjects ||
+		tableOffset < 8 || tableOffset > body || numObjects > (body-tableOffset)/uint64(offsetSize) {
+		return nil, fmt.Errorf("invalid binary plist: invalid trailer")
+	}
+	d := binaryPlistDecoder{data: data[:tableOffset], refSize: refSize, visiting: map[uint64]bool{}}
+	d.offsets = make([]uint64, numObjects)
+	for i := range d.offsets {
+		d.offsets[i] = readPlistUint(data[tableOffset+uint64(i*offsetSize):], offsetSize)
+	}
+	v, err := d.object(top, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid binary plist: %w", err)
+	}
+	return v, nil
+}
+
+func readPlistUint(b []byte, size int) uint64 {
+	var u uint64
+	for _, c := range b[:size] {
+		u = u<<8 | uint64(c)
+	}
+	return u
+}
+
+// need returns n bytes at offset off, or an error if they are not there.
+func (d *binaryPlistDecoder) need(off, n uint64) ([]byte, error) {
+	if off > uint64(len(d.data)) || n > uint64(len(d.data))-off {
+		return nil, fmt.Errorf("object at offset %d is truncated", off)
+	}
+	return d.data[off : off+n], nil
+}
+
+// count reads the element count from the low nibble of a marker, which may be
+// followed by an integer object for larger counts. It returns the count and
+// the offset of the object's contents.
+func (d *binaryPlistDecoder) count(off uint64, marker byte) (uint64, uint64, error) {
+	n := uint64(marker & 0x0f)
+	off++
+	if n != 0x0f {
+		return n, off, nil
+	}
+	b, err := d.need(off, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	if b[0]&0xf0 != 0x10 || b[0]&0x0f > 3 {
+		return 0, 0, fmt.Errorf("invalid count at offset %d", off)
+	}
+	size := uint64(1) << (b[0] & 0x0f)
+	ib, err := d.need(off+1, size)
+	if err != nil {
+		return 0, 0, err
+	}
+	return readPlistUint(ib, int(size)), off + 1 + size, nil
+}
+
+func (d *binaryPlistDecoder) refs(off, n uint64) ([]uint64, error) {
+	if n > uint64(len(d.data))/uint64(d.refSize) {
+		return nil, fmt.Errorf("object at offset %d is truncated", off)
+	}
+	b, err := d.need(off, n*uint64(d.refSize))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]uint64, n)
+	for i := range out {
+		out[i] = readPlistUint(b[i*d.refSize:], d.refSize)
+	}
+	return out, nil
+}
+
+func (d *binaryPlistDecoder) object(ref uint64, depth int) (Value, error) {
+	if ref >= uint64(len(d.offsets)) {
+		return nil, fmt.Errorf("object reference %d is out of range", ref)
+	}
+	if depth > maxPlistDepth || d.visiting[ref] {
+		return nil, fmt.Errorf("object %d is part of a cycle or nested too deeply", ref)
+	}
+	if d.decoded++; d.decoded > 64*len(d.offsets)+1024 {
+		return nil, fmt.Errorf("too many shared object references")
+	}
+	off := d.offsets[ref]
+	mb, err := d.need(off, 1)
+	if err != nil {
+		return nil, err
+	}
+	marker := mb[0]
+	switch marker >> 4 {
+	case 0x0:
+		switch marker {
+		case 0x08:
+			return Bool(false), nil
+		case 0x09:
+			return Bool(true), nil
+		case 0x00:
+			return nil, errPlistNull
+		}
+	case 0x1, 0x8:
+		size := uint64(1) << (marker & 0x0f)
+		if marker>>4 == 0x8 {
+			size = uint64(marker&0x0f) + 1
+			if size > 8 {
+				break
+			}
+		}
+		if size > 16 {
+			break
+		}
+		b, err := d.need(off+1, size)
+		if err != nil {
+			return nil, err
+		}
+		if size == 16 {
+			// 128-bit integers are only used for values beyond int64
+			if binary.BigEndian.Uint64(b) != 0 {
+				return nil, fmt.Errorf("integer at offset %d is too large", off)
+			}
+			return Number(float64(binary.BigEndian.Uint64(b[8:]))), nil
+		}
+		if size == 8 {
+			return Int(int64(binary.BigEndian.Uint64(b))), nil
+		}
+		return Int(int64(readPlistUint(b, int(size)))), nil
+	case 0x2:
+		switch marker {
+		case 0x22:
+			b, err := d.need(off+1, 4)
+			if err != nil {
+				return nil, err
+			}
+			return Number(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+		case 0x23:
+			b, err := d.need(off+1, 8)
+			if err != nil {
+				return nil, err
+			}
+			return Number(math.Float64frombits(binary.BigEndian.Uint64(b))), nil
+		}
+	case 0x3:
+		if marker != 0x33 {
+			break
+		}
+		b, err := d.need(off+1, 8)
+		if err != nil {
+			return nil, err
+		}
+		secs := math.Float64frombits(binary.BigEndian.Uint64(b))
+		if math.IsNaN(secs) || math.Abs(secs) > 1e11 {
+			return nil, fmt.Errorf("date at offset %d is out of range", off)
+		}
+		t := plistEpoch.Add(time.Duration(secs * float64(time.Second))).Round(time.Millisecond)
+		return String(t.Format(time.RFC3339Nano)), nil
+	case 0x4, 0x5, 0x6:
+		n, start, err := d.count(off, marker)
+		if err != nil {
+			return nil, err
+		}
+		size := n
+		if marker>>4 == 0x6 {
+			if n > uint64(len(d.data)) {
+				return nil, fmt.Errorf("object at offset %d is truncated", off)
+			}
+			size = 2 * n
+		}
+		b, err := d.need(start, size)
+		if err != nil {
+			return nil, err
+		}
+		switch marker >> 4 {
+		case 0x4:
+			return String(base64.StdEncoding.EncodeToString(b)), nil
+		case 0x5:
+			return String(b), nil
+		}
+		units := make([]uint16, n)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16(b[2*i:])
+		}
+		return String(utf16.Decode(units)), nil
+	case 0xA, 0xC, 0xD:
+		n, start, err := d.count(off, marker)
+		if err != nil {
+			return nil, err
+		}
+		total := n
+		if marker>>4 == 0xD {
+			total = 2 * n
+		}
+		refs, err := d.refs(start, total)
+		if err != nil {
+			return nil, err
+		}
+		d.visiting[ref] = true
+		defer delete(d.visiting, ref)
+		if marker>>4 != 0xD {
+			out := make(Array, len(refs))
+			for i, r := range refs {
+				if out[i], err = d.object(r, depth+1); err != nil {
+					return nil, err
+				}
+			}
+			return out, nil
+		}
+		out := make(Struct, n)
+		for i := uint64(0); i < n; i++ {
+			kv, err := d.object(refs[i], depth+1)
+			if err != nil {
+				return nil, err
+			}
+			k, ok := kv.(String)
+			if !ok {
+				return nil, fmt.Errorf("dictionary at offset %d has a key that is not a string", off)
+			}
+			if out[string(k)], err = d.object(refs[n+i], depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("unknown object marker 0x%02x at offset %d", marker, off)
+}
+
+// binaryPlistObject is an object to be written to a binary plist, with the
+// indexes of the objects it references.
+type binaryPlistObject struct {
+	v    Value
+	refs []int
+}
+
+type binaryPlistEncoder struct {
+	objects []binaryPlistObject
+}
+
+func (e *binaryPlistEncoder) add(path Path, v Value) (int, error) {
+	i := len(e.objects)
+	e.objects = append(e.objects, binaryPlistObject{v: v})
+	var refs []int
+	switch tv := v.(type) {
+	case nil:
+		return 0, encodeError{path: path, problem: errPlistNull.Error()}
+	case Struct:
+		keys := sortedKeys(tv)
+		refs = make([]int, 0, 2*len(keys))
+		for _, k := range keys {
+			ki, _ := e.add(path, String(k))
+			refs = append(refs, ki)
+		}
+		for _, k := range keys {
+			vi, err := e.add(path.Key(k), tv[k])
+			if err != nil {
+				return 0, err
+			}
+			refs = append(refs, vi)
+		}
+	case Array:
+		refs = make([]int, 0, len(tv))
+		for j, ev := range tv {
+			vi, err := e.add(path.Index(j), ev)
+			if err != nil {
+				return 0, err
+			}
+			refs = append(refs, vi)
+		}
+	case Number, Int, RawNumber:
+		if _, ok := numberValue(v); !ok {
+			return 0, encodeError{path: path, problem: fmt.Sprintf("%q is not a valid number", v.String())}
+		}
+	case String, Bool:
+	default:
+		return 0, encodeError{path: path, problem: fmt.Sprintf("unsupported value type %T", v), err: ErrUnsupportedKind}
+	}
+	e.objects[i].refs = refs
+	return i, nil
+}
+
+// plistUintSize returns the number of bytes needed to write u, which is 1, 2,
+// 4 or 8.
+func plistUintSize(u uint64) int {
+	switch {
+	case u <= math.MaxUint8:
+		return 1
+	case u <= math.MaxUint16:
+		return 2
+	case u <= math.MaxUint32:
+		return 4
+	}
+	return 8
+}
+
+func appendPlistUint(b []byte, u uint64, size int) []byte {
+	for i := size - 1; i >= 0; i-- {
+		b = append(b, byte(u>>(8*i)))
+	}
+	return b
+}
+
+func appendPlistInt(b []byte, i int64) []byte {
+	size := 8
+	if i >= 0 {
+		size = plistUintSize(uint64(i))
+	}
+	b = append(b, 0x10|byte(bitsLog2(size)))
+	return appendPlistUint(b, uint64(i), size)
+}
+
+func bitsLog2(size int) int {
+	switch size {
+	case 1:
+		return 0
+	case 2:
+		return 1
+	case 4:
+		return 2
+	}
+	return 3
+}
+
+func appendPlistMarker(b []byte, kind byte, n int) []byte {
+	if n < 15 {
+		return append(b, kind<<4|byte(n))
+	}
+	return appendPlistInt(append(b, kind<<4|0x0f), int64(n))
+}
+
+func toBinaryPlist(w io.Writer, v Value) error {
+	var e binaryPlistEncoder
+	if _, err := e.add(Path{}, v); err != nil {
+		return err
+	}
+	refSize := plistUintSize(uint64(len(e.objects)))
+	out := []byte("bplist00")
+	offsets := make([]uint64, len(e.objects))
+	for i, obj := range e.objects {
+		offsets[i] = uint64(len(out))
+		switch tv := obj.v.(type) {
+		case Struct:
+			out = appendPlistMarker(out, 0xD, len(tv))
+		case Array:
+			out = appendPlistMarker(out, 0xA, len(tv))
+		case String:
+			if isASCII(string(tv)) {
+				out = append(appendPlistMarker(out, 0x5, len(tv)), tv...)
+				break
+			}
+			units := utf16.Encode([]rune(string(tv)))
+			out = appendPlistMarker(out, 0x6, len(units))
+			for _, u := range units {
+				out = binary.BigEndian.AppendUint16(out, u)
+			}
+		case Bool:
+			if tv {
+				out = append(out, 0x09)
+			} else {
+				out = append(out, 0x08)
+			}
+		default:
+			if n, ok := plistInteger(tv); ok {
+				out = appendPlistInt(out, n)
+				break
+			}
+			f, _ := numberValue(tv)
+			out = binary.BigEndian.AppendUint64(append(out, 0x23), math.Float64bits(f))
+		}
+		for _, r := range obj.refs {
+			out = appendPlistUint(out, uint64(r), refSize)
+		}
+	}
+	tableOffset := uint64(len(out))
+	offsetSize := plistUintSize(tableOffset)
+	for _, o := range offsets {
+		out = appendPlistUint(out, o, offsetSize)
+	}
+	out = append(out, 0, 0, 0, 0, 0, 0, byte(offsetSize), byte(refSize))
+	out = binary.BigEndian.AppendUint64(out, uint64(len(e.objects)))
+	out = binary.BigEndian.AppendUint64(out, 0)
+	out = binary.BigEndian.AppendUint64(out, tableOffset)
+	_, err := w.Write(out)
+	return err
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+package simple
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromPlistXML(t *testing.T) {
+	v, err := FromPlist([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleName</key>
+	<string>App &amp; Co</string>
+	<key>Version</key>
+	<integer>3</integer>
+	<key>Scale</key>
+	<real>1.5</real>
+	<key>Enabled</key>
+	<true/>
+	<key>Created</key>
+	<date>2024-03-01T12:00:00Z</date>
+	<key>Icon</key>
+	<data>
+	AQID
+	</data>
+	<key>Items</key>
+	<array>
+		<false/>
+		<dict/>
+	</array>
+</dict>
+</plist>`))
+	require.NoError(t, err)
+	require.Equal(t, Struct{
+		"CFBundleName": String("App & Co"),
+		"Version":      Int(3),
+		"Scale":        Number(1.5),
+		"Enabled":      Bool(true),
+		"Created":      String("2024-03-01T12:00:00Z"),
+		"Icon":         String("AQID"),
+		"Items":        Array{Bool(false), Struct{}},
+	}, v)
+
+	for in, msg := range map[string]string{
+		`<plist><dict><string>x</string></dict></plist>`: "invalid XML plist: expected <key> in <dict>, found <string>",
+		`<plist><integer>x</integer></plist>`:            `invalid XML plist: invalid integer "x"`,
+		`<plist><dict><key>a</key></dict></plist>`:       `invalid XML plist: key "a" has no value`,
+		`<plist><null/></plist>`:                         "invalid XML plist: unknown element <null>",
+		`<plist></plist>`:                                "invalid XML plist: no value",
+		`<plist><true/><false/></plist>`:                 "invalid XML plist: more than one top-level value",
+	} {
+		_, err := FromPlist([]byte(in))
+		require.EqualError(t, err, msg, in)
+	}
+}
+
+func TestToPlist(t *testing.T) {
+	v := Struct{
+		"name":  String("a < b"),
+		"n":     Int(-2),
+		"raw":   RawNumber("7"),
+		"f":     Number(0.25),
+		"list":  Array{Bool(true), Array{}},
+		"empty": Struct{},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, ToPlist(&buf, v, PlistXML))
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>empty</key>
+	<dict/>
+	<key>f</key>
+	<real>0.25</real>
+	<key>list</key>
+	<array>
+		<true/>
+		<array/>
+	</array>
+	<key>n</key>
+	<integer>-2</integer>
+	<key>name</key>
+	<string>a &lt; b</string>
+	<key>raw</key>
+	<integer>7</integer>
+</dict>
+</plist>
+`, buf.String())
+	back, err := FromPlist(buf.Bytes())
+	require.NoError(t, err)
+	require.True(t, equal(v, back), "%s", back)
+
+	err = ToPlist(&buf, Struct{"a": Array{nil}}, PlistBinary)
+	require.EqualError(t, err, "cannot encode value at .a[0]: property lists cannot represent null")
+	err = ToPlist(&buf, Struct{"a": nil}, PlistXML)
+	require.EqualError(t, err, "cannot encode value at .a: property lists cannot represent null")
+}
+
+func TestBinaryPlistRoundTrip(t *testing.T) {
+	many := Array{}
+	for i := 0; i < 300; i++ {
+		many = append(many, Int(int64(i)*1000))
+	}
+	v := Struct{
+		"ascii":    String(strings.Repeat("x", 20)),
+		"unicode":  String("café 😀"),
+		"negative": Int(-5),
+		"big":      Int(1 << 40),
+		"real":     Number(-1.5),
+		"bools":    Array{Bool(true), Bool(false)},
+		"nested":   Struct{"empty": Array{}},
+		"many":     many,
+	}
+	var buf bytes.Buffer
+	require.NoError(t, ToPlist(&buf, v, PlistBinary))
+	require.True(t, bytes.HasPrefix(buf.Bytes(), []byte("bplist00")))
+	back, err := FromPlist(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, v, back)
+}
+
+func TestBinaryPlistErrors(t *testing.T) {
+	// an array that contains itself
+	cycle := []byte("bplist00\xa1\x00\x08")
+	cycle = append(cycle, 0, 0, 0, 0, 0, 0, 1, 1)
+	cycle = append(cycle, 0, 0, 0, 0, 0, 0, 0, 1)
+	cycle = append(cycle, 0, 0, 0, 0, 0, 0, 0, 0)
+	cycle = append(cycle, 0, 0, 0, 0, 0, 0, 0, 10)
+	_, err := FromPlist(cycle)
+	require.EqualError(t, err, "invalid binary plist: object 0 is part of a cycle or nested too deeply")
+
+	var buf bytes.Buffer
+	require.NoError(t, ToPlist(&buf, Struct{"a": String("b")}, PlistBinary))
+	_, err = FromPlist(buf.Bytes()[:20])
+	require.EqualError(t, err, "invalid binary plist: too short")
+	_, err = FromPlist(append(buf.Bytes()[:8], buf.Bytes()[12:]...))
+	require.EqualError(t, err, "invalid binary plist: invalid trailer")
+
+	// a date, which is read as a string
+	date := []byte("bplist00\x33\x41\xc5\xc8\xfd\x60\x00\x00\x00\x08")
+	date = append(date, 0, 0, 0, 0, 0, 0, 1, 1)
+	date = append(date, 0, 0, 0, 0, 0, 0, 0, 1)
+	date = append(date, 0, 0, 0, 0, 0, 0, 0, 0)
+	date = append(date, 0, 0, 0, 0, 0, 0, 0, 17)
+	v, err := FromPlist(date)
+	require.NoError(t, err)
+	require.Equal(t, String("2024-03-01T12:00:00Z"), v)
+}
@@ -0,0 +1,112 @@
+package simple
+
+import (
+	"reflect"
+	"sync"
+)
+
+// maxPooledSize keeps very large Structs and Arrays out of a [Pool], so that
+// one huge Value does not keep its memory alive for the small ones.
+const maxPooledSize = 1 << 10
+
+// Pool recycles the maps of Structs and the backing arrays of Arrays, for
+// services that decode many small Values per second and want less work for
+// the garbage collector. Values are taken from it by [FromJSON] and
+// [JSONDecoder] with [WithPool], and handed back with Put once they are no
+// longer used.
+//
+// The zero Pool is ready to use, and a Pool is safe for concurrent use.
+type Pool struct {
+	structs sync.Pool
+	arrays  sync.Pool
+}
+
+// Struct returns an empty Struct, reusing a recycled one if there is one.
+func (p *Pool) Struct() Struct {
+	if s, ok := p.structs.Get().(Struct); ok {
+		return s
+	}
+	return Struct{}
+}
+
+// Array returns an empty Array, reusing the backing array of a recycled one
+// if there is one.
+func (p *Pool) Array() Array {
+	if a, ok := p.arrays.Get().(*Array); ok {
+		return *a
+	}
+	return Array{}
+}
+
+// Put recycles all Structs and Arrays in v, including the nested ones. They
+// are emptied, so v and everything that was taken out of it must not be used
+// afterwards. A Struct or Array that appears more than once in v is only
+// recycled once, but Arrays that are different slices of the same backing
+// array must not be put.
+func (p *Pool) Put(v Value) {
+	p.put(v, map[any]bool{})
+}
+
+// put recycles v, unless it is in seen, which holds the map pointers of
+// Structs and the first elements of Arrays that were recycled already.
+func (p *Pool) put(v Value, seen map[any]bool) {
+	switch tv := v.(type) {
+	case Struct:
+		if tv == nil {
+			return
+		}
+		id := reflect.ValueOf(tv).UnsafePointer()
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		for _, ev := range tv {
+			p.put(ev, seen)
+		}
+		if len(tv) > maxPooledSize {
+			return
+		}
+		clear(tv)
+		p.structs.Put(tv)
+	case Array:
+		if cap(tv) == 0 {
+			// nothing to recycle
+			return
+		}
+		id := &tv[:1][0]
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		for _, ev := range tv {
+			p.put(ev, seen)
+		}
+		if cap(tv) > maxPooledSize {
+			return
+		}
+		clear(tv)
+		tv = tv[:0]
+		p.arrays.Put(&tv)
+	}
+}
+
+// WithPool makes [FromJSON] and [JSONDecoder] take the Structs and Arrays
+// they build from p. The state of the underlying json.Decoder cannot be
+// reset, so it is not pooled.
+func WithPool(p *Pool) JSONOption {
+	return func(b *jsonBuilder) { b.pool = p }
+}
+
+func (b *jsonBuilder) newStruct() Struct {
+	if b.pool != nil {
+		return b.pool.Struct()
+	}
+	return Struct{}
+}
+
+func (b *jsonBuilder) newArray() Array {
+	if b.pool != nil {
+		return b.pool.Array()
+	}
+	return Array{}
+}
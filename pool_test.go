@@ -0,0 +1,58 @@
+package simple
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	var p Pool
+	for range 3 {
+		v, err := FromJSON([]byte(`{"a":[1,{"b":true}],"c":{}}`), WithPool(&p))
+		require.NoError(t, err)
+		require.Equal(t, Struct{"a": Array{Number(1), Struct{"b": Bool(true)}}, "c": Struct{}}, v)
+		p.Put(v)
+		require.Empty(t, v)
+	}
+
+	dec := NewJSONDecoder(strings.NewReader(`[1,2] [3]`), WithPool(&p))
+	v, err := dec.Decode()
+	require.NoError(t, err)
+	require.Equal(t, Array{Number(1), Number(2)}, v)
+	p.Put(v)
+	v, err = dec.Decode()
+	require.NoError(t, err)
+	require.Equal(t, Array{Number(3)}, v)
+
+	require.Empty(t, p.Struct())
+	require.Empty(t, p.Array())
+	p.Put(nil)
+	p.Put(Struct(nil))
+	p.Put(String("x"))
+}
+
+func TestPoolSharedValues(t *testing.T) {
+	var p Pool
+	s := Struct{"a": Number(1)}
+	a := Array{Number(1)}
+	p.Put(Array{s, s, a, a, Struct{"s": s, "a": a}})
+
+	// every recycled Struct and Array is handed out only once
+	structs := map[any]bool{}
+	for range 8 {
+		ps := p.Struct()
+		ps["x"] = Bool(true)
+		id := reflect.ValueOf(ps).UnsafePointer()
+		require.False(t, structs[id])
+		structs[id] = true
+	}
+	arrays := map[*Value]bool{}
+	for range 8 {
+		pa := append(p.Array(), Bool(true))
+		require.False(t, arrays[&pa[0]])
+		arrays[&pa[0]] = true
+	}
+}
@@ -0,0 +1,194 @@
+package simple
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+type propertiesSyntaxError struct {
+	line    int
+	problem string
+}
+
+func (p propertiesSyntaxError) Error() string {
+	return fmt.Sprintf("invalid properties at line %d: %s", p.line, p.problem)
+}
+
+// FromProperties reads a Java .properties file, following the rules of
+// java.util.Properties: comments start with '#' or '!', keys are separated
+// from values by '=', ':' or whitespace, lines ending with a backslash are
+// continued and escapes like \t and \u00e9 are decoded. The input is read as
+// UTF-8. A key that appears more than once keeps its last value.
+//
+// Keys are then unflattened on "." with [Unflatten], so db.host=x and
+// hosts.0=a become {"db":{"host":"x"},"hosts":["a"]}. All values are
+// [String]s, use [Decode] with [WeaklyTyped] or [AsInt64] and friends to
+// convert them.
+func FromProperties(r io.Reader) (Value, error) {
+	flat := Struct{}
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		start := line
+		text := strings.TrimLeft(sc.Text(), " \t\f")
+		if text == "" || text[0] == '#' || text[0] == '!' {
+			continue
+		}
+		for continuesProperty(text) {
+			text = text[:len(text)-1]
+			if !sc.Scan() {
+				break
+			}
+			line++
+			text += strings.TrimLeft(sc.Text(), " \t\f")
+		}
+		k, v, err := splitProperty(text)
+		if err != nil {
+			return nil, propertiesSyntaxError{line: start, problem: err.Error()}
+		}
+		flat[k] = String(v)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return Unflatten(flat, ".")
+}
+
+// continuesProperty reports whether line ends with an odd number of
+// backslashes.
+func continuesProperty(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+func splitProperty(text string) (string, string, error) {
+	end := len(text)
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", text[i]) >= 0 {
+			end = i
+			break
+		}
+	}
+	rest := strings.TrimLeft(text[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	k, err := unescapeProperty(text[:end])
+	if err != nil {
+		return "", "", err
+	}
+	v, err := unescapeProperty(rest)
+	if err != nil {
+		return "", "", err
+	}
+	return k, v, nil
+}
+
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var sb strings.Builder
+	var units []uint16
+	flush := func() {
+		sb.WriteString(string(utf16.Decode(units)))
+		units = units[:0]
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i == len(s)-1 {
+			flush()
+			sb.WriteByte(c)
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'u':
+			if i+4 >= len(s) {
+				return "", fmt.Errorf(`malformed \uxxxx escape`)
+			}
+			u, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf(`malformed \uxxxx escape`)
+			}
+			// surrogate pairs are combined when the units are flushed
+			units = append(units, uint16(u))
+			i += 4
+			continue
+		case 't':
+			c = '\t'
+		case 'n':
+			c = '\n'
+		case 'r':
+			c = '\r'
+		case 'f':
+			c = '\f'
+		default:
+			c = s[i]
+		}
+		flush()
+		sb.WriteByte(c)
+	}
+	flush()
+	return sb.String(), nil
+}
+
+// ToProperties writes s as a Java .properties file that [FromProperties]
+// reads back. It is flattened with [ToStringMap] first, so nested keys are
+// joined with "." and nulls and empty composites are left out. Keys are
+// written in sorted order, and everything outside of printable ASCII is
+// escaped, as java.util.Properties.store does.
+func ToProperties(w io.Writer, s Struct) error {
+	flat, err := ToStringMap(s)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	for _, k := range sortedKeysOf(flat) {
+		bw.WriteString(escapeProperty(k, true))
+		bw.WriteByte('=')
+		bw.WriteString(escapeProperty(flat[k], false))
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+func escapeProperty(s string, isKey bool) string {
+	var sb strings.Builder
+	for i, r := range s {
+		switch {
+		case r == ' ' && (isKey || i == 0):
+			sb.WriteString(`\ `)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\f':
+			sb.WriteString(`\f`)
+		case strings.ContainsRune(`\=:#!`, r):
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			for _, u := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&sb, `\u%04X`, u)
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
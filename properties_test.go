@@ -0,0 +1,74 @@
+package simple
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromProperties(t *testing.T) {
+	v, err := FromProperties(strings.NewReader(`# comment
+! another comment
+db.host = localhost
+db.port:5432
+db.name   orders
+greeting = caf\u00e9 \uD83D\uDE00
+path=C:\\temp\\
+multi = one, \
+        two
+key\ with\ spaces = x\=y
+hosts.0=a
+hosts.1=b
+empty
+db.host = override
+`))
+	require.NoError(t, err)
+	require.Equal(t, Struct{
+		"db": Struct{
+			"host": String("override"),
+			"port": String("5432"),
+			"name": String("orders"),
+		},
+		"greeting":        String("café 😀"),
+		"path":            String(`C:\temp\`),
+		"multi":           String("one, two"),
+		"key with spaces": String("x=y"),
+		"hosts":           Array{String("a"), String("b")},
+		"empty":           String(""),
+	}, v)
+
+	_, err = FromProperties(strings.NewReader("a=1\nb=\\u12"))
+	require.EqualError(t, err, `invalid properties at line 2: malformed \uxxxx escape`)
+	_, err = FromProperties(strings.NewReader("a=1\na.b=2"))
+	require.EqualError(t, err, `key "a.b" conflicts with another key`)
+}
+
+func TestToProperties(t *testing.T) {
+	s := Struct{
+		"db":    Struct{"host": String("localhost"), "port": Number(5432)},
+		"hosts": Array{String(" a"), String("b")},
+		"note":  String("café = 😀\n#1"),
+		"a key": Bool(true),
+		"skip":  nil,
+	}
+	var buf bytes.Buffer
+	require.NoError(t, ToProperties(&buf, s))
+	require.Equal(t, `a\ key=true
+db.host=localhost
+db.port=5432
+hosts.0=\ a
+hosts.1=b
+note=caf\u00E9 \= \uD83D\uDE00\n\#1
+`, buf.String())
+
+	v, err := FromProperties(&buf)
+	require.NoError(t, err)
+	require.Equal(t, Struct{
+		"a key": String("true"),
+		"db":    Struct{"host": String("localhost"), "port": String("5432")},
+		"hosts": Array{String(" a"), String("b")},
+		"note":  String("café = 😀\n#1"),
+	}, v)
+}
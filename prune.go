@@ -0,0 +1,64 @@
+package simple
+
+// PruneOption changes what [Prune] removes.
+type PruneOption func(*pruneOptions)
+
+type pruneOptions struct {
+	keepNulls        bool
+	keepEmptyStructs bool
+	keepEmptyArrays  bool
+}
+
+// KeepNulls stops [Prune] from removing null values.
+func KeepNulls() PruneOption {
+	return func(po *pruneOptions) { po.keepNulls = true }
+}
+
+// KeepEmptyStructs stops [Prune] from removing empty Structs.
+func KeepEmptyStructs() PruneOption {
+	return func(po *pruneOptions) { po.keepEmptyStructs = true }
+}
+
+// KeepEmptyArrays stops [Prune] from removing empty Arrays.
+func KeepEmptyArrays() PruneOption {
+	return func(po *pruneOptions) { po.keepEmptyArrays = true }
+}
+
+// Prune returns a copy of v with nulls, empty Structs and empty Arrays
+// removed from it, both as struct fields and as array elements. Pruning is
+// recursive, so a Struct that only contains nulls is removed as well. If v
+// itself ends up being pruned, nil is returned. v is not modified.
+func Prune(v Value, opts ...PruneOption) Value {
+	var po pruneOptions
+	for _, o := range opts {
+		o(&po)
+	}
+	if out, keep := po.prune(v); keep {
+		return out
+	}
+	return nil
+}
+
+func (po *pruneOptions) prune(v Value) (Value, bool) {
+	switch tv := v.(type) {
+	case nil:
+		return nil, po.keepNulls
+	case Struct:
+		out := make(Struct, len(tv))
+		for k, ev := range tv {
+			if nv, keep := po.prune(ev); keep {
+				out[k] = nv
+			}
+		}
+		return out, len(out) > 0 || po.keepEmptyStructs
+	case Array:
+		out := make(Array, 0, len(tv))
+		for _, ev := range tv {
+			if nv, keep := po.prune(ev); keep {
+				out = append(out, nv)
+			}
+		}
+		return out, len(out) > 0 || po.keepEmptyArrays
+	}
+	return v, true
+}
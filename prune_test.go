@@ -0,0 +1,42 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrune(t *testing.T) {
+	v := Struct{
+		"id":     Number(1),
+		"email":  nil,
+		"tags":   Array{},
+		"meta":   Struct{"a": nil, "b": Struct{}},
+		"values": Array{nil, Number(0), Array{nil}},
+		"off":    Bool(false),
+	}
+
+	require.Equal(t, Struct{
+		"id":     Number(1),
+		"values": Array{Number(0)},
+		"off":    Bool(false),
+	}, Prune(v))
+
+	require.Equal(t, Struct{
+		"id":     Number(1),
+		"tags":   Array{},
+		"values": Array{Number(0), Array{}},
+		"off":    Bool(false),
+	}, Prune(v, KeepEmptyArrays()))
+
+	require.Equal(t, Struct{
+		"id":     Number(1),
+		"email":  nil,
+		"meta":   Struct{"a": nil},
+		"values": Array{nil, Number(0), Array{nil}},
+		"off":    Bool(false),
+	}, Prune(v, KeepNulls()))
+
+	require.Nil(t, Prune(Struct{"a": Array{}}))
+	require.Len(t, v, 6, "input must not be modified")
+}
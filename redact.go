@@ -0,0 +1,92 @@
+package simple
+
+import (
+	"path"
+	"strings"
+)
+
+// Redacted is what [Redactor] and [FromValue] replace sensitive values with.
+const Redacted = String("[REDACTED]")
+
+// Redactor produces copies of values with sensitive parts of them replaced,
+// so that they can be logged safely.
+type Redactor struct {
+	// Keys are patterns that are matched case-insensitively against every
+	// struct key in a value, using [path.Match] syntax, e.g. "password",
+	// "*token*" or "*secret*". A value held by a matching key is redacted
+	// regardless of where it is in the tree.
+	Keys []string
+
+	// Paths are path globs like ".users[*].ssn" or "**.credentials". A "*" key
+	// matches any one key, "[*]" matches any one index and "**" matches any
+	// number of either.
+	Paths []string
+
+	// Replacement is used in place of redacted values. When nil, [Redacted]
+	// is used.
+	Replacement Value
+}
+
+// Redact returns a copy of v with every value matched by r replaced. An error
+// is only returned if one of the patterns of r is malformed.
+func (r Redactor) Redact(v Value) (Value, error) {
+	keys := make([]string, len(r.Keys))
+	for i, k := range r.Keys {
+		keys[i] = strings.ToLower(k)
+		if _, err := path.Match(keys[i], ""); err != nil {
+			return nil, err
+		}
+	}
+	paths := make([]pathPattern, len(r.Paths))
+	for i, p := range r.Paths {
+		pp, err := parsePathPattern(p)
+		if err != nil {
+			return nil, err
+		}
+		paths[i] = pp
+	}
+	var replacement Value = Redacted
+	if r.Replacement != nil {
+		replacement = r.Replacement
+	}
+	matches := func(p Path) bool {
+		if len(p) > 0 {
+			if k, ok := p[len(p)-1].(string); ok {
+				k = strings.ToLower(k)
+				for _, pattern := range keys {
+					if m, _ := path.Match(pattern, k); m {
+						return true
+					}
+				}
+			}
+		}
+		for _, pp := range paths {
+			if pp.Match(p) {
+				return true
+			}
+		}
+		return false
+	}
+	return redact(Path{}, v, matches, replacement), nil
+}
+
+func redact(p Path, v Value, matches func(Path) bool, replacement Value) Value {
+	if matches(p) {
+		return replacement
+	}
+	switch tv := v.(type) {
+	case Struct:
+		out := make(Struct, len(tv))
+		for k, ev := range tv {
+			out[k] = redact(p.Key(k), ev, matches, replacement)
+		}
+		return out
+	case Array:
+		out := make(Array, len(tv))
+		for i, ev := range tv {
+			out[i] = redact(p.Index(i), ev, matches, replacement)
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,58 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactor(t *testing.T) {
+	v := Struct{
+		"user": Struct{
+			"name":         String("ann"),
+			"Password":     String("hunter2"),
+			"api_token_v2": String("abc"),
+		},
+		"client_secret_key": String("xyz"),
+		"cards": Array{
+			Struct{"number": String("4111"), "brand": String("visa")},
+		},
+		"deep": Struct{"er": Struct{"credentials": Struct{"k": String("v")}}},
+	}
+	r := Redactor{
+		Keys:  []string{"password", "*token*", "*secret*"},
+		Paths: []string{".cards[*].number", "**.credentials"},
+	}
+	got, err := r.Redact(v)
+	require.NoError(t, err)
+	require.Equal(t, Struct{
+		"user": Struct{
+			"name":         String("ann"),
+			"Password":     Redacted,
+			"api_token_v2": Redacted,
+		},
+		"client_secret_key": Redacted,
+		"cards": Array{
+			Struct{"number": Redacted, "brand": String("visa")},
+		},
+		"deep": Struct{"er": Struct{"credentials": Redacted}},
+	}, got)
+	require.Equal(t, String("hunter2"), v["user"].(Struct)["Password"], "input must not be modified")
+
+	got, err = Redactor{Paths: []string{"*"}, Replacement: nil}.Redact(Array{Number(1)})
+	require.NoError(t, err)
+	require.Equal(t, Array{Number(1)}, got)
+
+	_, err = Redactor{Keys: []string{"[oops"}}.Redact(v)
+	require.Error(t, err)
+}
+
+func TestFromValueRedactTag(t *testing.T) {
+	type login struct {
+		User     string
+		Password string `simple:"redact"`
+	}
+	got, err := FromValue(login{User: "ann", Password: "hunter2"})
+	require.NoError(t, err)
+	require.Equal(t, Struct{"User": String("ann"), "Password": Redacted}, got)
+}
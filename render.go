@@ -0,0 +1,159 @@
+package simple
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Palette holds the ANSI escape sequences that [Render] puts in front of each
+// kind of token, and that [UnifiedDiff] puts in front of each kind of line.
+// Empty entries are not colored.
+type Palette struct {
+	Key    string
+	String string
+	Number string
+	Bool   string
+	Null   string
+
+	Added   string
+	Removed string
+	Hunk    string
+}
+
+// DefaultPalette colors keys in bold blue, strings in green, numbers in cyan,
+// booleans in yellow and null in gray. In diffs, added lines are green,
+// removed lines red and hunk headers cyan.
+var DefaultPalette = Palette{
+	Key:    "\x1b[1;34m",
+	String: "\x1b[32m",
+	Number: "\x1b[36m",
+	Bool:   "\x1b[33m",
+	Null:   "\x1b[90m",
+
+	Added:   "\x1b[32m",
+	Removed: "\x1b[31m",
+	Hunk:    "\x1b[36m",
+}
+
+const ansiReset = "\x1b[0m"
+
+// RenderOption changes the output of [Render].
+type RenderOption func(*renderer)
+
+// WithPalette sets the colors used by [Render]. Passing the zero Palette
+// turns colors off, which is useful when the output is not a terminal.
+func WithPalette(p Palette) RenderOption {
+	return func(r *renderer) { r.palette = p }
+}
+
+// FoldDepth makes [Render] fold non-empty Structs and Arrays that are nested
+// n or more levels deep into [TruncatedStruct] and [TruncatedArray], like
+// [Truncate] does. A depth of 0, the default, does not fold anything.
+func FoldDepth(n int) RenderOption {
+	return func(r *renderer) { r.foldDepth = n }
+}
+
+// RenderIndent sets the string used for each level of indentation. The
+// default is two spaces.
+func RenderIndent(indent string) RenderOption {
+	return func(r *renderer) { r.indent = indent }
+}
+
+type renderer struct {
+	buf       bytes.Buffer
+	palette   Palette
+	foldDepth int
+	indent    string
+}
+
+// Render pretty-prints v to w for humans, as indented JSON with ANSI colors
+// and a trailing newline, for CLI tools built on this package. Keys are
+// written in sorted order. NaN and infinite numbers are written as NaN,
+// Infinity and -Infinity, so unlike [Encoder] it never fails to render a
+// Value. It uses [DefaultPalette] unless [WithPalette] says otherwise.
+func Render(w io.Writer, v Value, opts ...RenderOption) error {
+	r := renderer{palette: DefaultPalette, indent: "  "}
+	for _, o := range opts {
+		o(&r)
+	}
+	r.render(v, 0)
+	r.buf.WriteByte('\n')
+	_, err := w.Write(r.buf.Bytes())
+	return err
+}
+
+func (r *renderer) colored(color string, token func()) {
+	if color == "" {
+		token()
+		return
+	}
+	r.buf.WriteString(color)
+	token()
+	r.buf.WriteString(ansiReset)
+}
+
+func (r *renderer) newline(depth int) {
+	r.buf.WriteByte('\n')
+	r.buf.WriteString(strings.Repeat(r.indent, depth))
+}
+
+func (r *renderer) folds(depth int) bool {
+	return r.foldDepth > 0 && depth >= r.foldDepth
+}
+
+func (r *renderer) render(v Value, depth int) {
+	switch tv := v.(type) {
+	case nil:
+		r.colored(r.palette.Null, func() { r.buf.WriteString("null") })
+	case Bool:
+		r.colored(r.palette.Bool, func() { r.buf.WriteString(tv.String()) })
+	case Number, Int, RawNumber:
+		r.colored(r.palette.Number, func() { r.buf.WriteString(scalarText(tv)) })
+	case String:
+		r.colored(r.palette.String, func() { r.buf.WriteString(scalarText(tv)) })
+	case Struct:
+		if len(tv) == 0 {
+			r.buf.WriteString("{}")
+			return
+		}
+		if r.folds(depth) {
+			r.colored(r.palette.Null, func() { r.buf.WriteString(string(TruncatedStruct)) })
+			return
+		}
+		r.buf.WriteByte('{')
+		for i, k := range sortedKeys(tv) {
+			if i > 0 {
+				r.buf.WriteByte(',')
+			}
+			r.newline(depth + 1)
+			r.colored(r.palette.Key, func() { writeJSONString(&r.buf, k, false, false) })
+			r.buf.WriteString(": ")
+			r.render(tv[k], depth+1)
+		}
+		r.newline(depth)
+		r.buf.WriteByte('}')
+	case Array:
+		if len(tv) == 0 {
+			r.buf.WriteString("[]")
+			return
+		}
+		if r.folds(depth) {
+			r.colored(r.palette.Null, func() { r.buf.WriteString(string(TruncatedArray)) })
+			return
+		}
+		r.buf.WriteByte('[')
+		for i, ev := range tv {
+			if i > 0 {
+				r.buf.WriteByte(',')
+			}
+			r.newline(depth + 1)
+			r.render(ev, depth+1)
+		}
+		r.newline(depth)
+		r.buf.WriteByte(']')
+	default:
+		fmt.Fprintf(&r.buf, "%v", v)
+	}
+}
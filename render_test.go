@@ -0,0 +1,44 @@
+package simple
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	v := Struct{
+		"name": String("ann"),
+		"tags": Array{Bool(true), nil, Number(math.Inf(1))},
+		"meta": Struct{"n": Int(1), "deep": Array{Int(2)}},
+		"none": Struct{},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, v, WithPalette(Palette{})))
+	require.Equal(t, `{
+  "meta": {
+    "deep": [
+      2
+    ],
+    "n": 1
+  },
+  "name": "ann",
+  "none": {},
+  "tags": [
+    true,
+    null,
+    Infinity
+  ]
+}
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, Render(&buf, v, WithPalette(Palette{}), FoldDepth(1), RenderIndent("\t")))
+	require.Equal(t, "{\n\t\"meta\": {...},\n\t\"name\": \"ann\",\n\t\"none\": {},\n\t\"tags\": [...]\n}\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, Render(&buf, Struct{"a": Array{String("x"), Int(1), nil}}))
+	require.Equal(t, "{\n  \x1b[1;34m\"a\"\x1b[0m: [\n    \x1b[32m\"x\"\x1b[0m,\n    \x1b[36m1\x1b[0m,\n    \x1b[90mnull\x1b[0m\n  ]\n}\n", buf.String())
+}
@@ -0,0 +1,153 @@
+package simple
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrUnsupportedMediaType is returned by [FromRequest] when the request body
+// is of a type it does not understand. Handlers usually respond to it with
+// http.StatusUnsupportedMediaType.
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// RequestOption changes the behavior of [FromRequest].
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	maxBytes     int64
+	maxMemory    int64
+	fileContents bool
+	jsonOpts     []JSONOption
+}
+
+// MaxRequestBytes limits how many bytes of the request body are read. Bodies
+// that are larger result in a *[LimitError].
+func MaxRequestBytes(n int64) RequestOption {
+	return func(ro *requestOptions) { ro.maxBytes = n }
+}
+
+// MaxMemory sets how many bytes of a multipart body are kept in memory, the
+// rest of the files are stored on disk. The default is 32MB, like net/http.
+func MaxMemory(n int64) RequestOption {
+	return func(ro *requestOptions) { ro.maxMemory = n }
+}
+
+// IncludeFileContents adds the base64 encoded contents of uploaded files to
+// the values produced for them.
+func IncludeFileContents() RequestOption {
+	return func(ro *requestOptions) { ro.fileContents = true }
+}
+
+// RequestJSONOptions sets the options used to read JSON bodies.
+func RequestJSONOptions(opts ...JSONOption) RequestOption {
+	return func(ro *requestOptions) { ro.jsonOpts = append(ro.jsonOpts, opts...) }
+}
+
+// FromRequest reads the body of r and converts it to a [Value], giving
+// handlers one type of input regardless of how it was sent:
+//
+//   - JSON bodies (application/json and types like application/foo+json) are
+//     read with a [JSONDecoder].
+//   - application/x-www-form-urlencoded bodies are converted with
+//     [FromURLValues].
+//   - multipart/form-data bodies are converted with [FromURLValues] too, and
+//     every file becomes a Struct like
+//     {"filename":"a.png","content_type":"image/png","size":1234}, which also
+//     has a base64 encoded "content" with [IncludeFileContents].
+//
+// Other types of bodies result in [ErrUnsupportedMediaType]. Query
+// parameters are not included, use [FromURLValues] on r.URL.Query() for
+// those.
+func FromRequest(r *http.Request, opts ...RequestOption) (Value, error) {
+	ro := requestOptions{maxMemory: 32 << 20}
+	for _, o := range opts {
+		o(&ro)
+	}
+	if ro.maxBytes > 0 && r.Body != nil {
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{&limitedReader{r: r.Body, n: ro.maxBytes, max: ro.maxBytes}, r.Body}
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedMediaType, r.Header.Get("Content-Type"))
+	}
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return requestJSON(r.Body, ro.jsonOpts)
+	case mediaType == "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		return FromURLValues(r.PostForm), nil
+	case mediaType == "multipart/form-data":
+		if err := r.ParseMultipartForm(ro.maxMemory); err != nil {
+			return nil, err
+		}
+		return multipartValue(r.MultipartForm, ro.fileContents)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnsupportedMediaType, mediaType)
+}
+
+func requestJSON(body io.Reader, opts []JSONOption) (Value, error) {
+	dec := NewJSONDecoder(body, opts...)
+	v, err := dec.Decode()
+	if err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if _, err := dec.Decode(); err != io.EOF {
+		if err == nil {
+			err = errors.New("more than one JSON value in request body")
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+func multipartValue(form *multipart.Form, contents bool) (Value, error) {
+	out := FromURLValues(url.Values(form.Value))
+	for _, k := range sortedKeysOf(form.File) {
+		files := make(Array, 0, len(form.File[k]))
+		for _, fh := range form.File[k] {
+			file := Struct{
+				"filename":     String(fh.Filename),
+				"content_type": String(fh.Header.Get("Content-Type")),
+				"size":         Int(fh.Size),
+			}
+			if contents {
+				data, err := readFile(fh)
+				if err != nil {
+					return nil, fmt.Errorf("cannot read file %q: %w", fh.Filename, err)
+				}
+				file["content"] = String(base64.StdEncoding.EncodeToString(data))
+			}
+			files = append(files, file)
+		}
+		if len(files) == 0 {
+			continue
+		}
+		name, segs := parseURLKey(k)
+		out[name] = insertURLValue(out[name], segs, files)
+	}
+	return out, nil
+}
+
+func readFile(fh *multipart.FileHeader) ([]byte, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
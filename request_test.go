@@ -0,0 +1,76 @@
+package simple
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromRequest(t *testing.T) {
+	newRequest := func(contentType, body string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/?ignored=1", strings.NewReader(body))
+		r.Header.Set("Content-Type", contentType)
+		return r
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		v, err := FromRequest(newRequest("application/json; charset=utf-8", `{"a":[1,2]}`), RequestJSONOptions(UseInt()))
+		require.NoError(t, err)
+		require.Equal(t, Struct{"a": Array{Int(1), Int(2)}}, v)
+
+		v, err = FromRequest(newRequest("application/merge-patch+json", `null`))
+		require.NoError(t, err)
+		require.Nil(t, v)
+
+		_, err = FromRequest(newRequest("application/json", `{} {}`))
+		require.EqualError(t, err, "more than one JSON value in request body")
+
+		_, err = FromRequest(newRequest("application/json", ` `))
+		require.Error(t, err)
+
+		_, err = FromRequest(newRequest("application/json", `{"a":"`+strings.Repeat("x", 100)+`"}`), MaxRequestBytes(64))
+		var le *LimitError
+		require.ErrorAs(t, err, &le)
+	})
+
+	t.Run("form", func(t *testing.T) {
+		v, err := FromRequest(newRequest("application/x-www-form-urlencoded", `name=x&tags[]=a&tags[]=b`))
+		require.NoError(t, err)
+		require.Equal(t, `{"name":"x","tags":["a","b"]}`, v.String())
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		require.NoError(t, mw.WriteField("user[name]", "x"))
+		fw, err := mw.CreateFormFile("docs[]", "a.txt")
+		require.NoError(t, err)
+		_, err = fw.Write([]byte("hello"))
+		require.NoError(t, err)
+		require.NoError(t, mw.Close())
+
+		v, err := FromRequest(newRequest(mw.FormDataContentType(), body.String()), IncludeFileContents())
+		require.NoError(t, err)
+		require.Equal(t, Struct{
+			"user": Struct{"name": String("x")},
+			"docs": Array{Struct{
+				"filename":     String("a.txt"),
+				"content_type": String("application/octet-stream"),
+				"size":         Int(5),
+				"content":      String("aGVsbG8="),
+			}},
+		}, v)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := FromRequest(newRequest("text/plain", `hi`))
+		require.ErrorIs(t, err, ErrUnsupportedMediaType)
+		_, err = FromRequest(newRequest("", ``))
+		require.ErrorIs(t, err, ErrUnsupportedMediaType)
+	})
+}
@@ -0,0 +1,111 @@
+package simple
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ResponseOption changes the behavior of [WriteJSONResponse].
+type ResponseOption func(*responseOptions)
+
+type responseOptions struct {
+	indent  string
+	request *http.Request
+	minGzip int
+}
+
+// ResponseIndent makes [WriteJSONResponse] indent the JSON, with one indent
+// per level of nesting.
+func ResponseIndent(indent string) ResponseOption {
+	return func(ro *responseOptions) { ro.indent = indent }
+}
+
+// NegotiateGzip makes [WriteJSONResponse] compress the response with gzip if
+// the Accept-Encoding header of r allows it. Responses smaller than minSize
+// bytes are not worth compressing and are sent as they are.
+func NegotiateGzip(r *http.Request, minSize int) ResponseOption {
+	return func(ro *responseOptions) {
+		ro.request = r
+		ro.minGzip = minSize
+	}
+}
+
+// WriteJSONResponse writes v as the JSON body of a response with the given
+// status code. The value is encoded with a default [Encoder] before anything is
+// written, so if it cannot be encoded, the error is returned and w is left
+// untouched for the caller to write an error response.
+func WriteJSONResponse(w http.ResponseWriter, status int, v Value, opts ...ResponseOption) error {
+	var ro responseOptions
+	for _, o := range opts {
+		o(&ro)
+	}
+	var body bytes.Buffer
+	if err := NewEncoder(&body).Encode(v); err != nil {
+		return err
+	}
+	if ro.indent != "" {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, body.Bytes(), "", ro.indent); err != nil {
+			return err
+		}
+		body = indented
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", "application/json; charset=utf-8")
+	h.Set("X-Content-Type-Options", "nosniff")
+	if ro.request != nil {
+		h.Add("Vary", "Accept-Encoding")
+		if body.Len() >= ro.minGzip && acceptsGzip(ro.request.Header.Get("Accept-Encoding")) {
+			h.Set("Content-Encoding", "gzip")
+			h.Del("Content-Length")
+			w.WriteHeader(status)
+			gz := gzip.NewWriter(w)
+			if _, err := gz.Write(body.Bytes()); err != nil {
+				return err
+			}
+			return gz.Close()
+		}
+	}
+	h.Set("Content-Length", strconv.Itoa(body.Len()))
+	w.WriteHeader(status)
+	_, err := w.Write(body.Bytes())
+	return err
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip. An
+// explicit gzip entry takes precedence over the * wildcard, whatever order
+// they come in.
+func acceptsGzip(header string) bool {
+	gzipQ, starQ := -1.0, -1.0
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			k, v, ok := strings.Cut(param, "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(k), "q") {
+				continue
+			}
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				q = f
+			}
+		}
+		if coding == "gzip" {
+			gzipQ = max(gzipQ, q)
+		} else {
+			starQ = max(starQ, q)
+		}
+	}
+	if gzipQ >= 0 {
+		return gzipQ > 0
+	}
+	return starQ > 0
+}
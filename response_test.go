@@ -0,0 +1,70 @@
+package simple
+
+import (
+	"compress/gzip"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSONResponse(t *testing.T) {
+	v := Struct{"ok": Bool(true), "items": Array{Int(1)}}
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, WriteJSONResponse(rec, http.StatusCreated, v))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+	require.Equal(t, "24", rec.Header().Get("Content-Length"))
+	require.Equal(t, `{"items":[1],"ok":true}`+"\n", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, WriteJSONResponse(rec, http.StatusOK, v, ResponseIndent("  ")))
+	require.Equal(t, "{\n  \"items\": [\n    1\n  ],\n  \"ok\": true\n}\n", rec.Body.String())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "br;q=1.0, gzip;q=0.8")
+	rec = httptest.NewRecorder()
+	require.NoError(t, WriteJSONResponse(rec, http.StatusOK, v, NegotiateGzip(req, 0)))
+	require.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	require.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+	gz, err := gzip.NewReader(rec.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	require.Equal(t, `{"items":[1],"ok":true}`+"\n", string(body))
+
+	// too small to be worth it
+	rec = httptest.NewRecorder()
+	require.NoError(t, WriteJSONResponse(rec, http.StatusOK, v, NegotiateGzip(req, 1024)))
+	require.Empty(t, rec.Header().Get("Content-Encoding"))
+
+	rec = httptest.NewRecorder()
+	require.Error(t, WriteJSONResponse(rec, http.StatusOK, Number(math.NaN())))
+	require.Empty(t, rec.Header())
+	require.False(t, rec.Flushed)
+	require.Zero(t, rec.Body.Len())
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	for header, want := range map[string]bool{
+		"":                     false,
+		"gzip":                 true,
+		"deflate, GZIP":        true,
+		"gzip;q=0":             false,
+		"*":                    true,
+		"identity, br;q=0.5":   false,
+		"gzip ; q=0.001, br":   true,
+		"compress;q=1, gzip;":  true,
+		"*;q=1, gzip;q=0":      false,
+		"gzip;q=0, *":          false,
+		"*;q=0, gzip;q=0.5":    true,
+		"*;q=0":                false,
+		"br, gzip;level=1;q=0": false,
+	} {
+		require.Equal(t, want, acceptsGzip(header), header)
+	}
+}
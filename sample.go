@@ -0,0 +1,49 @@
+package simple
+
+import "math/rand/v2"
+
+// Keys of the Struct returned by [SampleFirst] and [SampleRandom].
+const (
+	SampleCountKey = "count"
+	SampleItemsKey = "items"
+)
+
+// SampleFirst reduces a to its first n elements, so that a huge Array can be
+// attached to a trace. The result is a Struct with the number of elements in a
+// under [SampleCountKey] and the sampled elements under [SampleItemsKey]. The
+// elements are not copied, but appending to the sample does not modify a.
+func SampleFirst(a Array, n int) Struct {
+	n = max(0, min(n, len(a)))
+	return sample(len(a), a[:n:n])
+}
+
+// SampleRandom is like [SampleFirst], but picks n elements at random. The same
+// seed always picks the same elements from an Array of the same length, so
+// samples are reproducible. The sampled elements keep their order in a.
+func SampleRandom(a Array, n int, seed uint64) Struct {
+	n = max(0, min(n, len(a)))
+	rng := rand.New(rand.NewPCG(seed, seed))
+	// Floyd's algorithm picks n distinct indexes in n steps
+	picked := make(map[int]bool, n)
+	for j := len(a) - n; j < len(a); j++ {
+		i := rng.IntN(j + 1)
+		if picked[i] {
+			i = j
+		}
+		picked[i] = true
+	}
+	items := make(Array, 0, n)
+	for i, ev := range a {
+		if picked[i] {
+			items = append(items, ev)
+		}
+	}
+	return sample(len(a), items)
+}
+
+func sample(count int, items Array) Struct {
+	return Struct{
+		SampleCountKey: Int(count),
+		SampleItemsKey: items,
+	}
+}
@@ -0,0 +1,37 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleFirst(t *testing.T) {
+	a := Array{Int(1), Int(2), Int(3)}
+	require.Equal(t, Struct{"count": Int(3), "items": Array{Int(1), Int(2)}}, SampleFirst(a, 2))
+	require.Equal(t, Struct{"count": Int(3), "items": a}, SampleFirst(a, 10))
+	require.Equal(t, Struct{"count": Int(3), "items": Array{}}, SampleFirst(a, -1))
+
+	s := SampleFirst(a, 1)
+	s[SampleItemsKey] = append(s[SampleItemsKey].(Array), Int(9))
+	require.Equal(t, Array{Int(1), Int(2), Int(3)}, a)
+}
+
+func TestSampleRandom(t *testing.T) {
+	a := Array{}
+	for i := range 100 {
+		a = append(a, Int(i))
+	}
+	s := SampleRandom(a, 10, 42)
+	require.Equal(t, s, SampleRandom(a, 10, 42))
+	require.Equal(t, Int(100), s[SampleCountKey])
+	items := s[SampleItemsKey].(Array)
+	require.Len(t, items, 10)
+	require.Equal(t, items, Unique(items))
+	for i := 1; i < len(items); i++ {
+		require.Less(t, items[i-1], items[i])
+	}
+
+	require.Equal(t, Struct{"count": Int(2), "items": Array{Int(0), Int(1)}}, SampleRandom(a[:2], 5, 1))
+	require.Equal(t, Struct{"count": Int(0), "items": Array{}}, SampleRandom(nil, 5, 1))
+}
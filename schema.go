@@ -0,0 +1,521 @@
+package simple
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError describes one way in which a value did not conform to a
+// schema.
+type ValidationError struct {
+	// Path is where in the value the problem was found.
+	Path Path
+	// Keyword is the schema keyword that failed, like "type" or "required".
+	Keyword string
+	// Message is a human readable description of the problem.
+	Message string
+}
+
+func (v ValidationError) Error() string {
+	path := v.Path.String()
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("%s: %s", path, v.Message)
+}
+
+// Pointer returns Path as a JSON Pointer, see [ErrorPointer].
+func (v ValidationError) Pointer() string {
+	return v.Path.Pointer()
+}
+
+// Validate checks v against a JSON Schema and returns every problem that it
+// finds. A nil result means v is valid.
+//
+// Only a practical subset of JSON Schema is supported: boolean schemas, type
+// (including "integer"), enum, const, minimum, maximum, exclusiveMinimum,
+// exclusiveMaximum, multipleOf, minLength, maxLength, pattern, minItems,
+// maxItems, items, required, properties, additionalProperties, allOf, anyOf,
+// oneOf and not. Unknown keywords are ignored, and $ref is not resolved.
+func Validate(v Value, schema Value) []ValidationError {
+	var sv schemaValidator
+	sv.validate(Path{}, v, schema)
+	return sv.errs
+}
+
+type schemaValidator struct {
+	errs []ValidationError
+
+	// openapi enables the OpenAPI specific "nullable" keyword, and makes
+	// "format" an assertion instead of an annotation.
+	openapi bool
+}
+
+func (sv *schemaValidator) fail(path Path, keyword, format string, args ...any) {
+	sv.errs = append(sv.errs, ValidationError{
+		Path:    path,
+		Keyword: keyword,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// schemaType reports the JSON Schema type name of v. Integers are reported
+// as "integer" which also satisfies "number".
+func schemaType(v Value) string {
+	switch tv := v.(type) {
+	case nil:
+		return "null"
+	case Struct:
+		return "object"
+	case Array:
+		return "array"
+	case String:
+		return "string"
+	case Bool:
+		return "boolean"
+	case Number, RawNumber, Int:
+		f, _ := numberValue(tv)
+		if f == math.Trunc(f) && !math.IsInf(f, 0) {
+			return "integer"
+		}
+		return "number"
+	}
+	return kindName(v)
+}
+
+func typeMatches(v Value, want string) bool {
+	got := schemaType(v)
+	return got == want || (want == "number" && got == "integer")
+}
+
+func (sv *schemaValidator) validate(path Path, v Value, schema Value) {
+	switch ts := schema.(type) {
+	case Bool:
+		if !ts {
+			sv.fail(path, "false", "no value is allowed here")
+		}
+		return
+	case Struct:
+		sv.validateStruct(path, v, ts)
+	}
+}
+
+func (sv *schemaValidator) validateStruct(path Path, v Value, schema Struct) {
+	if sv.openapi && v == nil && schema["nullable"] == Bool(true) {
+		return
+	}
+	if _, ok := schema["type"]; ok {
+		types := schemaTypes(schema)
+		matched := false
+		for _, want := range types {
+			if typeMatches(v, want) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			sv.fail(path, "type", "expected %s, got %s", strings.Join(types, " or "), schemaType(v))
+			// nothing else is going to be meaningful
+			return
+		}
+	}
+	if enum, ok := schema["enum"].(Array); ok {
+		found := false
+		for _, e := range enum {
+			if equal(v, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			sv.fail(path, "enum", "%s is not one of the allowed values", shortString(v))
+		}
+	}
+	if c, ok := schema["const"]; ok && !equal(v, c) {
+		sv.fail(path, "const", "expected %s, got %s", shortString(c), shortString(v))
+	}
+	sv.validateCombinators(path, v, schema)
+
+	switch tv := v.(type) {
+	case Number, RawNumber, Int:
+		f, _ := numberValue(tv)
+		sv.validateNumber(path, f, schema)
+	case String:
+		sv.validateString(path, string(tv), schema)
+	case Array:
+		sv.validateArray(path, tv, schema)
+	case Struct:
+		sv.validateObject(path, tv, schema)
+	}
+}
+
+// sub runs a validation that does not report its errors, only whether or not
+// there were any.
+func (sv *schemaValidator) sub(path Path, v Value, schema Value) bool {
+	inner := schemaValidator{openapi: sv.openapi}
+	inner.validate(path, v, schema)
+	return len(inner.errs) == 0
+}
+
+func (sv *schemaValidator) validateCombinators(path Path, v Value, schema Struct) {
+	if all, ok := schema["allOf"].(Array); ok {
+		for _, s := range all {
+			sv.validate(path, v, s)
+		}
+	}
+	if anyOf, ok := schema["anyOf"].(Array); ok {
+		matched := false
+		for _, s := range anyOf {
+			if sv.sub(path, v, s) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			sv.fail(path, "anyOf", "value does not match any of the allowed schemas")
+		}
+	}
+	if oneOf, ok := schema["oneOf"].(Array); ok {
+		matches := 0
+		for _, s := range oneOf {
+			if sv.sub(path, v, s) {
+				matches++
+			}
+		}
+		if matches != 1 {
+			sv.fail(path, "oneOf", "value matches %d of the schemas, expected exactly 1", matches)
+		}
+	}
+	if not, ok := schema["not"]; ok && sv.sub(path, v, not) {
+		sv.fail(path, "not", "value matches a schema that it must not match")
+	}
+}
+
+func schemaNumber(schema Struct, keyword string) (float64, bool) {
+	n, ok := numberValue(schema[keyword])
+	return float64(n), ok
+}
+
+func (sv *schemaValidator) validateNumber(path Path, n float64, schema Struct) {
+	if min, ok := schemaNumber(schema, "minimum"); ok && n < min {
+		sv.fail(path, "minimum", "%s is less than the minimum of %s", formatNumber(n), formatNumber(min))
+	}
+	if max, ok := schemaNumber(schema, "maximum"); ok && n > max {
+		sv.fail(path, "maximum", "%s is greater than the maximum of %s", formatNumber(n), formatNumber(max))
+	}
+	if min, ok := schemaNumber(schema, "exclusiveMinimum"); ok && n <= min {
+		sv.fail(path, "exclusiveMinimum", "%s must be greater than %s", formatNumber(n), formatNumber(min))
+	}
+	if max, ok := schemaNumber(schema, "exclusiveMaximum"); ok && n >= max {
+		sv.fail(path, "exclusiveMaximum", "%s must be less than %s", formatNumber(n), formatNumber(max))
+	}
+	if m, ok := schemaNumber(schema, "multipleOf"); ok && m > 0 {
+		if !isMultipleOf(n, m) {
+			sv.fail(path, "multipleOf", "%s is not a multiple of %s", formatNumber(n), formatNumber(m))
+		}
+	}
+}
+
+// isMultipleOf reports whether n is a multiple of m, taking both as the
+// shortest decimals that round to them, so 0.3 is a multiple of 0.1 even
+// though 0.3/0.1 is not an integer in floating point.
+func isMultipleOf(n, m float64) bool {
+	rn, ok := new(big.Rat).SetString(strconv.FormatFloat(n, 'g', -1, 64))
+	if !ok {
+		return false
+	}
+	rm, ok := new(big.Rat).SetString(strconv.FormatFloat(m, 'g', -1, 64))
+	if !ok {
+		return false
+	}
+	return rn.Quo(rn, rm).IsInt()
+}
+
+func (sv *schemaValidator) validateString(path Path, s string, schema Struct) {
+	length := utf8.RuneCountInString(s)
+	if min, ok := schemaNumber(schema, "minLength"); ok && float64(length) < min {
+		sv.fail(path, "minLength", "string is shorter than %s characters", formatNumber(min))
+	}
+	if max, ok := schemaNumber(schema, "maxLength"); ok && float64(length) > max {
+		sv.fail(path, "maxLength", "string is longer than %s characters", formatNumber(max))
+	}
+	if format, ok := schema["format"].(String); ok && sv.openapi {
+		if check, ok := stringFormats[string(format)]; ok && !check(s) {
+			sv.fail(path, "format", "string is not a valid %s", string(format))
+		}
+	}
+	if pattern, ok := schema["pattern"].(String); ok {
+		re, err := regexp.Compile(string(pattern))
+		if err != nil {
+			sv.fail(path, "pattern", "schema pattern %q is invalid: %s", string(pattern), err.Error())
+		} else if !re.MatchString(s) {
+			sv.fail(path, "pattern", "string does not match pattern %q", string(pattern))
+		}
+	}
+}
+
+func (sv *schemaValidator) validateArray(path Path, a Array, schema Struct) {
+	if min, ok := schemaNumber(schema, "minItems"); ok && float64(len(a)) < min {
+		sv.fail(path, "minItems", "array has fewer than %s items", formatNumber(min))
+	}
+	if max, ok := schemaNumber(schema, "maxItems"); ok && float64(len(a)) > max {
+		sv.fail(path, "maxItems", "array has more than %s items", formatNumber(max))
+	}
+	if items, ok := schema["items"]; ok {
+		for i, e := range a {
+			sv.validate(path.Index(i), e, items)
+		}
+	}
+}
+
+func (sv *schemaValidator) validateObject(path Path, s Struct, schema Struct) {
+	if required, ok := schema["required"].(Array); ok {
+		for _, r := range required {
+			k, ok := r.(String)
+			if !ok {
+				continue
+			}
+			if _, present := s[string(k)]; !present {
+				sv.fail(path, "required", "missing required property %q", string(k))
+			}
+		}
+	}
+	properties, _ := schema["properties"].(Struct)
+	additional, hasAdditional := schema["additionalProperties"]
+	for _, k := range sortedKeys(s) {
+		if ps, ok := properties[k]; ok {
+			sv.validate(path.Key(k), s[k], ps)
+			continue
+		}
+		if !hasAdditional {
+			continue
+		}
+		if b, ok := additional.(Bool); ok && !bool(b) {
+			sv.fail(path.Key(k), "additionalProperties", "property %q is not allowed", k)
+			continue
+		}
+		sv.validate(path.Key(k), s[k], additional)
+	}
+}
+
+// InferSchema generates a JSON Schema describing the shape of the given
+// example values. Properties that are missing from some of the examples are
+// left out of "required", values that are sometimes null get a "null" type
+// added, and array item schemas are inferred from every element of every
+// array seen at that location. The result can be passed to [Validate].
+func InferSchema(examples ...Value) Struct {
+	var si schemaInference
+	for _, e := range examples {
+		si.add(e)
+	}
+	return si.schema()
+}
+
+// schemaInference accumulates what has been seen at one location of the
+// examples given to InferSchema.
+type schemaInference struct {
+	types map[string]bool
+
+	// for objects
+	objects    int
+	properties map[string]*schemaInference
+	seen       map[string]int
+
+	// for arrays
+	items *schemaInference
+}
+
+func (si *schemaInference) add(v Value) {
+	if si.types == nil {
+		si.types = map[string]bool{}
+	}
+	si.types[schemaType(v)] = true
+	switch tv := v.(type) {
+	case Struct:
+		si.objects++
+		if si.properties == nil {
+			si.properties = map[string]*schemaInference{}
+			si.seen = map[string]int{}
+		}
+		for k, ev := range tv {
+			if si.properties[k] == nil {
+				si.properties[k] = &schemaInference{}
+			}
+			si.properties[k].add(ev)
+			si.seen[k]++
+		}
+	case Array:
+		if si.items == nil {
+			si.items = &schemaInference{}
+		}
+		for _, ev := range tv {
+			si.items.add(ev)
+		}
+	}
+}
+
+func (si *schemaInference) schema() Struct {
+	out := Struct{}
+	if len(si.types) == 0 {
+		// nothing was ever seen here, so anything goes
+		return out
+	}
+	if si.types["integer"] && si.types["number"] {
+		delete(si.types, "integer")
+	}
+	var types Array
+	for _, t := range []string{"object", "array", "string", "number", "integer", "boolean", "null"} {
+		if si.types[t] {
+			types = append(types, String(t))
+		}
+	}
+	if len(types) == 1 {
+		out["type"] = types[0]
+	} else {
+		out["type"] = types
+	}
+	if si.properties != nil {
+		props := make(Struct, len(si.properties))
+		var required Array
+		for _, k := range sortedKeysOf(si.properties) {
+			props[k] = si.properties[k].schema()
+			if si.seen[k] == si.objects {
+				required = append(required, String(k))
+			}
+		}
+		out["properties"] = props
+		if len(required) > 0 {
+			out["required"] = required
+		}
+	}
+	if si.items != nil {
+		out["items"] = si.items.schema()
+	}
+	return out
+}
+
+// Coerce converts the leaves of v to the types that schema (a JSON Schema, as
+// understood by [Validate]) expects them to be, which is useful when
+// ingesting CSV, form data and the like where everything arrives as strings.
+// Scalars are converted with [AsString], [AsBool], [AsInt64] and [AsFloat64].
+// Numbers given for a string with a "date-time" or "date" format are treated
+// as seconds since the Unix epoch and formatted as RFC 3339 in UTC.
+//
+// Null is never coerced, and values that already match one of the expected
+// types are left alone. Every value that could not be coerced is reported,
+// and left unchanged in the returned copy of v.
+func Coerce(v Value, schema Value) (Value, []ValidationError) {
+	var sc schemaCoercer
+	out := sc.coerce(Path{}, v, schema)
+	return out, sc.errs
+}
+
+type schemaCoercer struct {
+	errs []ValidationError
+}
+
+func schemaTypes(schema Struct) []string {
+	switch tt := schema["type"].(type) {
+	case String:
+		return []string{string(tt)}
+	case Array:
+		var types []string
+		for _, e := range tt {
+			if s, ok := e.(String); ok {
+				types = append(types, string(s))
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func (sc *schemaCoercer) coerce(path Path, v Value, schema Value) Value {
+	ss, ok := schema.(Struct)
+	if !ok || v == nil {
+		return Clone(v)
+	}
+	types := schemaTypes(ss)
+	matched := len(types) == 0
+	for _, t := range types {
+		if typeMatches(v, t) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		var lastErr error
+		for _, t := range types {
+			nv, err := coerceScalar(v, t, ss)
+			if err == nil {
+				return nv
+			}
+			lastErr = err
+		}
+		sc.errs = append(sc.errs, ValidationError{
+			Path:    path,
+			Keyword: "type",
+			Message: lastErr.Error(),
+		})
+		return Clone(v)
+	}
+
+	switch tv := v.(type) {
+	case Struct:
+		properties, _ := ss["properties"].(Struct)
+		additional := ss["additionalProperties"]
+		out := make(Struct, len(tv))
+		for _, k := range sortedKeys(tv) {
+			if ps, ok := properties[k]; ok {
+				out[k] = sc.coerce(path.Key(k), tv[k], ps)
+			} else {
+				out[k] = sc.coerce(path.Key(k), tv[k], additional)
+			}
+		}
+		return out
+	case Array:
+		out := make(Array, len(tv))
+		for i, ev := range tv {
+			out[i] = sc.coerce(path.Index(i), ev, ss["items"])
+		}
+		return out
+	}
+	return v
+}
+
+func coerceScalar(v Value, t string, schema Struct) (Value, error) {
+	switch t {
+	case "number":
+		f, err := AsFloat64(v)
+		return Number(f), err
+	case "integer":
+		i, err := AsInt64(v)
+		return Number(i), err
+	case "boolean":
+		b, err := AsBool(v)
+		return Bool(b), err
+	case "string":
+		if n, ok := numberValue(v); ok {
+			switch schema["format"] {
+			case String("date-time"):
+				return String(epochTime(n).Format(time.RFC3339Nano)), nil
+			case String("date"):
+				return String(epochTime(n).Format(time.DateOnly)), nil
+			}
+		}
+		s, err := AsString(v)
+		return String(s), err
+	}
+	return nil, coercionError{from: v, to: t}
+}
+
+func epochTime(seconds float64) time.Time {
+	whole, frac := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(frac*1e9)).UTC()
+}
@@ -0,0 +1,141 @@
+package simple
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustFromJSON(t *testing.T, s string) Value {
+	t.Helper()
+	v, err := FromJSON(json.RawMessage(s))
+	require.NoError(t, err)
+	return v
+}
+
+func TestValidate(t *testing.T) {
+	schema := mustFromJSON(t, `{
+		"type": "object",
+		"required": ["id", "name"],
+		"additionalProperties": false,
+		"properties": {
+			"id": {"type": "integer", "minimum": 1},
+			"name": {"type": "string", "minLength": 2, "pattern": "^[a-z]+$"},
+			"status": {"enum": ["active", "disabled"]},
+			"score": {"type": "number", "exclusiveMaximum": 10, "multipleOf": 0.5},
+			"tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}},
+			"parent": {"type": ["object", "null"]}
+		}
+	}`)
+
+	require.Empty(t, Validate(mustFromJSON(t, `{"id": 3, "name": "ann", "status": "active", "score": 9.5, "tags": ["a"], "parent": null}`), schema))
+
+	errs := Validate(mustFromJSON(t, `{"id": 1.5, "name": "A", "status": "gone", "score": 10, "tags": ["a", 2, "c"], "parent": [], "extra": true}`), schema)
+	var msgs []string
+	for _, e := range errs {
+		msgs = append(msgs, e.Error())
+	}
+	require.Equal(t, []string{
+		`.extra: property "extra" is not allowed`,
+		`.id: expected integer, got number`,
+		`.name: string is shorter than 2 characters`,
+		`.name: string does not match pattern "^[a-z]+$"`,
+		`.parent: expected object or null, got array`,
+		`.score: 10 must be less than 10`,
+		`.status: "gone" is not one of the allowed values`,
+		`.tags: array has more than 2 items`,
+		`.tags[1]: expected string, got integer`,
+	}, msgs)
+
+	errs = Validate(Struct{}, schema)
+	require.Len(t, errs, 2)
+	require.Equal(t, "required", errs[0].Keyword)
+	require.Equal(t, `(root): missing required property "id"`, errs[0].Error())
+
+	require.Len(t, Validate(Number(1), Bool(false)), 1)
+	require.Empty(t, Validate(Number(1), Bool(true)))
+}
+
+func TestValidateMultipleOf(t *testing.T) {
+	schema := Struct{"multipleOf": Number(0.1)}
+	for _, n := range []Value{Number(0.3), Number(0.7), Number(-1.2), Int(5), RawNumber("2.30")} {
+		require.Empty(t, Validate(n, schema), n.String())
+	}
+	errs := Validate(Number(0.35), schema)
+	require.Len(t, errs, 1)
+	require.Equal(t, "(root): 0.35 is not a multiple of 0.1", errs[0].Error())
+	require.Empty(t, Validate(Number(1e300), Struct{"multipleOf": Number(1e-5)}))
+}
+
+func TestInferSchema(t *testing.T) {
+	examples := []Value{
+		mustFromJSON(t, `{"id": 1, "name": "ann", "tags": ["a"], "score": 1}`),
+		mustFromJSON(t, `{"id": 2, "name": null, "tags": [], "score": 2.5, "extra": {"x": true}}`),
+	}
+	schema := InferSchema(examples...)
+	require.Equal(t, mustFromJSON(t, `{
+		"type": "object",
+		"required": ["id", "name", "score", "tags"],
+		"properties": {
+			"extra": {"type": "object", "required": ["x"], "properties": {"x": {"type": "boolean"}}},
+			"id": {"type": "integer"},
+			"name": {"type": ["string", "null"]},
+			"score": {"type": "number"},
+			"tags": {"type": "array", "items": {"type": "string"}}
+		}
+	}`), schema)
+	for _, e := range examples {
+		require.Empty(t, Validate(e, schema))
+	}
+	require.Equal(t, Struct{}, InferSchema())
+}
+
+func TestSchemaCoerce(t *testing.T) {
+	schema := mustFromJSON(t, `{
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer"},
+			"price": {"type": "number"},
+			"active": {"type": "boolean"},
+			"zip": {"type": "string"},
+			"created": {"type": "string", "format": "date-time"},
+			"day": {"type": "string", "format": "date"},
+			"sizes": {"type": "array", "items": {"type": ["integer", "null"]}},
+			"note": {"type": ["null", "string"]}
+		},
+		"additionalProperties": {"type": "boolean"}
+	}`)
+	input := Struct{
+		"id":      String("42"),
+		"price":   String("9.99"),
+		"active":  String("true"),
+		"zip":     Number(2134),
+		"created": Number(1700000000.5),
+		"day":     Number(86400),
+		"sizes":   Array{String("1"), nil, String("3")},
+		"note":    nil,
+		"flag":    String("0"),
+	}
+	got, errs := Coerce(input, schema)
+	require.Empty(t, errs)
+	require.Equal(t, Struct{
+		"id":      Number(42),
+		"price":   Number(9.99),
+		"active":  Bool(true),
+		"zip":     String("2134"),
+		"created": String("2023-11-14T22:13:20.5Z"),
+		"day":     String("1970-01-02"),
+		"sizes":   Array{Number(1), nil, Number(3)},
+		"note":    nil,
+		"flag":    Bool(false),
+	}, got)
+	require.Empty(t, Validate(got, schema))
+	require.Equal(t, String("42"), input["id"], "input must not be modified")
+
+	got, errs = Coerce(Struct{"id": String("4.5"), "active": String("yes")}, schema)
+	require.Len(t, errs, 2)
+	require.Equal(t, `.active: cannot coerce string "yes" to bool: not a boolean`, errs[0].Error())
+	require.Equal(t, `.id: cannot coerce string "4.5" to int64: has a fractional part`, errs[1].Error())
+	require.Equal(t, Struct{"id": String("4.5"), "active": String("yes")}, got)
+}
@@ -0,0 +1,181 @@
+package simple
+
+import (
+	"fmt"
+	"strings"
+)
+
+// selection maps the selected keys of a Struct to what is selected inside of
+// them. A nil selection selects everything.
+type selection map[string]selection
+
+type selectionSyntaxError struct {
+	selection string
+	offset    int
+	msg       string
+}
+
+func (s selectionSyntaxError) Error() string {
+	return fmt.Sprintf("invalid selection %q at offset %d: %s", s.selection, s.offset, s.msg)
+}
+
+// Select returns a copy of v that only has the fields named in selection,
+// like a GraphQL selection set, to implement sparse fieldsets over responses.
+// Fields are separated by commas or whitespace, and a field can be followed
+// by the selection of its own fields in braces. The outer braces are
+// optional, and a dotted name is short for a nested selection, so these are
+// all the same:
+//
+//	{user{id,name},items{sku}}
+//	user { id name } items { sku }
+//	user.id, user.name, items.sku
+//
+// Selections apply to every element of an Array. Fields that are not present
+// are left out, and a selection inside of a scalar is ignored.
+func Select(v Value, selection string) (Value, error) {
+	sel, err := parseSelection(selection)
+	if err != nil {
+		return nil, err
+	}
+	return sel.apply(v), nil
+}
+
+func (sel selection) apply(v Value) Value {
+	if sel == nil {
+		return Clone(v)
+	}
+	switch tv := v.(type) {
+	case Struct:
+		out := make(Struct, len(sel))
+		for k, sub := range sel {
+			if ev, ok := tv[k]; ok {
+				out[k] = sub.apply(ev)
+			}
+		}
+		return out
+	case Array:
+		out := make(Array, len(tv))
+		for i, ev := range tv {
+			out[i] = sel.apply(ev)
+		}
+		return out
+	}
+	return v
+}
+
+// merge adds the fields of other to sel.
+func (sel selection) merge(k string, other selection) {
+	existing, ok := sel[k]
+	switch {
+	case !ok:
+		sel[k] = other
+	case existing == nil || other == nil:
+		// selecting everything wins
+		sel[k] = nil
+	default:
+		for ok, os := range other {
+			existing.merge(ok, os)
+		}
+	}
+}
+
+type selectionParser struct {
+	s string
+	i int
+}
+
+func parseSelection(s string) (selection, error) {
+	p := &selectionParser{s: s}
+	p.skipSpace()
+	braced := p.i < len(s) && s[p.i] == '{'
+	if braced {
+		p.i++
+	}
+	sel, err := p.fields(braced)
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.i < len(s) {
+		return nil, p.errorf("unexpected %q", s[p.i:p.i+1])
+	}
+	return sel, nil
+}
+
+func (p *selectionParser) errorf(format string, args ...any) error {
+	return selectionSyntaxError{selection: p.s, offset: p.i, msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *selectionParser) skipSpace() {
+	for p.i < len(p.s) && (p.s[p.i] == ',' || strings.IndexByte(" \t\r\n", p.s[p.i]) >= 0) {
+		p.i++
+	}
+}
+
+// fields parses fields until the end of the input, or until the closing
+// brace if braced is set.
+func (p *selectionParser) fields(braced bool) (selection, error) {
+	sel := selection{}
+	for {
+		p.skipSpace()
+		if p.i >= len(p.s) {
+			if braced {
+				return nil, p.errorf(`missing "}"`)
+			}
+			break
+		}
+		if p.s[p.i] == '}' {
+			if !braced {
+				return nil, p.errorf(`unexpected "}"`)
+			}
+			p.i++
+			break
+		}
+		names, err := p.names()
+		if err != nil {
+			return nil, err
+		}
+		var sub selection
+		p.skipSpaceOnly()
+		if p.i < len(p.s) && p.s[p.i] == '{' {
+			p.i++
+			if sub, err = p.fields(true); err != nil {
+				return nil, err
+			}
+		}
+		// a.b.c{d} is a{b{c{d}}}
+		for j := len(names) - 1; j > 0; j-- {
+			sub = selection{names[j]: sub}
+		}
+		sel.merge(names[0], sub)
+	}
+	if len(sel) == 0 {
+		return nil, p.errorf("empty selection")
+	}
+	return sel, nil
+}
+
+func (p *selectionParser) skipSpaceOnly() {
+	for p.i < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.i]) >= 0 {
+		p.i++
+	}
+}
+
+// names parses a dotted field name.
+func (p *selectionParser) names() ([]string, error) {
+	var names []string
+	for {
+		start := p.i
+		for p.i < len(p.s) && strings.IndexByte("{},. \t\r\n", p.s[p.i]) < 0 {
+			p.i++
+		}
+		if p.i == start {
+			return nil, p.errorf("expected a field name")
+		}
+		names = append(names, p.s[start:p.i])
+		if p.i >= len(p.s) || p.s[p.i] != '.' {
+			return names, nil
+		}
+		p.i++
+	}
+}
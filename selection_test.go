@@ -0,0 +1,48 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelect(t *testing.T) {
+	v := mustFromJSON(t, `{
+		"user": {"id": 1, "name": "ann", "email": "a@example.com", "address": {"city": "x", "zip": "1"}},
+		"items": [{"sku": "a", "price": 1}, {"sku": "b", "price": 2}, "odd"],
+		"total": 3
+	}`)
+	for _, selection := range []string{
+		`{user{id,name},items{sku}}`,
+		"user { id name }\nitems { sku }",
+		`user.id, user.name, items.sku`,
+		`user{id} user{name} items.sku missing`,
+	} {
+		t.Run(selection, func(t *testing.T) {
+			got, err := Select(v, selection)
+			require.NoError(t, err)
+			require.Equal(t, `{"items":[{"sku":"a"},{"sku":"b"},"odd"],"user":{"id":1,"name":"ann"}}`, got.String())
+		})
+	}
+
+	got, err := Select(v, `user{address, id{nothing}}, total`)
+	require.NoError(t, err)
+	require.Equal(t, `{"total":3,"user":{"address":{"city":"x","zip":"1"},"id":1}}`, got.String())
+
+	// selecting everything wins over a narrower selection
+	got, err = Select(v, `user.address.city user.address`)
+	require.NoError(t, err)
+	require.Equal(t, `{"user":{"address":{"city":"x","zip":"1"}}}`, got.String())
+
+	for selection, msg := range map[string]string{
+		``:          `invalid selection "" at offset 0: empty selection`,
+		`{user{id}`: `invalid selection "{user{id}" at offset 9: missing "}"`,
+		`user}`:     `invalid selection "user}" at offset 4: unexpected "}"`,
+		`user{}`:    `invalid selection "user{}" at offset 6: empty selection`,
+		`a..b`:      `invalid selection "a..b" at offset 2: expected a field name`,
+		`{a} b`:     `invalid selection "{a} b" at offset 4: unexpected "b"`,
+	} {
+		_, err := Select(v, selection)
+		require.EqualError(t, err, msg, selection)
+	}
+}
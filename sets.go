@@ -0,0 +1,45 @@
+package simple
+
+// The set operations below treat Arrays as sets, for reconciling lists like
+// tags or permissions. Elements are compared the same way [Unique] compares
+// them, the results never contain duplicates, and they keep the order in
+// which elements first appear.
+
+// Union returns the elements that are in a or in b.
+func Union(a, b Array) Array {
+	seen := valueSet{}
+	out := Array{}
+	for _, arr := range [2]Array{a, b} {
+		for _, ev := range arr {
+			if seen.add(ev) {
+				out = append(out, ev)
+			}
+		}
+	}
+	return out
+}
+
+// Intersect returns the elements of a that are also in b.
+func Intersect(a, b Array) Array {
+	return filterSet(a, b, true)
+}
+
+// Difference returns the elements of a that are not in b.
+func Difference(a, b Array) Array {
+	return filterSet(a, b, false)
+}
+
+func filterSet(a, b Array, keepShared bool) Array {
+	other := valueSet{}
+	for _, ev := range b {
+		other.add(ev)
+	}
+	seen := valueSet{}
+	out := Array{}
+	for _, ev := range a {
+		if other.contains(ev) == keepShared && seen.add(ev) {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
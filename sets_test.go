@@ -0,0 +1,21 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetOperations(t *testing.T) {
+	a := Array{String("read"), String("write"), Int(1), String("read"), Struct{"x": nil}}
+	b := Array{Number(1), String("admin"), Struct{"x": nil}, String("admin")}
+
+	require.Equal(t, Array{String("read"), String("write"), Int(1), Struct{"x": nil}, String("admin")}, Union(a, b))
+	require.Equal(t, Array{Int(1), Struct{"x": nil}}, Intersect(a, b))
+	require.Equal(t, Array{String("read"), String("write")}, Difference(a, b))
+	require.Equal(t, Array{String("admin")}, Difference(b, a))
+
+	require.Equal(t, Array{}, Union(nil, nil))
+	require.Equal(t, Array{}, Intersect(a, nil))
+	require.Equal(t, Array{String("read"), String("write"), Int(1), Struct{"x": nil}}, Difference(a, nil))
+}
@@ -0,0 +1,183 @@
+package simple
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Matcher describes the expected shape of a [Value]. Matchers are built with
+// the Shape DSL ([Shape], [Each], [Optional], [Num], [Str], ...) and checked
+// with [Match]. They are a lighter weight alternative to [Validate] for
+// internal assertions.
+type Matcher interface {
+	matchShape(path Path, v Value, errs *[]ValidationError)
+	describe() string
+}
+
+// Match checks that v has the shape described by m and returns every mismatch
+// it finds, in the same form as [Validate].
+func Match(v Value, m Matcher) []ValidationError {
+	var errs []ValidationError
+	m.matchShape(Path{}, v, &errs)
+	return errs
+}
+
+type kindMatcher string
+
+func (k kindMatcher) matchShape(path Path, v Value, errs *[]ValidationError) {
+	if k == "any" || kindName(v) == string(k) {
+		return
+	}
+	*errs = append(*errs, ValidationError{
+		Path:    path,
+		Keyword: "type",
+		Message: fmt.Sprintf("expected %s, got %s", k, kindName(v)),
+	})
+}
+
+func (k kindMatcher) describe() string { return string(k) }
+
+// Matchers for each kind of value.
+var (
+	Num     Matcher = kindMatcher("number")
+	Str     Matcher = kindMatcher("string")
+	Boolean Matcher = kindMatcher("bool")
+	Obj     Matcher = kindMatcher("struct")
+	Arr     Matcher = kindMatcher("array")
+	Null    Matcher = kindMatcher("null")
+	Any     Matcher = kindMatcher("any")
+)
+
+// Shape matches a [Struct] whose keys match the given matchers. Keys that are
+// not mentioned in the Shape are allowed. Keys are required unless their
+// matcher is wrapped in [Optional].
+type Shape map[string]Matcher
+
+func (s Shape) matchShape(path Path, v Value, errs *[]ValidationError) {
+	sv, ok := v.(Struct)
+	if !ok {
+		Obj.matchShape(path, v, errs)
+		return
+	}
+	for _, k := range sortedKeysOf(s) {
+		ev, present := sv[k]
+		if !present {
+			if _, ok := s[k].(optionalMatcher); !ok {
+				*errs = append(*errs, ValidationError{
+					Path:    path,
+					Keyword: "required",
+					Message: fmt.Sprintf("missing key %q", k),
+				})
+			}
+			continue
+		}
+		s[k].matchShape(path.Key(k), ev, errs)
+	}
+}
+
+func (s Shape) describe() string { return "struct" }
+
+type optionalMatcher struct{ Matcher }
+
+// Optional marks a key of a [Shape] as optional. When the key is present, its
+// value still has to match m.
+func Optional(m Matcher) Matcher {
+	return optionalMatcher{m}
+}
+
+type eachMatcher struct{ m Matcher }
+
+// Each matches an [Array] where every element matches m.
+func Each(m Matcher) Matcher {
+	return eachMatcher{m}
+}
+
+func (e eachMatcher) matchShape(path Path, v Value, errs *[]ValidationError) {
+	av, ok := v.(Array)
+	if !ok {
+		Arr.matchShape(path, v, errs)
+		return
+	}
+	for i, ev := range av {
+		e.m.matchShape(path.Index(i), ev, errs)
+	}
+}
+
+func (e eachMatcher) describe() string { return "array of " + e.m.describe() }
+
+type oneOfMatcher []Matcher
+
+// OneOf matches a value that matches at least one of ms.
+func OneOf(ms ...Matcher) Matcher {
+	return oneOfMatcher(ms)
+}
+
+// Nullable matches null or anything that m matches.
+func Nullable(m Matcher) Matcher {
+	return oneOfMatcher{Null, m}
+}
+
+func (o oneOfMatcher) matchShape(path Path, v Value, errs *[]ValidationError) {
+	for _, m := range o {
+		var sub []ValidationError
+		m.matchShape(path, v, &sub)
+		if len(sub) == 0 {
+			return
+		}
+	}
+	*errs = append(*errs, ValidationError{
+		Path:    path,
+		Keyword: "oneOf",
+		Message: fmt.Sprintf("expected %s, got %s", o.describe(), kindName(v)),
+	})
+}
+
+func (o oneOfMatcher) describe() string {
+	names := make([]string, len(o))
+	for i, m := range o {
+		names[i] = m.describe()
+	}
+	return strings.Join(names, " or ")
+}
+
+type eqMatcher struct{ v Value }
+
+// Eq matches values equal to v.
+func Eq(v Value) Matcher {
+	return eqMatcher{v}
+}
+
+func (e eqMatcher) matchShape(path Path, v Value, errs *[]ValidationError) {
+	if equal(e.v, v) {
+		return
+	}
+	*errs = append(*errs, ValidationError{
+		Path:    path,
+		Keyword: "const",
+		Message: fmt.Sprintf("expected %s, got %s", shortString(e.v), shortString(v)),
+	})
+}
+
+func (e eqMatcher) describe() string { return shortString(e.v) }
+
+type checkMatcher struct {
+	fn func(Value) error
+}
+
+// Check matches values for which fn returns nil, the message of the returned
+// error is used to describe the mismatch.
+func Check(fn func(v Value) error) Matcher {
+	return checkMatcher{fn}
+}
+
+func (c checkMatcher) matchShape(path Path, v Value, errs *[]ValidationError) {
+	if err := c.fn(v); err != nil {
+		*errs = append(*errs, ValidationError{
+			Path:    path,
+			Keyword: "check",
+			Message: err.Error(),
+		})
+	}
+}
+
+func (c checkMatcher) describe() string { return "custom check" }
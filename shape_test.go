@@ -0,0 +1,44 @@
+package simple
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	positive := Check(func(v Value) error {
+		if n, ok := v.(Number); !ok || n <= 0 {
+			return errors.New("must be a positive number")
+		}
+		return nil
+	})
+	shape := Shape{
+		"id":    positive,
+		"kind":  OneOf(Eq(String("user")), Eq(String("bot"))),
+		"tags":  Each(Str),
+		"meta":  Optional(Obj),
+		"email": Nullable(Str),
+		"owner": Optional(Shape{"id": Num}),
+	}
+
+	require.Empty(t, Match(mustFromJSON(t, `{"id": 1, "kind": "bot", "tags": [], "email": null, "other": 1}`), shape))
+
+	errs := Match(mustFromJSON(t, `{"id": -1, "kind": "cat", "tags": ["a", 1], "meta": [], "owner": {}}`), shape)
+	var msgs []string
+	for _, e := range errs {
+		msgs = append(msgs, e.Error())
+	}
+	require.Equal(t, []string{
+		`(root): missing key "email"`,
+		`.id: must be a positive number`,
+		`.kind: expected "user" or "bot", got string`,
+		`.meta: expected struct, got array`,
+		`.owner: missing key "id"`,
+		`.tags[1]: expected string, got number`,
+	}, msgs)
+
+	require.Len(t, Match(Array{}, shape), 1)
+	require.Empty(t, Match(nil, Any))
+}
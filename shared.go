@@ -0,0 +1,139 @@
+package simple
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Shared holds a document that many goroutines can read and change at the
+// same time without any locking of their own, like live configuration or
+// session state. It keeps the document as an [Immutable] snapshot: readers
+// never wait, and every change builds a new snapshot that is swapped in
+// atomically, so a reader always sees either all or none of a change.
+// Changes are serialized with each other.
+//
+// The zero Shared holds null and is ready to use. A Shared must not be copied
+// after first use.
+type Shared struct {
+	mu       sync.Mutex
+	snap     atomic.Pointer[Immutable]
+	wmu      sync.Mutex
+	watchers []*watcher
+}
+
+type watcher struct {
+	path Path
+	fn   func(Change)
+}
+
+// NewShared returns a Shared holding a deep copy of v.
+func NewShared(v Value) *Shared {
+	var s Shared
+	im := NewImmutable(v)
+	s.snap.Store(&im)
+	return &s
+}
+
+// Snapshot returns the current state of the document, without copying it.
+func (s *Shared) Snapshot() Immutable {
+	if im := s.snap.Load(); im != nil {
+		return *im
+	}
+	return Immutable{}
+}
+
+// Get returns a deep copy of the value at p, like [Lookup] does.
+func (s *Shared) Get(p Path) (Value, bool) {
+	im, ok := s.Snapshot().Lookup(p)
+	if !ok {
+		return nil, false
+	}
+	return im.Value(), true
+}
+
+// Set puts a deep copy of v at p, with the same rules as [Immutable.Set].
+func (s *Shared) Set(p Path, v Value) error {
+	return s.Update(p, func(Value, bool) (Value, error) { return v, nil })
+}
+
+// Delete removes the value at p, with the same rules as [Immutable.Delete].
+func (s *Shared) Delete(p Path) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur := s.Snapshot()
+	next := cur.Delete(p)
+	s.snap.Store(&next)
+	s.notify(cur, next)
+}
+
+// Update replaces the value at p with the result of fn, which is given a
+// deep copy of the current value at p and whether it exists. No other change
+// can happen between fn reading the value and its result being stored, so
+// fn must not change s itself. If fn returns an error, nothing is changed
+// and the error is returned.
+func (s *Shared) Update(p Path, fn func(v Value, ok bool) (Value, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur := s.Snapshot()
+	old, ok := cur.Lookup(p)
+	v, err := fn(old.Value(), ok)
+	if err != nil {
+		return err
+	}
+	next, err := cur.Set(p, v)
+	if err != nil {
+		return err
+	}
+	s.snap.Store(&next)
+	s.notify(cur, next)
+	return nil
+}
+
+// Watch calls fn every time the value at p changes, for example to reload
+// part of a dynamic configuration. This includes changes to anything below p,
+// and changes to the Structs and Arrays containing p that affect it. fn gets
+// a [Change] with a deep copy of the old and new value at p, and p as its
+// path, where a value that did not exist before is Added and one that no
+// longer exists is Removed.
+//
+// fn is called synchronously, after the change is visible to readers, and
+// before the next change is made, so it must not change s itself. Calling the
+// returned function stops the calls to fn, it may be called from fn.
+func (s *Shared) Watch(p Path, fn func(Change)) (stop func()) {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+	w := &watcher{path: p, fn: fn}
+	s.watchers = append(s.watchers, w)
+	return func() {
+		s.wmu.Lock()
+		defer s.wmu.Unlock()
+		s.watchers = slices.DeleteFunc(s.watchers, func(o *watcher) bool { return o == w })
+	}
+}
+
+// notify calls the watchers whose value differs between cur and next.
+func (s *Shared) notify(cur, next Immutable) {
+	s.wmu.Lock()
+	watchers := slices.Clone(s.watchers)
+	s.wmu.Unlock()
+	for _, w := range watchers {
+		old, hadOld := Lookup(cur.v, w.path)
+		nv, hasNew := Lookup(next.v, w.path)
+		var kind ChangeKind
+		switch {
+		case hadOld && hasNew:
+			if equal(old, nv) {
+				continue
+			}
+			kind = Modified
+		case hasNew:
+			kind = Added
+		case hadOld:
+			kind = Removed
+		default:
+			continue
+		}
+		w.fn(Change{Kind: kind, Path: w.path, Old: Clone(old), New: Clone(nv)})
+	}
+}
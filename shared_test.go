@@ -0,0 +1,90 @@
+package simple
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShared(t *testing.T) {
+	var zero Shared
+	require.NoError(t, zero.Set(MustParsePath(".a"), Int(1)))
+	require.Equal(t, `{"a":1}`, zero.Snapshot().String())
+
+	src := Struct{"config": Struct{"level": String("info")}}
+	s := NewShared(src)
+	src["config"] = nil
+	snap := s.Snapshot()
+
+	v, ok := s.Get(MustParsePath(".config"))
+	require.True(t, ok)
+	v.(Struct)["level"] = String("changed")
+	v, _ = s.Get(MustParsePath(".config.level"))
+	require.Equal(t, String("info"), v)
+	_, ok = s.Get(MustParsePath(".missing"))
+	require.False(t, ok)
+
+	counter := MustParsePath(".hits")
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, s.Update(counter, func(v Value, ok bool) (Value, error) {
+				if !ok {
+					return Int(1), nil
+				}
+				return v.(Int) + 1, nil
+			}))
+			_, _ = s.Get(counter)
+		}()
+	}
+	wg.Wait()
+	v, _ = s.Get(counter)
+	require.Equal(t, Int(50), v)
+	require.Equal(t, `{"config":{"level":"info"}}`, snap.String())
+
+	errNope := errors.New("nope")
+	require.ErrorIs(t, s.Update(counter, func(Value, bool) (Value, error) { return nil, errNope }), errNope)
+	require.EqualError(t, s.Set(MustParsePath(".hits.x"), Int(1)), "cannot set value at .hits: number is not a struct")
+	v, _ = s.Get(counter)
+	require.Equal(t, Int(50), v)
+
+	s.Delete(counter)
+	require.Equal(t, `{"config":{"level":"info"}}`, s.Snapshot().String())
+}
+
+func TestSharedWatch(t *testing.T) {
+	s := NewShared(Struct{"db": Struct{"host": String("a")}})
+	var changes []Change
+	stop := s.Watch(MustParsePath(".db.host"), func(c Change) {
+		changes = append(changes, c)
+		v, _ := s.Get(c.Path)
+		require.Equal(t, c.New, v)
+	})
+	var dbChanges []Change
+	s.Watch(MustParsePath(".db"), func(c Change) {
+		dbChanges = append(dbChanges, c)
+	})
+	var stopOnce func()
+	stopOnce = s.Watch(Path{}, func(Change) { stopOnce() })
+
+	require.NoError(t, s.Set(MustParsePath(".db.host"), String("b")))
+	require.NoError(t, s.Set(MustParsePath(".db.host"), String("b")))
+	require.NoError(t, s.Set(MustParsePath(".other"), Int(1)))
+	require.NoError(t, s.Set(MustParsePath(".db"), Struct{"port": Int(5432)}))
+	require.NoError(t, s.Set(MustParsePath(".db.host"), String("c")))
+	stop()
+	require.NoError(t, s.Set(MustParsePath(".db.host"), String("d")))
+
+	require.Equal(t, []Change{
+		{Kind: Modified, Path: MustParsePath(".db.host"), Old: String("a"), New: String("b")},
+		{Kind: Removed, Path: MustParsePath(".db.host"), Old: String("b")},
+		{Kind: Added, Path: MustParsePath(".db.host"), New: String("c")},
+	}, changes)
+	require.Len(t, dbChanges, 4)
+	require.Equal(t, Struct{"host": String("c"), "port": Int(5432)}, dbChanges[2].New)
+	require.Len(t, s.watchers, 1)
+}
@@ -0,0 +1,103 @@
+package simple
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidSignature is returned by [Verify] and [VerifyEmbedded] when the
+// signature does not match the value.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// signedMessage is what actually gets signed: the canonical JSON itself when
+// the signature scheme does its own hashing (like Ed25519), or its digest.
+func signedMessage(v Value, opts crypto.SignerOpts) ([]byte, error) {
+	h := opts.HashFunc()
+	if h == 0 {
+		return CanonicalJSON(v)
+	}
+	return Digest(v, h)
+}
+
+// Sign produces a detached signature over the [CanonicalJSON] form of v. opts
+// are passed to the signer; use crypto.Hash(0) for Ed25519 keys and something
+// like crypto.SHA256 for RSA and ECDSA keys.
+func Sign(v Value, signer crypto.Signer, opts crypto.SignerOpts) ([]byte, error) {
+	msg, err := signedMessage(v, opts)
+	if err != nil {
+		return nil, err
+	}
+	return signer.Sign(rand.Reader, msg, opts)
+}
+
+// Verify checks a signature created by [Sign]. RSA (PKCS #1 v1.5, or PSS when
+// opts is a *rsa.PSSOptions), ECDSA and Ed25519 public keys are supported.
+// [ErrInvalidSignature] is returned when the signature does not match.
+func Verify(v Value, pub crypto.PublicKey, sig []byte, opts crypto.SignerOpts) error {
+	msg, err := signedMessage(v, opts)
+	if err != nil {
+		return err
+	}
+	valid := false
+	switch key := pub.(type) {
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, msg, sig)
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(key, msg, sig)
+	case *rsa.PublicKey:
+		if pss, ok := opts.(*rsa.PSSOptions); ok {
+			valid = rsa.VerifyPSS(key, pss.Hash, msg, sig, pss) == nil
+		} else {
+			valid = rsa.VerifyPKCS1v15(key, opts.HashFunc(), msg, sig) == nil
+		}
+	default:
+		return fmt.Errorf("simple.Verify: unsupported public key type %T", pub)
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// SignEmbedded signs s without its key field, and returns a copy of s with the
+// base64url encoded signature stored at key. This is handy for payloads like
+// webhooks where the signature travels with the document.
+func SignEmbedded(s Struct, key string, signer crypto.Signer, opts crypto.SignerOpts) (Struct, error) {
+	unsigned := withoutKey(s, key)
+	sig, err := Sign(unsigned, signer, opts)
+	if err != nil {
+		return nil, err
+	}
+	unsigned[key] = String(base64.RawURLEncoding.EncodeToString(sig))
+	return unsigned, nil
+}
+
+// VerifyEmbedded checks a signature embedded by [SignEmbedded].
+func VerifyEmbedded(s Struct, key string, pub crypto.PublicKey, opts crypto.SignerOpts) error {
+	encoded, ok := s[key].(String)
+	if !ok {
+		return fmt.Errorf("simple.VerifyEmbedded: no signature found at key %q", key)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(string(encoded))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	return Verify(withoutKey(s, key), pub, sig, opts)
+}
+
+// withoutKey returns a shallow copy of s without key.
+func withoutKey(s Struct, key string) Struct {
+	out := make(Struct, len(s))
+	for k, v := range s {
+		if k != key {
+			out[k] = v
+		}
+	}
+	return out
+}
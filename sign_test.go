@@ -0,0 +1,56 @@
+package simple
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify(t *testing.T) {
+	payload := mustFromJSON(t, `{"event": "order.created", "id": 7, "items": [{"sku": "a"}]}`)
+	tampered := mustFromJSON(t, `{"event": "order.created", "id": 8, "items": [{"sku": "a"}]}`)
+	reordered := mustFromJSON(t, `{"items": [{"sku": "a"}], "id": 7, "event": "order.created"}`)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name   string
+		signer crypto.Signer
+		opts   crypto.SignerOpts
+	}{
+		{name: "ed25519", signer: edKey, opts: crypto.Hash(0)},
+		{name: "ecdsa", signer: ecKey, opts: crypto.SHA256},
+		{name: "rsa", signer: rsaKey, opts: crypto.SHA256},
+		{name: "rsa-pss", signer: rsaKey, opts: &rsa.PSSOptions{Hash: crypto.SHA256}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sig, err := Sign(payload, tc.signer, tc.opts)
+			require.NoError(t, err)
+			require.NoError(t, Verify(payload, tc.signer.Public(), sig, tc.opts))
+			require.NoError(t, Verify(reordered, tc.signer.Public(), sig, tc.opts))
+			require.ErrorIs(t, Verify(tampered, tc.signer.Public(), sig, tc.opts), ErrInvalidSignature)
+
+			signed, err := SignEmbedded(payload.(Struct), "signature", tc.signer, tc.opts)
+			require.NoError(t, err)
+			require.Contains(t, signed, "signature")
+			require.NotContains(t, payload, "signature")
+			require.NoError(t, VerifyEmbedded(signed, "signature", tc.signer.Public(), tc.opts))
+
+			signed["id"] = Number(8)
+			require.ErrorIs(t, VerifyEmbedded(signed, "signature", tc.signer.Public(), tc.opts), ErrInvalidSignature)
+		})
+	}
+
+	require.Error(t, VerifyEmbedded(Struct{}, "signature", edKey.Public(), crypto.Hash(0)))
+}
@@ -6,55 +6,101 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // Value is a way of having structured data with no specific schema. It mirrors
 // JSON's limited type set. So, Value can only be one of the following:
-// [Struct], [Array], [Number], [String], [Bool]. JSON "null" can be represented
-// by Go's nil.
+// [Struct], [Array], [Number] (or [RawNumber] and [Int]), [String], [Bool].
+// JSON "null" can be represented by Go's nil.
+//
+// String returns a Value as compact JSON, StringIndent returns it indented
+// like [json.MarshalIndent] does, for debug output and CLI tools.
 type Value interface {
 	xIsValue()
 	String() string
-}
-
-// FromJSON will instantiate a Value based on JSON. The only possible failure is
-// JSON syntax errors.
-func FromJSON(jb json.RawMessage) (Value, error) {
-	var anyv any
-	if err := json.Unmarshal(jb, &anyv); err != nil {
-		return nil, err
-	}
-	return fastFromValue(anyv), nil
+	StringIndent(prefix, indent string) string
 }
 
 // fastFromValue converts untyped data to simple values with assumptions that
-// these values came straight from a json unmarshal
-func fastFromValue(v any) Value {
+// these values came straight from a json unmarshal. A json.Number, which a
+// decoder using UseNumber produces, becomes a Number too.
+func fastFromValue(v any) (Value, error) {
 	switch rv := v.(type) {
 	case map[string]any:
 		out := make(Struct, len(rv))
 		for k, v := range rv {
-			out[k] = fastFromValue(v)
+			ev, err := fastFromValue(v)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = ev
 		}
-		return out
+		return out, nil
 	case []any:
 		out := make(Array, 0, len(rv))
 		for _, v := range rv {
-			out = append(out, fastFromValue(v))
+			ev, err := fastFromValue(v)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, ev)
 		}
-		return out
+		return out, nil
 	case float64:
-		return Number(rv)
+		return Number(rv), nil
+	case json.Number:
+		f, err := rv.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return Number(f), nil
 	case bool:
-		return Bool(rv)
+		return Bool(rv), nil
 	case string:
-		return String(rv)
+		return String(rv), nil
+	case nil:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unexpected type %T: %w", v, ErrUnsupportedKind)
+}
+
+// ToAny renders v as plain Go data: map[string]any, []any, float64, string,
+// bool and nil. A [RawNumber] becomes a json.Number, which keeps its text, and
+// an [Int] becomes an int64. It is the inverse of what [FromJSON] accepts,
+// and is useful for handing values to libraries that only understand untyped
+// JSON-ish data.
+func ToAny(v Value) any {
+	switch tv := v.(type) {
+	case Struct:
+		out := make(map[string]any, len(tv))
+		for k, v := range tv {
+			out[k] = ToAny(v)
+		}
+		return out
+	case Array:
+		out := make([]any, len(tv))
+		for i, v := range tv {
+			out[i] = ToAny(v)
+		}
+		return out
+	case Number:
+		return float64(tv)
+	case RawNumber:
+		return json.Number(tv)
+	case Int:
+		return int64(tv)
+	case String:
+		return string(tv)
+	case Bool:
+		return bool(tv)
 	case nil:
 		return nil
 	}
-	panic(fmt.Sprintf("fastFromValue: unexpected type %T", v))
+	panic(fmt.Sprintf("ToAny: unexpected type %T", v))
 }
 
 // FromValue allows any scalar or composite value to be simplified to a [Value].
@@ -65,8 +111,118 @@ func fastFromValue(v any) Value {
 // Any value that implements `SimpleValue() (Value, error)` or
 // `SimpleValue() Value` can override some logic and handle value simplification
 // on their own.
-func FromValue(v any) (Value, error) {
-	return fromReflectValue(reflect.ValueOf(v), []string{})
+//
+// Struct fields tagged with `simple:"redact"` are replaced with [Redacted]
+// instead of being converted.
+func FromValue(v any, opts ...FromValueOption) (Value, error) {
+	c := converter{maxDepth: DefaultMaxConvertDepth}
+	for _, o := range opts {
+		o(&c)
+	}
+	out, err := c.fromReflectValue(reflect.ValueOf(v), []string{})
+	if err != nil && c.collect(err) {
+		return nil, nil
+	}
+	return out, err
+}
+
+// FromValueOption changes the behavior of [FromValue].
+type FromValueOption func(*converter)
+
+// converter holds the settings of a single [FromValue] call.
+type converter struct {
+	utf8      UTF8Policy
+	nonFinite NonFinitePolicy
+	errs      *[]error
+	stringer  bool
+	diagnose  bool
+	types     map[string]string
+	maxDepth  int
+
+	// depth is the number of composites being converted
+	depth int
+}
+
+// DefaultMaxConvertDepth is how deeply structs, maps, slices and arrays may be
+// nested in a value given to [FromValue], unless [ConvertMaxDepth] says
+// otherwise. It mostly protects against cyclic pointers.
+const DefaultMaxConvertDepth = 1000
+
+// ConvertMaxDepth limits how deeply structs, maps, slices and arrays may be
+// nested in a value given to [FromValue]. The top-level value is at depth 1,
+// and pointers do not count. A value that is nested deeper fails with
+// [ErrTooDeep].
+func ConvertMaxDepth(n int) FromValueOption {
+	return func(c *converter) { c.maxDepth = n }
+}
+
+// CollectErrors makes [FromValue] return as much of the tree as it can. A
+// value that cannot be converted is replaced with null and its error, which
+// names its path, is appended to dst instead of being returned. Map entries
+// whose key is rejected by [UTF8Reject] are left out.
+func CollectErrors(dst *[]error) FromValueOption {
+	return func(c *converter) { c.errs = dst }
+}
+
+// StringerFallback makes [FromValue] convert values it would otherwise reject,
+// like channels and funcs, to a [String] instead of failing. The same goes
+// for structs that have fields but none of them exported, which would
+// otherwise become an empty [Struct]. The String is the result of the
+// String method of the value if it has one, and its type, like "func()",
+// if not. This is meant for capturing values for debugging, where something
+// is better than nothing.
+func StringerFallback() FromValueOption {
+	return func(c *converter) { c.stringer = true }
+}
+
+// fallback converts rv as described by [StringerFallback].
+func (c *converter) fallback(rv reflect.Value, path []string) (Value, error) {
+	var s string
+	if sv, ok := rv.Interface().(fmt.Stringer); ok {
+		// fmt recovers from String methods that panic, like on nil pointers
+		s = fmt.Sprint(sv)
+	} else {
+		s = fmt.Sprintf("%T", rv.Interface())
+	}
+	s, err := c.string(s, path)
+	if err != nil {
+		return nil, err
+	}
+	return String(s), nil
+}
+
+// isOpaque reports whether rt is a struct type with fields that are all
+// unexported.
+func isOpaque(rt reflect.Type) bool {
+	for i := 0; i < rt.NumField(); i++ {
+		if rt.Field(i).IsExported() {
+			return false
+		}
+	}
+	return rt.NumField() > 0
+}
+
+// collect appends err to the collected errors and reports whether the
+// conversion should carry on.
+func (c *converter) collect(err error) bool {
+	if c.errs == nil {
+		return false
+	}
+	*c.errs = append(*c.errs, err)
+	return true
+}
+
+// string checks s against the UTF-8 policy.
+func (c *converter) string(s string, path []string) (string, error) {
+	switch c.utf8 {
+	case UTF8Replace:
+		return toValidUTF8(s), nil
+	case UTF8Reject:
+		if !utf8.ValidString(s) {
+			return "", fromValueWrappedError{error: ErrInvalidUTF8, path: slices.Clone(path)}
+		}
+	}
+	return s, nil
 }
 
 var builtinString = reflect.TypeFor[string]()
@@ -112,24 +268,48 @@ func stringify(rt reflect.Type) func(reflect.Value) string {
 
 type fromValueError struct {
 	path    []string
+	typ     string
 	problem string
+	err     error
 }
 
+func (f fromValueError) Unwrap() error { return f.err }
 func (f fromValueError) Error() string {
-	return fmt.Sprintf("cannot convert value at %s: %s", strings.Join(f.path, ""), f.problem)
+	return fmt.Sprintf("cannot convert value at %s: %s", describeAt(f.path, f.typ), f.problem)
 }
 
+func (f fromValueError) Pointer() string { return fragmentsPath(f.path).Pointer() }
+
 type fromValueWrappedError struct {
 	error
 	path []string
+	typ  string
 }
 
 func (f fromValueWrappedError) Unwrap() error { return f.error }
 func (f fromValueWrappedError) Error() string {
-	return fmt.Sprintf("cannot convert value at %s: %s", strings.Join(f.path, ""), f.error.Error())
+	return fmt.Sprintf("cannot convert value at %s: %s", describeAt(f.path, f.typ), f.error.Error())
 }
 
-func fromReflectValue(rv reflect.Value, path []string) (Value, error) {
+func (f fromValueWrappedError) Pointer() string { return fragmentsPath(f.path).Pointer() }
+
+// fragmentsPath turns the path fragments of [FromValue], like ".", "name" and
+// "[0]", back into a [Path].
+func fragmentsPath(frags []string) Path {
+	out := Path{}
+	for i := 0; i < len(frags); i++ {
+		if frags[i] == "." && i+1 < len(frags) {
+			out = append(out, frags[i+1])
+			i++
+			continue
+		}
+		n, _ := strconv.Atoi(strings.Trim(frags[i], "[]"))
+		out = append(out, n)
+	}
+	return out
+}
+
+func (c *converter) convert(rv reflect.Value, path []string) (Value, error) {
 	if !rv.IsValid() {
 		return nil, nil
 	}
@@ -142,7 +322,7 @@ func fromReflectValue(rv reflect.Value, path []string) (Value, error) {
 			if err != nil {
 				return nil, fromValueWrappedError{
 					error: err,
-					path:  path,
+					path:  slices.Clone(path),
 				}
 			}
 			return v, nil
@@ -151,8 +331,13 @@ func fromReflectValue(rv reflect.Value, path []string) (Value, error) {
 		rv = reflect.ValueOf(rv.Interface())
 	}
 
-	if len(path) >= 1000 {
-		panic(fmt.Sprintf("fromReflectValue: value too deep, path: %v", path))
+	switch rv.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Array, reflect.Slice:
+		if c.depth >= c.maxDepth {
+			return nil, fromValueError{path: slices.Clone(path), problem: fmt.Sprintf("value is nested deeper than %d levels", c.maxDepth), err: ErrTooDeep}
+		}
+		c.depth++
+		defer func() { c.depth-- }()
 	}
 
 	rt := rv.Type()
@@ -163,16 +348,23 @@ func fromReflectValue(rv reflect.Value, path []string) (Value, error) {
 		if rv.IsNil() {
 			return nil, nil
 		}
-		return fromReflectValue(rv.Elem(), path)
+		return c.fromReflectValue(rv.Elem(), path)
 	case reflect.Struct:
+		if c.stringer && rv.CanInterface() && isOpaque(rt) {
+			return c.fallback(rv, path)
+		}
 		outstruct := make(Struct, rt.NumField())
 		for i := 0; i < rv.NumField(); i++ {
 			if !rt.Field(i).IsExported() {
 				continue
 			}
 			key := rt.Field(i).Name
-			value, err := fromReflectValue(rv.Field(i), append(path, ".", key))
-			if err != nil {
+			if hasTagOption(rt.Field(i).Tag.Get("simple"), "redact") {
+				outstruct[key] = Redacted
+				continue
+			}
+			value, err := c.fromReflectValue(rv.Field(i), append(path, ".", key))
+			if err != nil && !c.collect(err) {
 				return nil, err
 			}
 			outstruct[key] = value
@@ -181,16 +373,22 @@ func fromReflectValue(rv reflect.Value, path []string) (Value, error) {
 	case reflect.Map:
 		keytostr := stringify(rt.Key())
 		if keytostr == nil {
-			return nil, fromValueError{path: path, problem: fmt.Sprintf("map key with %s type %q cannot be stringified", rt.Key().Kind(), rt.Key().String())}
+			return nil, fromValueError{path: slices.Clone(path), problem: fmt.Sprintf("map key with %s type %q cannot be stringified", rt.Key().Kind(), rt.Key().String()), err: ErrUnstringifiableMapKey}
 		}
 		outstruct := make(Struct, rv.Len())
 		mapiter := rv.MapRange()
 		for mapiter.Next() {
 			key := mapiter.Key()
-			keystr := keytostr(key)
-			value := mapiter.Value()
-			goodValue, err := fromReflectValue(value, append(path, ".", keystr))
+			keystr, err := c.string(keytostr(key), path)
 			if err != nil {
+				if c.collect(err) {
+					continue
+				}
+				return nil, err
+			}
+			value := mapiter.Value()
+			goodValue, err := c.fromReflectValue(value, append(path, ".", keystr))
+			if err != nil && !c.collect(err) {
 				return nil, err
 			}
 			outstruct[keystr] = goodValue
@@ -199,8 +397,8 @@ func fromReflectValue(rv reflect.Value, path []string) (Value, error) {
 	case reflect.Array, reflect.Slice:
 		outarray := make(Array, 0, rv.Len())
 		for i := 0; i < rv.Len(); i++ {
-			v, err := fromReflectValue(rv.Index(i), append(path, fmt.Sprintf("[%d]", i)))
-			if err != nil {
+			v, err := c.fromReflectValue(rv.Index(i), append(path, fmt.Sprintf("[%d]", i)))
+			if err != nil && !c.collect(err) {
 				return nil, err
 			}
 			outarray = append(outarray, v)
@@ -213,7 +411,11 @@ func fromReflectValue(rv reflect.Value, path []string) (Value, error) {
 		if rt != builtinString {
 			fv = fv.Convert(builtinString)
 		}
-		return String(fv.Interface().(string)), nil
+		str, err := c.string(fv.Interface().(string), path)
+		if err != nil {
+			return nil, err
+		}
+		return String(str), nil
 
 		// numbers
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
@@ -223,7 +425,7 @@ func fromReflectValue(rv reflect.Value, path []string) (Value, error) {
 		if rt != builtinFloat64 {
 			fv = fv.Convert(builtinFloat64)
 		}
-		return Number(fv.Interface().(float64)), nil
+		return c.number(fv.Interface().(float64), path)
 
 	case reflect.Bool:
 		if rt != builtinBool {
@@ -232,19 +434,41 @@ func fromReflectValue(rv reflect.Value, path []string) (Value, error) {
 		return Bool(rv.Interface().(bool)), nil
 
 	default:
+		if c.stringer && rv.CanInterface() {
+			return c.fallback(rv, path)
+		}
 		return nil, fromValueError{
-			path:    path,
+			path:    slices.Clone(path),
 			problem: fmt.Sprintf("cannot convert value of kind %s to simple value", rv.Kind()),
+			err:     ErrUnsupportedKind,
 		}
 	}
 }
 
-func mustJSONEncodeValue(v Value) string {
-	jb, err := json.Marshal(v)
-	if err != nil {
-		panic(fmt.Sprintf("mustJSONEncodeValue: json encode failed: %s", err.Error()))
+// hasTagOption reports whether the comma separated struct tag value contains
+// opt.
+func hasTagOption(tag, opt string) bool {
+	for tag != "" {
+		var o string
+		o, tag, _ = strings.Cut(tag, ",")
+		if o == opt {
+			return true
+		}
 	}
-	return string(jb)
+	return false
+}
+
+// tagOptionValue returns the value of the name=value option in the comma
+// separated struct tag value.
+func tagOptionValue(tag, name string) (string, bool) {
+	for tag != "" {
+		var o string
+		o, tag, _ = strings.Cut(tag, ",")
+		if v, ok := strings.CutPrefix(o, name+"="); ok {
+			return v, true
+		}
+	}
+	return "", false
 }
 
 // Struct is a key value structure where keys are strings the are mapped to a
@@ -253,6 +477,12 @@ type Struct map[string]Value
 
 func (Struct) xIsValue() {}
 
+// NewStruct returns an empty, non-nil Struct. Unlike a nil Struct, it is
+// written as {} by encoding/json.
+func NewStruct() Struct {
+	return Struct{}
+}
+
 func (s *Struct) UnmarshalJSON(data []byte) error {
 	var intermediate map[string]json.RawMessage
 	err := json.Unmarshal(data, &intermediate)
@@ -270,9 +500,11 @@ func (s *Struct) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// String implements [Value]
+// String implements [Value]. The keys are always written in sorted order, so
+// the output is deterministic and may be relied on by golden tests and caches.
+// Use an [Encoder] to choose a different order.
 func (s Struct) String() string {
-	return mustJSONEncodeValue(s)
+	return stringJSON(s)
 }
 
 // Array is an ordered set of [Value] values
@@ -280,6 +512,15 @@ type Array []Value
 
 func (Array) xIsValue() {}
 
+// NewArray returns an Array holding values. The result is never nil, even
+// without any values, so it is written as [] by encoding/json.
+func NewArray(values ...Value) Array {
+	if values == nil {
+		return Array{}
+	}
+	return Array(values)
+}
+
 func (a *Array) UnmarshalJSON(data []byte) error {
 	var intermediate []json.RawMessage
 	err := json.Unmarshal(data, &intermediate)
@@ -299,17 +540,20 @@ func (a *Array) UnmarshalJSON(data []byte) error {
 
 // String implements [Value]
 func (a Array) String() string {
-	return mustJSONEncodeValue(a)
+	return stringJSON(a)
 }
 
 // Number is some numeric value. IEEE754 floating point number.
+//
+// JSON has no way of writing NaN or infinite numbers. String renders them as
+// null, see [NonFinitePolicy] for other options.
 type Number float64
 
 func (Number) xIsValue() {}
 
 // String implements [Value]
 func (n Number) String() string {
-	return mustJSONEncodeValue(n)
+	return stringJSON(n)
 }
 
 // Bool is true of false
@@ -319,7 +563,7 @@ func (Bool) xIsValue() {}
 
 // String implements [Value]
 func (b Bool) String() string {
-	return mustJSONEncodeValue(b)
+	return stringJSON(b)
 }
 
 // String is an ordered set of UTF-8 characters.
@@ -329,5 +573,5 @@ func (String) xIsValue() {}
 
 // String implements [Value]
 func (s String) String() string {
-	return mustJSONEncodeValue(s)
+	return stringJSON(s)
 }
@@ -223,3 +223,130 @@ func TestFromValue(t *testing.T) {
 		})
 	}
 }
+
+func TestToAny(t *testing.T) {
+	input := `{"alpha":["beta",1,true,null],"charlie":{"delta":{}},"echo":[]}`
+	v, err := FromJSON(json.RawMessage(input))
+	require.NoError(t, err)
+
+	got := ToAny(v)
+	require.Equal(t, map[string]any{
+		"alpha":   []any{"beta", float64(1), true, nil},
+		"charlie": map[string]any{"delta": map[string]any{}},
+		"echo":    []any{},
+	}, got)
+
+	jb, err := json.Marshal(got)
+	require.NoError(t, err)
+	require.JSONEq(t, input, string(jb))
+}
+
+func TestFastFromValue(t *testing.T) {
+	v, err := fastFromValue(map[string]any{"n": json.Number("1.5"), "l": []any{json.Number("2")}})
+	require.NoError(t, err)
+	require.Equal(t, Struct{"n": Number(1.5), "l": Array{Number(2)}}, v)
+
+	_, err = fastFromValue([]any{json.Number("1e999")})
+	require.Error(t, err)
+	_, err = fastFromValue(map[string]any{"x": int64(1)})
+	require.EqualError(t, err, "unexpected type int64: unsupported kind")
+	require.ErrorIs(t, err, ErrUnsupportedKind)
+}
+
+func TestFromValueCollectErrors(t *testing.T) {
+	type inner struct {
+		Fn   func()
+		Name string
+	}
+	var errs []error
+	v, err := FromValue(map[string]any{
+		"ok":    1,
+		"ch":    make(chan int),
+		"list":  []any{"a", math.NaN(), inner{Name: "b"}},
+		"\xff":  true,
+		"keyed": map[[2]int]int{{1, 2}: 3},
+	}, CollectErrors(&errs), ConvertInvalidUTF8(UTF8Reject))
+	require.NoError(t, err)
+	require.Equal(t, Struct{
+		"ok":    Number(1),
+		"ch":    nil,
+		"list":  Array{String("a"), nil, Struct{"Fn": nil, "Name": String("b")}},
+		"keyed": nil,
+	}, v)
+
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	require.ElementsMatch(t, []string{
+		"cannot convert value at .ch: cannot convert value of kind chan to simple value",
+		"cannot convert value at .list[1]: NaN is not a valid number",
+		"cannot convert value at .list[2].Fn: cannot convert value of kind func to simple value",
+		"cannot convert value at : invalid UTF-8",
+		`cannot convert value at .keyed: map key with array type "[2]int" cannot be stringified`,
+	}, msgs)
+
+	errs = nil
+	v, err = FromValue(make(chan int), CollectErrors(&errs))
+	require.NoError(t, err)
+	require.Nil(t, v)
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], ErrUnsupportedKind)
+}
+
+type opaqueStringer struct{ id int }
+
+func (o opaqueStringer) String() string { return "opaque-" + strconv.Itoa(o.id) }
+
+type stringerFunc func()
+
+func (stringerFunc) String() string { return "a func" }
+
+type nilStringer chan int
+
+func (n nilStringer) String() string { return strconv.Itoa(cap(n) / len(n)) }
+
+func TestFromValueStringerFallback(t *testing.T) {
+	input := map[string]any{
+		"ch":     make(chan int),
+		"fn":     stringerFunc(func() {}),
+		"opaque": opaqueStringer{id: 7},
+		"plain":  struct{ n int }{1},
+		"empty":  struct{}{},
+		"panics": nilStringer(nil),
+		"n":      complex(1, 2),
+	}
+	_, err := FromValue(input)
+	require.ErrorIs(t, err, ErrUnsupportedKind)
+
+	v, err := FromValue(input, StringerFallback())
+	require.NoError(t, err)
+	require.Equal(t, Struct{
+		"ch":     String("chan int"),
+		"fn":     String("a func"),
+		"opaque": String("opaque-7"),
+		"plain":  String("struct { n int }"),
+		"empty":  Struct{},
+		"panics": String("%!v(PANIC=String method: runtime error: integer divide by zero)"),
+		"n":      String("complex128"),
+	}, v)
+}
+
+func TestFromValueMaxDepth(t *testing.T) {
+	type node struct {
+		Next *node
+	}
+	cyclic := &node{}
+	cyclic.Next = cyclic
+	_, err := FromValue(cyclic)
+	require.ErrorIs(t, err, ErrTooDeep)
+
+	nested := map[string]any{"a": []any{map[string]int{"b": 1}}}
+	v, err := FromValue(nested, ConvertMaxDepth(3))
+	require.NoError(t, err)
+	require.Equal(t, Struct{"a": Array{Struct{"b": Number(1)}}}, v)
+
+	_, err = FromValue(nested, ConvertMaxDepth(2))
+	require.EqualError(t, err, "cannot convert value at .a[0]: value is nested deeper than 2 levels")
+	require.ErrorIs(t, err, ErrTooDeep)
+}
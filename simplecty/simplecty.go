@@ -0,0 +1,213 @@
+// Package simplecty converts between [simple.Value] and the cty.Value of
+// github.com/zclconf/go-cty, and decodes HCL files into Values, so that
+// Terraform-adjacent tooling can use simple as its dynamic data layer.
+package simplecty // import "code.nkcmr.net/simple/simplecty"
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"code.nkcmr.net/simple"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type conversionError struct {
+	path    simple.Path
+	problem string
+	err     error
+}
+
+func (c conversionError) Unwrap() error { return c.err }
+
+func (c conversionError) Error() string {
+	return fmt.Sprintf("cannot convert value at %s: %s", c.path, c.problem)
+}
+
+func (c conversionError) Pointer() string { return c.path.Pointer() }
+
+// ToCty converts v to a cty.Value. Structs become objects and Arrays become
+// tuples, since their elements do not need to have the same type. A null is
+// a null of the dynamic pseudo-type. Numbers that are not finite cannot be
+// represented.
+func ToCty(v simple.Value) (cty.Value, error) {
+	return toCty(simple.Path{}, v)
+}
+
+func toCty(path simple.Path, v simple.Value) (cty.Value, error) {
+	switch tv := v.(type) {
+	case nil:
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	case simple.Struct:
+		if len(tv) == 0 {
+			return cty.EmptyObjectVal, nil
+		}
+		attrs := make(map[string]cty.Value, len(tv))
+		for k, ev := range tv {
+			cv, err := toCty(path.Key(k), ev)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			attrs[k] = cv
+		}
+		return cty.ObjectVal(attrs), nil
+	case simple.Array:
+		if len(tv) == 0 {
+			return cty.EmptyTupleVal, nil
+		}
+		elems := make([]cty.Value, len(tv))
+		for i, ev := range tv {
+			cv, err := toCty(path.Index(i), ev)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			elems[i] = cv
+		}
+		return cty.TupleVal(elems), nil
+	case simple.Number:
+		f := float64(tv)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return cty.NilVal, conversionError{path: path, problem: fmt.Sprintf("%v is not a valid cty number", f)}
+		}
+		return cty.NumberFloatVal(f), nil
+	case simple.Int:
+		return cty.NumberIntVal(int64(tv)), nil
+	case simple.RawNumber:
+		cv, err := cty.ParseNumberVal(string(tv))
+		if err != nil {
+			return cty.NilVal, conversionError{path: path, problem: err.Error()}
+		}
+		return cv, nil
+	case simple.String:
+		return cty.StringVal(string(tv)), nil
+	case simple.Bool:
+		return cty.BoolVal(bool(tv)), nil
+	}
+	return cty.NilVal, conversionError{path: path, problem: fmt.Sprintf("unsupported value type %T", v), err: simple.ErrUnsupportedKind}
+}
+
+// FromCty converts a cty.Value to a [simple.Value]. Objects and maps become
+// Structs, and lists, sets and tuples become Arrays. Whole numbers that fit
+// in an int64 become a [simple.Int], other numbers a [simple.Number]. Unknown
+// values, marked values and capsules cannot be converted.
+func FromCty(v cty.Value) (simple.Value, error) {
+	return fromCty(simple.Path{}, v)
+}
+
+func fromCty(path simple.Path, v cty.Value) (simple.Value, error) {
+	if v.IsMarked() {
+		return nil, conversionError{path: path, problem: "value is marked"}
+	}
+	if !v.IsKnown() {
+		return nil, conversionError{path: path, problem: "value is unknown"}
+	}
+	if v.IsNull() {
+		return nil, nil
+	}
+	t := v.Type()
+	switch {
+	case t.IsObjectType() || t.IsMapType():
+		out := simple.Struct{}
+		for it := v.ElementIterator(); it.Next(); {
+			k, ev := it.Element()
+			sv, err := fromCty(path.Key(k.AsString()), ev)
+			if err != nil {
+				return nil, err
+			}
+			out[k.AsString()] = sv
+		}
+		return out, nil
+	case t.IsListType() || t.IsSetType() || t.IsTupleType():
+		out := simple.Array{}
+		for it := v.ElementIterator(); it.Next(); {
+			_, ev := it.Element()
+			sv, err := fromCty(path.Index(len(out)), ev)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, sv)
+		}
+		return out, nil
+	case t == cty.Number:
+		bf := v.AsBigFloat()
+		if bf.IsInt() {
+			if i, acc := bf.Int64(); acc == big.Exact {
+				return simple.Int(i), nil
+			}
+		}
+		f, _ := bf.Float64()
+		return simple.Number(f), nil
+	case t == cty.String:
+		return simple.String(v.AsString()), nil
+	case t == cty.Bool:
+		return simple.Bool(v.True()), nil
+	}
+	return nil, conversionError{path: path, problem: fmt.Sprintf("cannot convert cty type %s", t.FriendlyName())}
+}
+
+// DecodeHCL parses src as a file in the native HCL syntax and decodes its
+// body into a [simple.Struct], without needing a schema. Attributes are
+// evaluated with ctx, which may be nil. Blocks are keyed by their type and
+// then by each of their labels, and the bodies of the blocks that end up
+// under the same key are collected in an Array, so
+//
+//	resource "aws_instance" "web" {
+//	  ami = "ami-123"
+//	}
+//
+// becomes {"resource":{"aws_instance":{"web":[{"ami":"ami-123"}]}}}.
+func DecodeHCL(src []byte, filename string, ctx *hcl.EvalContext) (simple.Struct, error) {
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return decodeBody(simple.Path{}, file.Body.(*hclsyntax.Body), ctx)
+}
+
+func decodeBody(path simple.Path, body *hclsyntax.Body, ctx *hcl.EvalContext) (simple.Struct, error) {
+	out := simple.Struct{}
+	for name, attr := range body.Attributes {
+		cv, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		v, err := fromCty(path.Key(name), cv)
+		if err != nil {
+			return nil, err
+		}
+		out[name] = v
+	}
+	for _, block := range body.Blocks {
+		keys := append([]string{block.Type}, block.Labels...)
+		parent := out
+		blockPath := path
+		for _, k := range keys[:len(keys)-1] {
+			blockPath = blockPath.Key(k)
+			child, ok := parent[k].(simple.Struct)
+			if !ok {
+				if _, exists := parent[k]; exists {
+					return nil, conversionError{path: blockPath, problem: "block conflicts with an attribute"}
+				}
+				child = simple.Struct{}
+				parent[k] = child
+			}
+			parent = child
+		}
+		last := keys[len(keys)-1]
+		blockPath = blockPath.Key(last)
+		bodies, ok := parent[last].(simple.Array)
+		if !ok {
+			if _, exists := parent[last]; exists {
+				return nil, conversionError{path: blockPath, problem: "block conflicts with an attribute"}
+			}
+		}
+		v, err := decodeBody(blockPath.Index(len(bodies)), block.Body, ctx)
+		if err != nil {
+			return nil, err
+		}
+		parent[last] = append(bodies, v)
+	}
+	return out, nil
+}
@@ -0,0 +1,109 @@
+package simplecty
+
+import (
+	"math"
+	"testing"
+
+	"code.nkcmr.net/simple"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestRoundTrip(t *testing.T) {
+	v := simple.Struct{
+		"name":  simple.String("web"),
+		"count": simple.Int(3),
+		"ratio": simple.Number(0.5),
+		"on":    simple.Bool(true),
+		"none":  nil,
+		"tags":  simple.Array{simple.String("a"), simple.Int(1)},
+		"empty": simple.Struct{},
+	}
+	cv, err := ToCty(v)
+	require.NoError(t, err)
+	require.True(t, cv.Type().IsObjectType())
+	require.True(t, cv.GetAttr("tags").Type().IsTupleType())
+	back, err := FromCty(cv)
+	require.NoError(t, err)
+	require.Equal(t, v, back)
+
+	big, err := ToCty(simple.RawNumber("123456789012345678901234567890"))
+	require.NoError(t, err)
+	require.Equal(t, "123456789012345678901234567890", big.AsBigFloat().Text('f', -1))
+
+	_, err = ToCty(simple.Struct{"a": simple.Array{simple.Number(math.NaN())}})
+	require.EqualError(t, err, "cannot convert value at .a[0]: NaN is not a valid cty number")
+	pointer, ok := simple.ErrorPointer(err)
+	require.True(t, ok)
+	require.Equal(t, "/a/0", pointer)
+}
+
+func TestFromCty(t *testing.T) {
+	v, err := FromCty(cty.ObjectVal(map[string]cty.Value{
+		"list": cty.ListVal([]cty.Value{cty.NumberFloatVal(1.5)}),
+		"set":  cty.SetVal([]cty.Value{cty.StringVal("x")}),
+		"map":  cty.MapVal(map[string]cty.Value{"k": cty.True}),
+		"null": cty.NullVal(cty.String),
+	}))
+	require.NoError(t, err)
+	require.Equal(t, simple.Struct{
+		"list": simple.Array{simple.Number(1.5)},
+		"set":  simple.Array{simple.String("x")},
+		"map":  simple.Struct{"k": simple.Bool(true)},
+		"null": nil,
+	}, v)
+
+	_, err = FromCty(cty.ObjectVal(map[string]cty.Value{"a": cty.UnknownVal(cty.String)}))
+	require.EqualError(t, err, "cannot convert value at .a: value is unknown")
+}
+
+func TestDecodeHCL(t *testing.T) {
+	src := []byte(`
+region = "us-east-1"
+zones  = [for z in ["a", "b"] : "${var.prefix}${z}"]
+
+resource "aws_instance" "web" {
+  ami   = "ami-123"
+  count = 2
+
+  tag {
+    key = "env"
+  }
+  tag {
+    key = "team"
+  }
+}
+
+resource "aws_instance" "db" {
+  ami = "ami-456"
+}
+`)
+	ctx := &hcl.EvalContext{Variables: map[string]cty.Value{
+		"var": cty.ObjectVal(map[string]cty.Value{"prefix": cty.StringVal("us-east-1")}),
+	}}
+	s, err := DecodeHCL(src, "main.tf", ctx)
+	require.NoError(t, err)
+	require.Equal(t, simple.Struct{
+		"region": simple.String("us-east-1"),
+		"zones":  simple.Array{simple.String("us-east-1a"), simple.String("us-east-1b")},
+		"resource": simple.Struct{
+			"aws_instance": simple.Struct{
+				"web": simple.Array{simple.Struct{
+					"ami":   simple.String("ami-123"),
+					"count": simple.Int(2),
+					"tag": simple.Array{
+						simple.Struct{"key": simple.String("env")},
+						simple.Struct{"key": simple.String("team")},
+					},
+				}},
+				"db": simple.Array{simple.Struct{"ami": simple.String("ami-456")}},
+			},
+		},
+	}, s)
+
+	_, err = DecodeHCL([]byte(`a = var.missing`), "main.tf", nil)
+	require.ErrorContains(t, err, "Variables not allowed")
+	_, err = DecodeHCL([]byte(`a = `), "main.tf", nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,16 @@
+module code.nkcmr.net/simple/simplejwt
+
+go 1.23
+
+require (
+	code.nkcmr.net/simple v0.1.0
+	github.com/golang-jwt/jwt/v5 v5.3.1
+	github.com/stretchr/testify v1.10.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
@@ -0,0 +1,237 @@
+// Package simplejwt converts between [simple.Value] and JWT claim sets of
+// github.com/golang-jwt/jwt, so that token payloads can be inspected and built
+// with the path and query APIs of simple.
+package simplejwt // import "code.nkcmr.net/simple/simplejwt"
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"code.nkcmr.net/simple"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims is a claim set backed by a [simple.Struct]. It implements
+// [jwt.Claims], so it can be used to both sign and parse tokens:
+//
+//	token := jwt.NewWithClaims(jwt.SigningMethodHS256, simplejwt.Claims{
+//		"sub": simple.String("1234"),
+//		"exp": simplejwt.NumericDate(time.Now().Add(time.Hour)),
+//	})
+//
+//	var claims simplejwt.Claims
+//	_, err := jwt.ParseWithClaims(s, &claims, keyFunc)
+//
+// Numbers in parsed claims are [simple.Int] when they are integers.
+type Claims simple.Struct
+
+var _ jwt.Claims = Claims{}
+
+// Struct returns c as a [simple.Struct].
+func (c Claims) Struct() simple.Struct {
+	return simple.Struct(c)
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (c Claims) MarshalJSON() ([]byte, error) {
+	return json.Marshal(simple.Struct(c))
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (c *Claims) UnmarshalJSON(data []byte) error {
+	v, err := simple.FromJSON(data, simple.UseInt())
+	if err != nil {
+		return err
+	}
+	s, ok := v.(simple.Struct)
+	if !ok {
+		return fmt.Errorf("%w: claims must be a JSON object", jwt.ErrInvalidType)
+	}
+	*c = Claims(s)
+	return nil
+}
+
+// GetExpirationTime implements [jwt.Claims].
+func (c Claims) GetExpirationTime() (*jwt.NumericDate, error) {
+	return c.date("exp")
+}
+
+// GetIssuedAt implements [jwt.Claims].
+func (c Claims) GetIssuedAt() (*jwt.NumericDate, error) {
+	return c.date("iat")
+}
+
+// GetNotBefore implements [jwt.Claims].
+func (c Claims) GetNotBefore() (*jwt.NumericDate, error) {
+	return c.date("nbf")
+}
+
+// GetIssuer implements [jwt.Claims].
+func (c Claims) GetIssuer() (string, error) {
+	return c.string("iss")
+}
+
+// GetSubject implements [jwt.Claims].
+func (c Claims) GetSubject() (string, error) {
+	return c.string("sub")
+}
+
+// GetAudience implements [jwt.Claims]. The audience may be a single string or
+// an array of strings.
+func (c Claims) GetAudience() (jwt.ClaimStrings, error) {
+	switch tv := c["aud"].(type) {
+	case nil:
+		return nil, nil
+	case simple.String:
+		return jwt.ClaimStrings{string(tv)}, nil
+	case simple.Array:
+		out := make(jwt.ClaimStrings, 0, len(tv))
+		for _, e := range tv {
+			s, ok := e.(simple.String)
+			if !ok {
+				return nil, fmt.Errorf("%w: aud must hold strings", jwt.ErrInvalidType)
+			}
+			out = append(out, string(s))
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("%w: aud must be a string or an array of strings", jwt.ErrInvalidType)
+}
+
+func (c Claims) date(name string) (*jwt.NumericDate, error) {
+	v, ok := c[name]
+	if !ok || v == nil {
+		return nil, nil
+	}
+	t, err := Time(v)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", jwt.ErrInvalidType, name, err)
+	}
+	return jwt.NewNumericDate(t), nil
+}
+
+func (c Claims) string(name string) (string, error) {
+	switch tv := c[name].(type) {
+	case nil:
+		return "", nil
+	case simple.String:
+		return string(tv), nil
+	}
+	return "", fmt.Errorf("%w: %s must be a string", jwt.ErrInvalidType, name)
+}
+
+// NumericDate returns t as a JWT NumericDate, the number of seconds since the
+// Unix epoch. It is a [simple.Int] unless [jwt.TimePrecision] asks for
+// fractional seconds.
+func NumericDate(t time.Time) simple.Value {
+	t = t.Truncate(jwt.TimePrecision)
+	if jwt.TimePrecision >= time.Second {
+		return simple.Int(t.Unix())
+	}
+	return simple.Number(float64(t.UnixNano()) / float64(time.Second))
+}
+
+// Time converts a JWT NumericDate to a time.Time. Fractional seconds are
+// kept.
+func Time(v simple.Value) (time.Time, error) {
+	switch tv := v.(type) {
+	case simple.Int:
+		return time.Unix(int64(tv), 0), nil
+	case simple.Number, simple.RawNumber:
+		f, err := simple.AsFloat64(tv)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			break
+		}
+		sec, frac := math.Modf(f)
+		return time.Unix(int64(sec), int64(math.Round(frac*1e9))), nil
+	}
+	return time.Time{}, fmt.Errorf("%s is not a numeric date", v)
+}
+
+// FromRegisteredClaims converts rc to a [simple.Struct], leaving out the
+// claims that are not set. Dates become numeric dates, see [NumericDate].
+func FromRegisteredClaims(rc jwt.RegisteredClaims) simple.Struct {
+	out := simple.Struct{}
+	setString := func(name, s string) {
+		if s != "" {
+			out[name] = simple.String(s)
+		}
+	}
+	setDate := func(name string, d *jwt.NumericDate) {
+		if d != nil {
+			out[name] = NumericDate(d.Time)
+		}
+	}
+	setString("iss", rc.Issuer)
+	setString("sub", rc.Subject)
+	setString("jti", rc.ID)
+	switch len(rc.Audience) {
+	case 0:
+	case 1:
+		out["aud"] = simple.String(rc.Audience[0])
+	default:
+		aud := make(simple.Array, len(rc.Audience))
+		for i, a := range rc.Audience {
+			aud[i] = simple.String(a)
+		}
+		out["aud"] = aud
+	}
+	setDate("exp", rc.ExpiresAt)
+	setDate("nbf", rc.NotBefore)
+	setDate("iat", rc.IssuedAt)
+	return out
+}
+
+// RegisteredClaims extracts the registered claims (iss, sub, aud, exp, nbf,
+// iat and jti) out of s. Other claims are ignored. An error is returned if a
+// registered claim has the wrong type.
+func RegisteredClaims(s simple.Struct) (jwt.RegisteredClaims, error) {
+	c := Claims(s)
+	var rc jwt.RegisteredClaims
+	var err error
+	if rc.Issuer, err = c.GetIssuer(); err != nil {
+		return rc, err
+	}
+	if rc.Subject, err = c.GetSubject(); err != nil {
+		return rc, err
+	}
+	if rc.ID, err = c.string("jti"); err != nil {
+		return rc, err
+	}
+	if rc.Audience, err = c.GetAudience(); err != nil {
+		return rc, err
+	}
+	if rc.ExpiresAt, err = c.GetExpirationTime(); err != nil {
+		return rc, err
+	}
+	if rc.NotBefore, err = c.GetNotBefore(); err != nil {
+		return rc, err
+	}
+	if rc.IssuedAt, err = c.GetIssuedAt(); err != nil {
+		return rc, err
+	}
+	return rc, nil
+}
+
+// FromClaims converts any claim set, like a struct that embeds
+// [jwt.RegisteredClaims], to a [simple.Struct] by way of its JSON encoding.
+func FromClaims(c jwt.Claims) (simple.Struct, error) {
+	if sc, ok := c.(Claims); ok {
+		return simple.Struct(sc), nil
+	}
+	jb, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	v, err := simple.FromJSON(jb, simple.UseInt())
+	if err != nil {
+		return nil, err
+	}
+	s, ok := v.(simple.Struct)
+	if !ok {
+		return nil, fmt.Errorf("%w: claims must encode to a JSON object", jwt.ErrInvalidType)
+	}
+	return s, nil
+}
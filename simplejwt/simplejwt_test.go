@@ -0,0 +1,77 @@
+package simplejwt
+
+import (
+	"testing"
+	"time"
+
+	"code.nkcmr.net/simple"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimsRoundTrip(t *testing.T) {
+	key := []byte("secret")
+	exp := time.Unix(2000000000, 0)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		"sub":   simple.String("1234"),
+		"aud":   simple.Array{simple.String("a"), simple.String("b")},
+		"exp":   NumericDate(exp),
+		"roles": simple.Array{simple.String("admin")},
+	}).SignedString(key)
+	require.NoError(t, err)
+
+	var claims Claims
+	_, err = jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) { return key, nil }, jwt.WithAudience("b"))
+	require.NoError(t, err)
+	require.Equal(t, simple.Int(2000000000), claims["exp"])
+	roles, ok := simple.Lookup(claims.Struct(), simple.MustParsePath("roles[0]"))
+	require.True(t, ok)
+	require.Equal(t, simple.String("admin"), roles)
+
+	rc, err := RegisteredClaims(claims.Struct())
+	require.NoError(t, err)
+	require.Equal(t, "1234", rc.Subject)
+	require.Equal(t, jwt.ClaimStrings{"a", "b"}, rc.Audience)
+	require.True(t, rc.ExpiresAt.Equal(exp))
+	require.Nil(t, rc.IssuedAt)
+
+	// expired tokens are rejected based on the Struct
+	_, err = jwt.ParseWithClaims(token, &Claims{}, func(*jwt.Token) (any, error) { return key, nil },
+		jwt.WithTimeFunc(func() time.Time { return exp.Add(time.Minute) }))
+	require.ErrorIs(t, err, jwt.ErrTokenExpired)
+}
+
+func TestRegisteredClaims(t *testing.T) {
+	rc := jwt.RegisteredClaims{
+		Issuer:   "me",
+		Audience: jwt.ClaimStrings{"you"},
+		IssuedAt: jwt.NewNumericDate(time.Unix(1700000000, 0)),
+	}
+	s := FromRegisteredClaims(rc)
+	require.Equal(t, simple.Struct{
+		"iss": simple.String("me"),
+		"aud": simple.String("you"),
+		"iat": simple.Int(1700000000),
+	}, s)
+
+	back, err := RegisteredClaims(s)
+	require.NoError(t, err)
+	require.Equal(t, rc, back)
+
+	fromStruct, err := FromClaims(rc)
+	require.NoError(t, err)
+	require.Equal(t, simple.Struct{
+		"iss": simple.String("me"),
+		"aud": simple.Array{simple.String("you")},
+		"iat": simple.Int(1700000000),
+	}, fromStruct)
+
+	_, err = RegisteredClaims(simple.Struct{"exp": simple.String("tomorrow")})
+	require.ErrorIs(t, err, jwt.ErrInvalidType)
+	_, err = RegisteredClaims(simple.Struct{"aud": simple.Array{simple.Int(1)}})
+	require.ErrorIs(t, err, jwt.ErrInvalidType)
+
+	ts, err := Time(simple.Number(1.5))
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(1, 5e8), ts)
+}
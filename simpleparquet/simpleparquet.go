@@ -0,0 +1,252 @@
+// Package simpleparquet writes Arrays of [simple.Struct] as Parquet files with
+// github.com/parquet-go/parquet-go, so that captured event Values can be
+// dumped directly into analytics storage.
+package simpleparquet // import "code.nkcmr.net/simple/simpleparquet"
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"code.nkcmr.net/simple"
+	"github.com/parquet-go/parquet-go"
+)
+
+type columnKind int
+
+const (
+	kindNull columnKind = iota
+	kindBool
+	kindInt
+	kindDouble
+	kindString
+	kindJSON
+)
+
+var kindNames = [...]string{"null", "boolean", "integer", "double", "string", "JSON"}
+
+type column struct {
+	kind     columnKind
+	optional bool
+}
+
+// InferSchema infers a flat Parquet schema from rows, which must all be
+// Structs. Every key that appears in any row becomes a column:
+//   - [simple.Bool] values become BOOLEAN columns.
+//   - Numbers without a fractional part that fit in an int64 become INT64
+//     columns, other numbers make the column a DOUBLE.
+//   - [simple.String] values become STRING columns.
+//   - Nested Structs and Arrays are stored as JSON.
+//
+// A column is optional if it is null or missing in any row. Columns that are
+// null in every row are optional STRING columns. A column that mixes any
+// other kinds of values is an error.
+func InferSchema(name string, rows simple.Array) (*parquet.Schema, error) {
+	columns := map[string]*column{}
+	for i, row := range rows {
+		s, ok := row.(simple.Struct)
+		if !ok {
+			return nil, fmt.Errorf("row %d is not a struct", i)
+		}
+		for k, v := range s {
+			c, ok := columns[k]
+			if !ok {
+				// absent from all previous rows
+				c = &column{optional: i > 0}
+				columns[k] = c
+			}
+			kind := kindOf(v)
+			switch {
+			case kind == kindNull:
+				c.optional = true
+			case c.kind == kindNull || c.kind == kind:
+				c.kind = kind
+			case (c.kind == kindInt && kind == kindDouble) || (c.kind == kindDouble && kind == kindInt):
+				c.kind = kindDouble
+			default:
+				return nil, fmt.Errorf("column %q has both %s and %s values", k, kindNames[c.kind], kindNames[kind])
+			}
+		}
+		for k, c := range columns {
+			if _, ok := s[k]; !ok {
+				c.optional = true
+			}
+		}
+	}
+	group := parquet.Group{}
+	for k, c := range columns {
+		var node parquet.Node
+		switch c.kind {
+		case kindBool:
+			node = parquet.Leaf(parquet.BooleanType)
+		case kindInt:
+			node = parquet.Int(64)
+		case kindDouble:
+			node = parquet.Leaf(parquet.DoubleType)
+		case kindJSON:
+			node = parquet.JSON()
+		default:
+			node = parquet.String()
+		}
+		if c.optional || c.kind == kindNull {
+			node = parquet.Optional(node)
+		}
+		group[k] = node
+	}
+	return parquet.NewSchema(name, group), nil
+}
+
+func kindOf(v simple.Value) columnKind {
+	switch tv := v.(type) {
+	case simple.Bool:
+		return kindBool
+	case simple.Int:
+		return kindInt
+	case simple.Number, simple.RawNumber:
+		f, err := simple.AsFloat64(tv)
+		if err == nil && f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			return kindInt
+		}
+		return kindDouble
+	case simple.String:
+		return kindString
+	case simple.Struct, simple.Array:
+		return kindJSON
+	}
+	return kindNull
+}
+
+// Writer streams Structs to a Parquet file with a flat schema, like the ones
+// returned by [InferSchema].
+type Writer struct {
+	pw     *parquet.Writer
+	fields []parquet.Field
+	row    parquet.Row
+	n      int
+}
+
+// NewWriter returns a [Writer] that writes rows with the given schema to w.
+// Close must be called to write the footer of the file.
+func NewWriter(w io.Writer, schema *parquet.Schema, opts ...parquet.WriterOption) *Writer {
+	return &Writer{
+		pw:     parquet.NewWriter(w, append([]parquet.WriterOption{schema}, opts...)...),
+		fields: schema.Fields(),
+	}
+}
+
+// Write writes a row. Keys of the row that are not columns of the schema are
+// an error, as are values that do not match the type of their column.
+func (w *Writer) Write(row simple.Struct) error {
+	defer func() { w.n++ }()
+	w.row = w.row[:0]
+	for i, f := range w.fields {
+		v := row[f.Name()]
+		pv, err := leafValue(f, v)
+		if err != nil {
+			return fmt.Errorf("cannot write row %d: column %q: %w", w.n, f.Name(), err)
+		}
+		definition := 0
+		if f.Optional() && v != nil {
+			definition = 1
+		}
+		w.row = append(w.row, pv.Level(0, definition, i))
+	}
+	if len(row) > len(w.fields) {
+		for k := range row {
+			if !w.hasColumn(k) {
+				return fmt.Errorf("cannot write row %d: %q is not a column of the schema", w.n, k)
+			}
+		}
+	}
+	_, err := w.pw.WriteRows([]parquet.Row{w.row})
+	return err
+}
+
+func (w *Writer) hasColumn(name string) bool {
+	for _, f := range w.fields {
+		if f.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func leafValue(f parquet.Field, v simple.Value) (parquet.Value, error) {
+	if !f.Leaf() {
+		return parquet.Value{}, fmt.Errorf("nested columns are not supported")
+	}
+	if v == nil {
+		if !f.Optional() {
+			return parquet.Value{}, fmt.Errorf("null value in a required column")
+		}
+		return parquet.Value{}, nil
+	}
+	typ := f.Type()
+	switch typ.Kind() {
+	case parquet.Boolean:
+		b, ok := v.(simple.Bool)
+		if !ok {
+			return parquet.Value{}, fmt.Errorf("expected a bool, got %s", v)
+		}
+		return parquet.BooleanValue(bool(b)), nil
+	case parquet.Int32, parquet.Int64:
+		if kindOf(v) != kindInt {
+			return parquet.Value{}, fmt.Errorf("expected an integer, got %s", v)
+		}
+		i, err := simple.AsInt64(v)
+		if err != nil {
+			return parquet.Value{}, err
+		}
+		if typ.Kind() == parquet.Int32 {
+			if i < math.MinInt32 || i > math.MaxInt32 {
+				return parquet.Value{}, fmt.Errorf("%d does not fit in an INT32", i)
+			}
+			return parquet.Int32Value(int32(i)), nil
+		}
+		return parquet.Int64Value(i), nil
+	case parquet.Float, parquet.Double:
+		if k := kindOf(v); k != kindInt && k != kindDouble {
+			return parquet.Value{}, fmt.Errorf("expected a number, got %s", v)
+		}
+		d, err := simple.AsFloat64(v)
+		if err != nil {
+			return parquet.Value{}, err
+		}
+		if typ.Kind() == parquet.Float {
+			return parquet.FloatValue(float32(d)), nil
+		}
+		return parquet.DoubleValue(d), nil
+	case parquet.ByteArray:
+		if lt := typ.LogicalType(); lt != nil && lt.Json != nil {
+			return parquet.ByteArrayValue([]byte(v.String())), nil
+		}
+		s, ok := v.(simple.String)
+		if !ok {
+			return parquet.Value{}, fmt.Errorf("expected a string, got %s", v)
+		}
+		return parquet.ByteArrayValue([]byte(s)), nil
+	}
+	return parquet.Value{}, fmt.Errorf("unsupported column type %s", typ)
+}
+
+// Close flushes buffered rows and writes the footer of the file. It does not
+// close the underlying io.Writer.
+func (w *Writer) Close() error {
+	return w.pw.Close()
+}
+
+// Write infers a schema from rows with [InferSchema] and writes them all to
+// w as a complete Parquet file.
+func Write(w io.Writer, rows simple.Array, opts ...parquet.WriterOption) error {
+	schema, err := InferSchema("simple", rows)
+	if err != nil {
+		return err
+	}
+	pw := NewWriter(w, schema, opts...)
+	for _, row := range rows {
+		if err := pw.Write(row.(simple.Struct)); err != nil {
+			return err
+		}
+	}
+	return pw.Close()
+}
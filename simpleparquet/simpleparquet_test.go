@@ -0,0 +1,74 @@
+package simpleparquet
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"code.nkcmr.net/simple"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInferSchema(t *testing.T) {
+	schema, err := InferSchema("event", simple.Array{
+		simple.Struct{"id": simple.Number(1), "ok": simple.Bool(true), "score": simple.Number(1), "meta": simple.Struct{}, "note": nil},
+		simple.Struct{"id": simple.Int(2), "ok": simple.Bool(false), "score": simple.Number(1.5), "name": simple.String("b"), "note": nil},
+	})
+	require.NoError(t, err)
+	require.Equal(t, `message event {
+	required int64 id (INT(64,true));
+	optional binary meta (JSON);
+	optional binary name (STRING);
+	optional binary note (STRING);
+	required boolean ok;
+	required double score;
+}`, schema.String())
+
+	_, err = InferSchema("event", simple.Array{
+		simple.Struct{"id": simple.Number(1)},
+		simple.Struct{"id": simple.String("2")},
+	})
+	require.EqualError(t, err, `column "id" has both integer and string values`)
+	_, err = InferSchema("event", simple.Array{simple.Array{}})
+	require.EqualError(t, err, "row 0 is not a struct")
+}
+
+func TestWrite(t *testing.T) {
+	rows := simple.Array{
+		simple.Struct{"id": simple.Number(1), "name": simple.String("a"), "tags": simple.Array{simple.String("x")}},
+		simple.Struct{"id": simple.Number(2), "score": simple.Number(0.5)},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, rows))
+
+	r := parquet.NewReader(bytes.NewReader(buf.Bytes()))
+	defer r.Close()
+	read := make([]parquet.Row, 3)
+	n, err := r.ReadRows(read)
+	if err != io.EOF {
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, n)
+	require.Equal(t, [][]string{{"id"}, {"name"}, {"score"}, {"tags"}}, r.Schema().Columns())
+
+	require.Equal(t, int64(1), read[0][0].Int64())
+	require.Equal(t, "a", string(read[0][1].ByteArray()))
+	require.True(t, read[0][2].IsNull())
+	require.Equal(t, `["x"]`, string(read[0][3].ByteArray()))
+	require.Equal(t, int64(2), read[1][0].Int64())
+	require.True(t, read[1][1].IsNull())
+	require.Equal(t, 0.5, read[1][2].Double())
+	require.True(t, read[1][3].IsNull())
+}
+
+func TestWriterErrors(t *testing.T) {
+	schema, err := InferSchema("event", simple.Array{simple.Struct{"id": simple.Number(1)}})
+	require.NoError(t, err)
+	w := NewWriter(io.Discard, schema)
+	require.NoError(t, w.Write(simple.Struct{"id": simple.Int(1)}))
+	require.EqualError(t, w.Write(simple.Struct{"id": simple.Number(1.5)}), `cannot write row 1: column "id": expected an integer, got 1.5`)
+	require.EqualError(t, w.Write(simple.Struct{}), `cannot write row 2: column "id": null value in a required column`)
+	require.EqualError(t, w.Write(simple.Struct{"id": simple.Int(1), "x": nil}), `cannot write row 3: "x" is not a column of the schema`)
+	require.NoError(t, w.Close())
+}
@@ -0,0 +1,15 @@
+module code.nkcmr.net/simple/simplepb
+
+go 1.23
+
+require (
+	code.nkcmr.net/simple v0.1.0
+	github.com/stretchr/testify v1.10.0
+	google.golang.org/protobuf v1.36.10
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,57 @@
+// Package simplepb converts between [simple.Value] and protobuf messages of
+// any type, following the protojson mapping, so that gateways can treat
+// arbitrary proto payloads as Values. Messages are described at runtime by a
+// protoreflect.MessageDescriptor, so no generated code is needed.
+package simplepb // import "code.nkcmr.net/simple/simplepb"
+
+import (
+	"bytes"
+	"fmt"
+
+	"code.nkcmr.net/simple"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// FromMessage converts m to a Value the same way protojson would render it:
+// fields are keyed by their lowerCamelCase JSON names, enums are written as
+// their names, bytes are base64 encoded and 64-bit integers are strings.
+// Well-known types like google.protobuf.Timestamp get their special JSON
+// form, so the result is not always a [simple.Struct].
+func FromMessage(m proto.Message) (simple.Value, error) {
+	return FromMessageWithOptions(m, protojson.MarshalOptions{})
+}
+
+// FromMessageWithOptions is like [FromMessage], with control over the
+// protojson options, e.g. to use the original proto field names or to include
+// fields that are not set.
+func FromMessageWithOptions(m proto.Message, opts protojson.MarshalOptions) (simple.Value, error) {
+	jb, err := opts.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return simple.FromJSON(jb, simple.UseInt())
+}
+
+// ToMessage converts v to a new dynamic message of the type described by md.
+// Both the JSON names and the original names of fields are accepted, and
+// unknown fields are an error.
+func ToMessage(v simple.Value, md protoreflect.MessageDescriptor) (*dynamicpb.Message, error) {
+	return ToMessageWithOptions(v, md, protojson.UnmarshalOptions{})
+}
+
+// ToMessageWithOptions is like [ToMessage], with control over the protojson
+// options, e.g. to discard unknown fields.
+func ToMessageWithOptions(v simple.Value, md protoreflect.MessageDescriptor, opts protojson.UnmarshalOptions) (*dynamicpb.Message, error) {
+	var buf bytes.Buffer
+	if err := simple.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	m := dynamicpb.NewMessage(md)
+	if err := opts.Unmarshal(buf.Bytes(), m); err != nil {
+		return nil, fmt.Errorf("cannot convert value to %s: %w", md.FullName(), err)
+	}
+	return m, nil
+}
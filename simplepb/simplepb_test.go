@@ -0,0 +1,75 @@
+package simplepb
+
+import (
+	"testing"
+	"time"
+
+	"code.nkcmr.net/simple"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func testDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("test.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Status"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("STATUS_UNKNOWN"), Number: proto.Int32(0)},
+				{Name: proto.String("STATUS_ACTIVE"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("User"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("user_id"), JsonName: proto.String("userId"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				{Name: proto.String("name"), JsonName: proto.String("name"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				{Name: proto.String("status"), JsonName: proto.String("status"), Number: proto.Int32(3), Type: descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum(), TypeName: proto.String(".test.Status"), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				{Name: proto.String("scores"), JsonName: proto.String("scores"), Number: proto.Int32(4), Type: descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()},
+				{Name: proto.String("avatar"), JsonName: proto.String("avatar"), Number: proto.Int32(5), Type: descriptorpb.FieldDescriptorProto_TYPE_BYTES.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+			},
+		}},
+	}, nil)
+	require.NoError(t, err)
+	return fd.Messages().ByName("User")
+}
+
+func TestRoundTrip(t *testing.T) {
+	md := testDescriptor(t)
+	m, err := ToMessage(simple.Struct{
+		"user_id": simple.String("12345678901234567"),
+		"name":    simple.String("ann"),
+		"status":  simple.String("STATUS_ACTIVE"),
+		"scores":  simple.Array{simple.Number(1), simple.Int(2)},
+		"avatar":  simple.String("AQI="),
+	}, md)
+	require.NoError(t, err)
+	require.Equal(t, int64(12345678901234567), m.Get(md.Fields().ByName("user_id")).Int())
+
+	v, err := FromMessage(m)
+	require.NoError(t, err)
+	require.Equal(t, simple.Struct{
+		"userId": simple.String("12345678901234567"),
+		"name":   simple.String("ann"),
+		"status": simple.String("STATUS_ACTIVE"),
+		"scores": simple.Array{simple.Int(1), simple.Int(2)},
+		"avatar": simple.String("AQI="),
+	}, v)
+
+	_, err = ToMessage(simple.Struct{"nope": simple.Bool(true)}, md)
+	require.ErrorContains(t, err, `cannot convert value to test.User`)
+	require.ErrorContains(t, err, `unknown field "nope"`)
+}
+
+func TestWellKnownTypes(t *testing.T) {
+	v, err := FromMessage(timestamppb.New(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+	require.NoError(t, err)
+	require.Equal(t, simple.String("2024-03-01T12:00:00Z"), v)
+}
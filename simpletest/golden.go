@@ -0,0 +1,92 @@
+package simpletest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"code.nkcmr.net/simple"
+)
+
+// Update makes [RequireGolden] rewrite golden files with the actual values
+// instead of comparing against them. Golden files are also rewritten when the
+// SIMPLETEST_UPDATE environment variable is set to a true value, or when the
+// test binary defines a boolean -update flag of its own and it is set:
+//
+//	var _ = flag.Bool("update", false, "rewrite golden files")
+//
+//	go test ./... -update
+//
+// simpletest does not register the flag itself, so that it does not clash
+// with packages that already do.
+var Update bool
+
+// updating reports whether golden files should be rewritten.
+func updating() bool {
+	if Update {
+		return true
+	}
+	if b, err := strconv.ParseBool(os.Getenv("SIMPLETEST_UPDATE")); err == nil && b {
+		return true
+	}
+	if f := flag.Lookup("update"); f != nil {
+		if g, ok := f.Value.(flag.Getter); ok {
+			b, _ := g.Get().(bool)
+			return b
+		}
+	}
+	return false
+}
+
+// RequireGolden compares actual against the value stored as JSON in the golden
+// file at path (typically under testdata/), and fails the test immediately
+// with the first differing path when they do not match. Formatting of the
+// file does not matter, only the value it holds.
+//
+// When [Update] is in effect the file is (re)written instead, as indented
+// JSON with sorted keys, creating parent directories as needed.
+func RequireGolden(t testing.TB, path string, actual simple.Value, msgAndArgs ...any) {
+	t.Helper()
+	if updating() {
+		if err := writeGolden(path, actual); err != nil {
+			t.Fatalf("simpletest: cannot update golden file: %s", err.Error())
+		}
+		return
+	}
+	jb, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("simpletest: golden file %s does not exist, run the test with -update to create it", path)
+		return
+	} else if err != nil {
+		t.Fatalf("simpletest: cannot read golden file: %s", err.Error())
+		return
+	}
+	expected, err := simple.FromJSON(jb)
+	if err != nil {
+		t.Fatalf("simpletest: golden file %s is not valid JSON: %s", path, err.Error())
+		return
+	}
+	if d := firstDifference(simple.Path{}, expected, actual, false); d != nil {
+		t.Fatal(failureMessage(d, msgAndArgs) + "\n(golden file " + path + ", run the test with -update to accept the new value)")
+	}
+}
+
+func writeGolden(path string, v simple.Value) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(simple.ToAny(v)); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
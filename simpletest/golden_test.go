@@ -0,0 +1,73 @@
+package simpletest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.nkcmr.net/simple"
+	"github.com/stretchr/testify/require"
+)
+
+// packages that use golden files often define -update themselves, which must
+// not clash with simpletest
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func TestRequireGolden(t *testing.T) {
+	dir := t.TempDir()
+	golden := filepath.Join(dir, "testdata", "order.golden.json")
+	v := simple.Struct{
+		"id":    simple.Number(1),
+		"items": simple.Array{simple.Struct{"sku": simple.String("<a>")}},
+	}
+
+	r := &recorder{TB: t}
+	RequireGolden(r, golden, v)
+	require.Contains(t, r.failure, "does not exist, run the test with -update")
+
+	Update = true
+	t.Cleanup(func() { Update = false })
+	r.failure = ""
+	RequireGolden(r, golden, v)
+	require.Empty(t, r.failure)
+	jb, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	require.Equal(t, "{\n  \"id\": 1,\n  \"items\": [\n    {\n      \"sku\": \"<a>\"\n    }\n  ]\n}\n", string(jb))
+
+	Update = false
+	RequireGolden(r, golden, v)
+	require.Empty(t, r.failure)
+
+	// formatting of the file does not matter
+	require.NoError(t, os.WriteFile(golden, []byte(`{"items":[{"sku":"<a>"}],"id":1.0}`), 0o644))
+	RequireGolden(r, golden, v)
+	require.Empty(t, r.failure)
+
+	v["items"].(simple.Array)[0].(simple.Struct)["sku"] = simple.String("b")
+	RequireGolden(r, golden, v)
+	require.Contains(t, r.failure, "values differ at .items[0].sku: values are not equal")
+	require.Contains(t, r.failure, "run the test with -update to accept the new value")
+}
+
+func TestRequireGoldenEnvironment(t *testing.T) {
+	golden := filepath.Join(t.TempDir(), "value.golden.json")
+	t.Setenv("SIMPLETEST_UPDATE", "1")
+	r := &recorder{TB: t}
+	RequireGolden(r, golden, simple.Array{simple.Bool(true)})
+	require.Empty(t, r.failure)
+	jb, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	require.Equal(t, "[\n  true\n]\n", string(jb))
+}
+
+func TestRequireGoldenFlag(t *testing.T) {
+	golden := filepath.Join(t.TempDir(), "value.golden.json")
+	require.NoError(t, flag.Set("update", "true"))
+	t.Cleanup(func() { *update = false })
+	r := &recorder{TB: t}
+	RequireGolden(r, golden, simple.String("x"))
+	require.Empty(t, r.failure)
+	_, err := os.Stat(golden)
+	require.NoError(t, err)
+}
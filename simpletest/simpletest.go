@@ -0,0 +1,117 @@
+// Package simpletest provides test helpers for code that works with
+// [simple.Value]. Failures point at the first path where the values differ
+// instead of dumping two large JSON documents side by side.
+package simpletest // import "code.nkcmr.net/simple/simpletest"
+
+import (
+	"fmt"
+	"testing"
+
+	"code.nkcmr.net/simple"
+)
+
+// RequireEqual fails the test immediately if actual is not semantically equal
+// to expected. A nil Struct or Array is equal to an empty one.
+func RequireEqual(t testing.TB, expected, actual simple.Value, msgAndArgs ...any) {
+	t.Helper()
+	if d := firstDifference(simple.Path{}, expected, actual, false); d != nil {
+		t.Fatal(failureMessage(d, msgAndArgs))
+	}
+}
+
+// RequireSubset fails the test immediately unless every key of every Struct
+// in expected is also present in actual with an equal value. Keys in actual
+// that are not in expected are ignored. Arrays must have the same length, and
+// their elements are compared as subsets of each other.
+func RequireSubset(t testing.TB, expected, actual simple.Value, msgAndArgs ...any) {
+	t.Helper()
+	if d := firstDifference(simple.Path{}, expected, actual, true); d != nil {
+		t.Fatal(failureMessage(d, msgAndArgs))
+	}
+}
+
+type difference struct {
+	path     simple.Path
+	problem  string
+	expected simple.Value
+	actual   simple.Value
+}
+
+func failureMessage(d *difference, msgAndArgs []any) string {
+	path := d.path.String()
+	if path == "" {
+		path = "(root)"
+	}
+	msg := fmt.Sprintf("values differ at %s: %s\n\texpected: %s\n\tactual:   %s", path, d.problem, render(d.expected), render(d.actual))
+	if len(msgAndArgs) > 0 {
+		if format, ok := msgAndArgs[0].(string); ok {
+			msg = fmt.Sprintf(format, msgAndArgs[1:]...) + "\n" + msg
+		} else {
+			msg = fmt.Sprint(msgAndArgs...) + "\n" + msg
+		}
+	}
+	return msg
+}
+
+func render(v simple.Value) string {
+	if v == nil {
+		return "null"
+	}
+	return v.String()
+}
+
+// firstDifference finds the first place where actual does not match expected,
+// visiting struct keys in sorted order.
+func firstDifference(path simple.Path, expected, actual simple.Value, subset bool) *difference {
+	switch te := expected.(type) {
+	case simple.Struct:
+		ta, ok := actual.(simple.Struct)
+		if !ok {
+			return &difference{path: path, problem: "different kinds", expected: expected, actual: actual}
+		}
+		for _, k := range sortedKeys(te) {
+			av, present := ta[k]
+			if !present {
+				return &difference{path: path.Key(k), problem: "key is missing", expected: te[k], actual: nil}
+			}
+			if d := firstDifference(path.Key(k), te[k], av, subset); d != nil {
+				return d
+			}
+		}
+		if !subset {
+			for _, k := range sortedKeys(ta) {
+				if _, present := te[k]; !present {
+					return &difference{path: path.Key(k), problem: "unexpected key", expected: nil, actual: ta[k]}
+				}
+			}
+		}
+		return nil
+	case simple.Array:
+		ta, ok := actual.(simple.Array)
+		if !ok {
+			return &difference{path: path, problem: "different kinds", expected: expected, actual: actual}
+		}
+		if len(te) != len(ta) {
+			return &difference{path: path, problem: fmt.Sprintf("expected %d elements, got %d", len(te), len(ta)), expected: expected, actual: actual}
+		}
+		for i := range te {
+			if d := firstDifference(path.Index(i), te[i], ta[i], subset); d != nil {
+				return d
+			}
+		}
+		return nil
+	}
+	// Compare treats numbers of different representations as the same
+	if simple.Compare(expected, actual) != 0 {
+		return &difference{path: path, problem: "values are not equal", expected: expected, actual: actual}
+	}
+	return nil
+}
+
+func sortedKeys(s simple.Struct) []string {
+	var keys []string
+	for k := range s.Sorted() {
+		keys = append(keys, k)
+	}
+	return keys
+}
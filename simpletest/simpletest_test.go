@@ -0,0 +1,95 @@
+package simpletest
+
+import (
+	"fmt"
+	"testing"
+
+	"code.nkcmr.net/simple"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder captures the failure of a helper instead of failing the test.
+type recorder struct {
+	testing.TB
+	failure string
+}
+
+func (r *recorder) Helper() {}
+func (r *recorder) Fatal(args ...any) {
+	r.failure = fmt.Sprint(args...)
+}
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.failure = fmt.Sprintf(format, args...)
+}
+
+func TestRequireEqual(t *testing.T) {
+	expected := simple.Struct{
+		"id":    simple.Number(1),
+		"items": simple.Array{simple.Struct{"sku": simple.String("a")}},
+		"empty": simple.Array{},
+	}
+
+	r := &recorder{TB: t}
+	RequireEqual(r, expected, simple.Struct{
+		"id":    simple.Number(1),
+		"items": simple.Array{simple.Struct{"sku": simple.String("a")}},
+		"empty": simple.Array(nil),
+	})
+	require.Empty(t, r.failure)
+
+	RequireEqual(r, expected, simple.Struct{
+		"id":    simple.Number(1),
+		"items": simple.Array{simple.Struct{"sku": simple.String("b")}},
+		"empty": simple.Array{},
+	}, "order %d", 7)
+	require.Equal(t, "order 7\nvalues differ at .items[0].sku: values are not equal\n\texpected: \"a\"\n\tactual:   \"b\"", r.failure)
+
+	RequireEqual(r, expected, simple.Struct{
+		"id":    simple.Number(1),
+		"items": simple.Array{simple.Struct{"sku": simple.String("a")}},
+		"empty": simple.Array{},
+		"extra": simple.Bool(true),
+	})
+	require.Equal(t, "values differ at .extra: unexpected key\n\texpected: null\n\tactual:   true", r.failure)
+
+	RequireEqual(r, simple.Number(1), simple.String("1"))
+	require.Equal(t, "values differ at (root): values are not equal\n\texpected: 1\n\tactual:   \"1\"", r.failure)
+}
+
+func TestRequireEqualNumbers(t *testing.T) {
+	r := &recorder{TB: t}
+	for _, pair := range [][2]simple.Value{
+		{simple.Number(1), simple.Int(1)},
+		{simple.Int(1), simple.RawNumber("1.0")},
+		{simple.RawNumber("1e2"), simple.Number(100)},
+	} {
+		RequireEqual(r, simple.Struct{"a": pair[0]}, simple.Struct{"a": pair[1]})
+		require.Empty(t, r.failure)
+		RequireSubset(r, simple.Array{pair[1]}, simple.Array{pair[0]})
+		require.Empty(t, r.failure)
+	}
+
+	RequireEqual(r, simple.Struct{"a": simple.Int(1)}, simple.Struct{"a": simple.RawNumber("2")})
+	require.Equal(t, "values differ at .a: values are not equal\n\texpected: 1\n\tactual:   2", r.failure)
+}
+
+func TestRequireSubset(t *testing.T) {
+	actual := simple.Struct{
+		"id":      simple.Number(1),
+		"created": simple.String("2024-01-01"),
+		"tags":    simple.Array{simple.Struct{"name": simple.String("x"), "n": simple.Number(1)}},
+	}
+
+	r := &recorder{TB: t}
+	RequireSubset(r, simple.Struct{
+		"id":   simple.Number(1),
+		"tags": simple.Array{simple.Struct{"name": simple.String("x")}},
+	}, actual)
+	require.Empty(t, r.failure)
+
+	RequireSubset(r, simple.Struct{"tags": simple.Array{}}, actual)
+	require.Contains(t, r.failure, "values differ at .tags: expected 0 elements, got 1")
+
+	RequireSubset(r, simple.Struct{"missing": simple.Number(1)}, actual)
+	require.Contains(t, r.failure, "values differ at .missing: key is missing")
+}
@@ -0,0 +1,100 @@
+package simple
+
+import (
+	"math"
+	"sort"
+)
+
+// SizeOf approximates the number of bytes v occupies on the heap of a 64-bit
+// platform, for capacity planning of caches that hold Values in memory. It
+// accounts for maps, the backing arrays of slices, string data and headers,
+// and the allocations made to box values in interfaces, with every
+// allocation rounded up to the size classes of the Go allocator. Memory that
+// is shared between values, like strings that are substrings of the same
+// input, is counted once for every value that refers to it.
+func SizeOf(v Value) int {
+	switch tv := v.(type) {
+	case Struct:
+		size := mapSize(len(tv))
+		for k, ev := range tv {
+			size += allocSize(len(k)) + SizeOf(ev)
+		}
+		return size
+	case Array:
+		size := sliceSize(cap(tv))
+		for _, ev := range tv {
+			size += SizeOf(ev)
+		}
+		return size
+	}
+	return scalarSize(v)
+}
+
+// scalarSize is the size of the allocations made to store a scalar in a
+// Value. Values whose bits are below 256, like small integers and 0.0, and
+// empty strings are not allocated at all, the runtime uses static values for
+// them.
+func scalarSize(v Value) int {
+	switch tv := v.(type) {
+	case String:
+		if tv == "" {
+			return 0
+		}
+		return 16 + allocSize(len(tv))
+	case RawNumber:
+		if tv == "" {
+			return 0
+		}
+		return 16 + allocSize(len(tv))
+	case Number:
+		if math.Float64bits(float64(tv)) < 256 {
+			return 0
+		}
+		return 8
+	case Int:
+		if tv >= 0 && tv < 256 {
+			return 0
+		}
+		return 8
+	}
+	return 0
+}
+
+// mapSize estimates the size of a map with n entries of string keys and
+// Value elements. Maps store their entries in groups of 8 slots of 32 bytes
+// each, with 8 bytes of control data per group, and keep at most 7/8 of the
+// slots in use. This is the layout of Go 1.24 and later; the buckets of older
+// releases hold as many entries in a similar amount of memory.
+func mapSize(n int) int {
+	slots := 8
+	for slots*7/8 < n {
+		slots *= 2
+	}
+	return 48 + allocSize(slots/8*(8+8*32))
+}
+
+// sliceSize estimates the size of an Array with capacity n, whose slice
+// header is boxed whenever it is stored in a Value.
+func sliceSize(n int) int {
+	return 24 + allocSize(n*16)
+}
+
+// sizeClasses are the sizes that small allocations are rounded up to.
+var sizeClasses = []int{
+	0, 8, 16, 24, 32, 48, 64, 80, 96, 112, 128, 144, 160, 176, 192, 208, 224,
+	240, 256, 288, 320, 352, 384, 416, 448, 480, 512, 576, 640, 704, 768, 896,
+	1024, 1152, 1280, 1408, 1536, 1792, 2048, 2304, 2688, 3072, 3200, 3456,
+	4096, 4864, 5376, 6144, 6528, 6784, 6912, 8192, 9472, 9728, 10240, 10880,
+	12288, 13568, 14336, 16384, 18432, 19072, 20480, 21760, 24576, 27264,
+	28672, 32768,
+}
+
+// allocSize rounds n up to the size that the allocator actually reserves.
+// Allocations beyond the largest size class are rounded up to whole pages.
+func allocSize(n int) int {
+	if n > sizeClasses[len(sizeClasses)-1] {
+		const page = 8192
+		return (n + page - 1) / page * page
+	}
+	return sizeClasses[sort.SearchInts(sizeClasses, n)]
+}
@@ -0,0 +1,49 @@
+package simple
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeOf(t *testing.T) {
+	require.Equal(t, 0, SizeOf(nil))
+	require.Equal(t, 0, SizeOf(Bool(true)))
+	require.Equal(t, 0, SizeOf(Int(7)))
+	require.Equal(t, 8, SizeOf(Int(1000)))
+	require.Equal(t, 0, SizeOf(Number(0)))
+	require.Equal(t, 8, SizeOf(Number(1)))
+	require.Equal(t, 0, SizeOf(String("")))
+	require.Equal(t, 16+24, SizeOf(String("seventeen bytes!!")))
+	require.Equal(t, 24, SizeOf(Array{}))
+	require.Equal(t, 24+32+16+8, SizeOf(Array{String("a"), Bool(false)}))
+	require.Equal(t, 48+288, SizeOf(Struct{}))
+	require.Equal(t, 48+288+8, SizeOf(Struct{"a": nil}))
+	require.Less(t, mapSize(7), mapSize(8))
+	require.Equal(t, 5*8192, allocSize(32769))
+}
+
+func TestSizeOfIsCloseToActualAllocations(t *testing.T) {
+	if testing.CoverMode() != "" {
+		t.Skip("coverage instrumentation changes allocations")
+	}
+	build := func() Value {
+		a := make(Array, 0, 1000)
+		for i := 0; i < 1000; i++ {
+			a = append(a, Struct{"id": Int(int64(i) + 1000), "name": String("name " + string(rune('a'+i%26)))})
+		}
+		return a
+	}
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	v := build()
+	runtime.ReadMemStats(&after)
+	actual := int(after.TotalAlloc - before.TotalAlloc)
+	estimate := SizeOf(v)
+	// map layouts differ between toolchains, so only require the estimate to
+	// be within a factor of two
+	ratio := float64(estimate) / float64(actual)
+	require.True(t, ratio >= 0.5 && ratio <= 2, "actual %d, estimate %d", actual, estimate)
+}
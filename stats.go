@@ -0,0 +1,67 @@
+package simple
+
+// ValueStats describes the size and shape of a [Value], see [Stats].
+type ValueStats struct {
+	// The number of values of each kind, including the root.
+	Structs, Arrays, Numbers, Strings, Bools, Nulls int
+
+	// MaxDepth is the number of nested levels of composites. A scalar has a
+	// depth of 0, {} and [] have a depth of 1 and {"a":[1]} has a depth of 2,
+	// which is the same way the [MaxDepth] option of [FromJSON] counts.
+	MaxDepth int
+
+	// StringBytes is the total length of all strings and struct keys.
+	StringBytes int
+
+	// HeapBytes is an estimate of the memory the value occupies on the heap,
+	// the same as [SizeOf] returns.
+	HeapBytes int
+}
+
+// Nodes returns the total number of values.
+func (s ValueStats) Nodes() int {
+	return s.Structs + s.Arrays + s.Numbers + s.Strings + s.Bools + s.Nulls
+}
+
+// Stats measures v in a single pass, so that services can enforce payload
+// budgets and alert on anomalies.
+func Stats(v Value) ValueStats {
+	var s ValueStats
+	s.HeapBytes = s.add(v, 1)
+	return s
+}
+
+// add counts v, which is at the given depth, and returns its estimated heap
+// size.
+func (s *ValueStats) add(v Value, depth int) int {
+	switch tv := v.(type) {
+	case nil:
+		s.Nulls++
+		return 0
+	case Struct:
+		s.Structs++
+		s.MaxDepth = max(s.MaxDepth, depth)
+		size := mapSize(len(tv))
+		for k, ev := range tv {
+			s.StringBytes += len(k)
+			size += allocSize(len(k)) + s.add(ev, depth+1)
+		}
+		return size
+	case Array:
+		s.Arrays++
+		s.MaxDepth = max(s.MaxDepth, depth)
+		size := sliceSize(cap(tv))
+		for _, ev := range tv {
+			size += s.add(ev, depth+1)
+		}
+		return size
+	case String:
+		s.Strings++
+		s.StringBytes += len(tv)
+	case Number, RawNumber, Int:
+		s.Numbers++
+	case Bool:
+		s.Bools++
+	}
+	return scalarSize(v)
+}
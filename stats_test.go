@@ -0,0 +1,37 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	s := Stats(Struct{
+		"id":   Int(1),
+		"name": String("ann"),
+		"tags": Array{String("a"), Bool(true), nil},
+		"meta": Struct{"n": Number(2), "nested": Array{}},
+	})
+	require.Equal(t, 2, s.Structs)
+	require.Equal(t, 2, s.Arrays)
+	require.Equal(t, 2, s.Numbers)
+	require.Equal(t, 2, s.Strings)
+	require.Equal(t, 1, s.Bools)
+	require.Equal(t, 1, s.Nulls)
+	require.Equal(t, 10, s.Nodes())
+	require.Equal(t, 3, s.MaxDepth)
+	// keys: id name tags meta n nested, strings: ann a
+	require.Equal(t, 2+4+4+4+1+6+3+1, s.StringBytes)
+	require.Greater(t, s.HeapBytes, 2*mapSize(4))
+	require.Equal(t, SizeOf(Struct{
+		"id":   Int(1),
+		"name": String("ann"),
+		"tags": Array{String("a"), Bool(true), nil},
+		"meta": Struct{"n": Number(2), "nested": Array{}},
+	}), s.HeapBytes)
+
+	require.Equal(t, ValueStats{Strings: 1, StringBytes: 2, HeapBytes: 24}, Stats(String("hi")))
+	require.Equal(t, ValueStats{Nulls: 1}, Stats(nil))
+	require.Equal(t, 1, Stats(Array{}).MaxDepth)
+}
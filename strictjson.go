@@ -0,0 +1,59 @@
+package simple
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// StrictJSON makes [FromJSON] and [JSONDecoder] reject numbers that cannot be
+// represented: numbers beyond the range of a float64, and integers that
+// would be rounded when read as a [Number], like 9007199254740993. Numbers
+// that are kept exactly, as a [RawNumber] with [PreserveNumbers] or as an
+// [Int] with [UseInt], only have to be in range.
+//
+// A [JSONDecoder] in strict mode also reads its input as a single value, so
+// anything but whitespace after the first value is an error, as it already
+// is for [FromJSON]. [FromJSONDecoder] leaves the rest of the stream alone, and
+// only checks numbers if UseNumber was called on the json.Decoder.
+func StrictJSON() JSONOption {
+	return func(b *jsonBuilder) { b.strict = true }
+}
+
+type numberError struct {
+	path    Path
+	literal string
+	problem string
+}
+
+func (n numberError) Error() string {
+	return fmt.Sprintf("invalid number %s at %s: %s", n.literal, n.path, n.problem)
+}
+
+func (n numberError) Pointer() string { return n.path.Pointer() }
+
+// checkNumber enforces [StrictJSON] on the number literal s.
+func (b *jsonBuilder) checkNumber(path Path, s string) error {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(f, 0) {
+		return numberError{path: path, literal: s, problem: "out of range"}
+	}
+	if !b.preserveNumbers && !strings.ContainsAny(s, ".eE") && strconv.FormatFloat(f, 'f', -1, 64) != s {
+		if _, ok := intLiteral(s); !ok || !b.useInt {
+			return numberError{path: path, literal: s, problem: "integer cannot be represented exactly"}
+		}
+	}
+	return nil
+}
+
+// checkTrailing makes sure nothing but whitespace is left in dec.
+func checkTrailing(dec *json.Decoder) error {
+	offset := dec.InputOffset()
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("unexpected data after top-level value at offset %d", offset)
+	}
+	return nil
+}
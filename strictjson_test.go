@@ -0,0 +1,121 @@
+package simple
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrictJSON(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		opts  []JSONOption
+		err   string
+	}{
+		{input: `{"a": [1.5, -2e10, 9007199254740992]}`},
+		{input: `{"a": [1, 1e999]}`, err: "invalid number 1e999 at .a[1]: out of range"},
+		{input: `{"a": 9007199254740993}`, err: "invalid number 9007199254740993 at .a: integer cannot be represented exactly"},
+		{input: `9007199254740993`, opts: []JSONOption{UseInt()}},
+		{input: `99999999999999999999`, opts: []JSONOption{UseInt()}, err: "invalid number 99999999999999999999 at : integer cannot be represented exactly"},
+		{input: `99999999999999999999`, opts: []JSONOption{PreserveNumbers()}},
+		{input: `-1e400`, opts: []JSONOption{PreserveNumbers()}, err: "invalid number -1e400 at : out of range"},
+		{input: `1.00000000000000000001`},
+	} {
+		_, err := FromJSON(json.RawMessage(tc.input), append(tc.opts, StrictJSON())...)
+		if tc.err == "" {
+			require.NoError(t, err, tc.input)
+		} else {
+			require.EqualError(t, err, tc.err, tc.input)
+		}
+	}
+
+	dec := NewJSONDecoder(strings.NewReader("{\"a\": 1}\n\t "), StrictJSON())
+	v, err := dec.Decode()
+	require.NoError(t, err)
+	require.Equal(t, Struct{"a": Number(1)}, v)
+	_, err = dec.Decode()
+	require.ErrorIs(t, err, io.EOF)
+
+	dec = NewJSONDecoder(strings.NewReader(`{"a": 1} {"b": 2}`), StrictJSON())
+	_, err = dec.Decode()
+	require.EqualError(t, err, "unexpected data after top-level value at offset 8")
+}
+
+func FuzzFromJSON(f *testing.F) {
+	for _, seed := range []string{
+		`{"a": [1, 2.5, "x", true, null], "b": {}}`,
+		`[[[[]]]]`,
+		`"é😀"`,
+		`12345678901234567890`,
+		`1e999`,
+		`{"a": 1, "a": 2}`,
+		"\"\xff\"",
+		`{"a": 1} x`,
+		`[1,]`,
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v, err := FromJSON(data)
+		if err != nil {
+			return
+		}
+		if !json.Valid(data) {
+			t.Fatalf("FromJSON accepted invalid JSON %q", data)
+		}
+		again, err := FromJSON(json.RawMessage(v.String()))
+		if err != nil {
+			t.Fatalf("cannot read back %s: %v", v, err)
+		}
+		if !equal(v, again) {
+			t.Fatalf("%s was read back as %s", v, again)
+		}
+
+		withOpts, err := FromJSON(data, MaxDepth(10000))
+		if err != nil {
+			t.Fatalf("FromJSON with options rejected %q: %v", data, err)
+		}
+		if !equal(v, withOpts) {
+			t.Fatalf("FromJSON with options read %q as %s, not %s", data, withOpts, v)
+		}
+
+		for _, opts := range [][]JSONOption{
+			{StrictJSON()},
+			{StrictJSON(), UseInt()},
+			{StrictJSON(), PreserveNumbers(), RejectDuplicateKeys()},
+		} {
+			if _, err := FromJSON(data, opts...); err != nil {
+				continue
+			}
+			if _, err := FromJSON(data, opts[1:]...); err != nil && len(opts) > 1 {
+				t.Fatalf("strict mode accepted %q, which is rejected without it: %v", data, err)
+			}
+		}
+	})
+}
+
+func FuzzJSONDecoder(f *testing.F) {
+	for _, seed := range []string{
+		`{"a": 1} [true] "x"`,
+		`1 2 3`,
+		`{"a": [1, {"b": null}]}]`,
+		`{}{}`,
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		dec := NewJSONDecoder(bytes.NewReader(data), MaxDepth(32), MaxNodes(1000), StrictJSON())
+		for i := 0; dec.More(); i++ {
+			if _, err := dec.Decode(); err != nil {
+				break
+			}
+			if i > 0 {
+				t.Fatalf("strict decoder returned more than one value from %q", data)
+			}
+		}
+	})
+}
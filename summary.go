@@ -0,0 +1,112 @@
+package simple
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Summary renders v on a single line for inline logging, as compact JSON that
+// is cut off after about maxLen characters, followed by its size:
+//
+//	{"user":{"id":42,…},…} (3 keys, 14 nodes)
+//
+// Values that do not fit are replaced with "…", and the Structs and Arrays
+// around them are still closed. A maxLen of 0 or less does not cut anything.
+// Scalars are summarized without a size.
+func Summary(v Value, maxLen int) string {
+	s := summarizer{max: maxLen}
+	s.write(v)
+	var size string
+	switch tv := v.(type) {
+	case Struct:
+		size = fmt.Sprintf("%d %s", len(tv), plural(len(tv), "key", "keys"))
+	case Array:
+		size = fmt.Sprintf("%d %s", len(tv), plural(len(tv), "element", "elements"))
+	default:
+		return s.sb.String()
+	}
+	nodes := Stats(v).Nodes()
+	fmt.Fprintf(&s.sb, " (%s, %d %s)", size, nodes, plural(nodes, "node", "nodes"))
+	return s.sb.String()
+}
+
+type summarizer struct {
+	sb   strings.Builder
+	n    int
+	max  int
+	full bool
+}
+
+// token writes t if it still fits.
+func (s *summarizer) token(t string) bool {
+	if s.full {
+		return false
+	}
+	n := utf8.RuneCountInString(t)
+	if s.max > 0 && s.n+n > s.max {
+		s.full = true
+		return false
+	}
+	s.sb.WriteString(t)
+	s.n += n
+	return true
+}
+
+func (s *summarizer) write(v Value) {
+	switch tv := v.(type) {
+	case Struct:
+		if !s.token("{") {
+			s.sb.WriteString("…")
+			return
+		}
+		keys := sortedKeys(tv)
+		for i, k := range keys {
+			if !s.element(i > 0, scalarText(String(k))+":", tv[k], i < len(keys)-1) {
+				break
+			}
+		}
+		s.sb.WriteString("}")
+	case Array:
+		if !s.token("[") {
+			s.sb.WriteString("…")
+			return
+		}
+		for i, ev := range tv {
+			if !s.element(i > 0, "", ev, i < len(tv)-1) {
+				break
+			}
+		}
+		s.sb.WriteString("]")
+	default:
+		if !s.token(scalarText(v)) {
+			s.sb.WriteString("…")
+		}
+	}
+}
+
+// element writes an element of a Struct or Array, with its label if it has
+// one. It reports whether there is room for more elements.
+func (s *summarizer) element(comma bool, label string, v Value, more bool) bool {
+	sep := ""
+	if comma {
+		sep = ","
+	}
+	switch v.(type) {
+	case Struct, Array:
+		if !s.token(sep + label) {
+			s.sb.WriteString(sep + "…")
+			return false
+		}
+		s.write(v)
+		if s.full && more {
+			s.sb.WriteString(",…")
+		}
+		return !s.full
+	}
+	if !s.token(sep + label + scalarText(v)) {
+		s.sb.WriteString(sep + "…")
+		return false
+	}
+	return true
+}
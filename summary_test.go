@@ -0,0 +1,22 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummary(t *testing.T) {
+	v := Struct{
+		"user":  Struct{"id": Int(42), "name": String("ann"), "roles": Array{String("admin")}},
+		"items": Array{Int(1), Int(2), Int(3)},
+		"ok":    Bool(true),
+	}
+	require.Equal(t, `{"items":[1,2,3],"ok":true,"user":{"id":42,"name":"ann","roles":["admin"]}} (3 keys, 11 nodes)`, Summary(v, 0))
+	require.Equal(t, `{"items":[1,2,3],"ok":true,"user":{"id":42,…}} (3 keys, 11 nodes)`, Summary(v, 45))
+	require.Equal(t, `{"items":[1,…],…} (3 keys, 11 nodes)`, Summary(v, 12))
+	require.Equal(t, `{…} (3 keys, 11 nodes)`, Summary(v, 3))
+	require.Equal(t, `[] (0 elements, 1 node)`, Summary(Array{}, 10))
+	require.Equal(t, `"héllo"`, Summary(String("héllo"), 7))
+	require.Equal(t, `…`, Summary(String("héllo"), 6))
+}
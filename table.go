@@ -0,0 +1,167 @@
+package simple
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// TableOption changes the behavior of [WriteTable].
+type TableOption func(*tableOptions)
+
+type tableOptions struct {
+	columns  []string
+	maxWidth int
+	markdown bool
+}
+
+// TableColumns sets the columns of the table, and their order. By default
+// every key that appears in any row is a column, in sorted order.
+func TableColumns(columns ...string) TableOption {
+	return func(to *tableOptions) { to.columns = columns }
+}
+
+// MaxColumnWidth limits how many characters wide a column can be. Longer
+// cells are cut off and end with "…".
+func MaxColumnWidth(n int) TableOption {
+	return func(to *tableOptions) { to.maxWidth = n }
+}
+
+// Markdown makes [WriteTable] write a GitHub flavored Markdown table.
+func Markdown() TableOption {
+	return func(to *tableOptions) { to.markdown = true }
+}
+
+// WriteTable writes a, which must hold nothing but Structs, as a table with
+// aligned columns, for command line tools that print query results:
+//
+//	name  age  tags
+//	ann    31  ["x"]
+//	bob     7
+//
+// Strings are written without quotes, nulls and missing keys are empty
+// cells, and composites are written as JSON. Columns that only hold numbers
+// are aligned to the right.
+func WriteTable(w io.Writer, a Array, opts ...TableOption) error {
+	var to tableOptions
+	for _, o := range opts {
+		o(&to)
+	}
+	rows, err := structRows(a, "table")
+	if err != nil {
+		return err
+	}
+	columns := to.columns
+	if columns == nil {
+		columns = allKeys(rows)
+	}
+
+	cells := make([][]string, len(rows)+1)
+	cells[0] = make([]string, len(columns))
+	widths := make([]int, len(columns))
+	numeric := make([]bool, len(columns))
+	mixed := make([]bool, len(columns))
+	for j, col := range columns {
+		cells[0][j] = to.cell(col)
+	}
+	for i, row := range rows {
+		cells[i+1] = make([]string, len(columns))
+		for j, col := range columns {
+			v := row[col]
+			switch v.(type) {
+			case nil:
+			case Number, RawNumber, Int:
+				numeric[j] = true
+			default:
+				mixed[j] = true
+			}
+			cells[i+1][j] = to.cell(tableText(v))
+		}
+	}
+	for j := range columns {
+		numeric[j] = numeric[j] && !mixed[j]
+		if to.markdown {
+			// markdown needs at least three dashes
+			widths[j] = 3
+		}
+	}
+	for _, record := range cells {
+		for j, cell := range record {
+			widths[j] = max(widths[j], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var sb strings.Builder
+	writeRow := func(record []string) {
+		var line strings.Builder
+		if to.markdown {
+			line.WriteString("|")
+		}
+		for j, cell := range record {
+			pad := strings.Repeat(" ", widths[j]-utf8.RuneCountInString(cell))
+			switch {
+			case to.markdown:
+				line.WriteString(" ")
+			case j > 0:
+				line.WriteString("  ")
+			}
+			if numeric[j] {
+				line.WriteString(pad + cell)
+			} else {
+				line.WriteString(cell + pad)
+			}
+			if to.markdown {
+				line.WriteString(" |")
+			}
+		}
+		sb.WriteString(strings.TrimRight(line.String(), " "))
+		sb.WriteString("\n")
+	}
+	writeRow(cells[0])
+	if to.markdown {
+		sb.WriteString("|")
+		for j, width := range widths {
+			dashes := strings.Repeat("-", width)
+			if numeric[j] {
+				dashes = dashes[1:] + ":"
+			}
+			sb.WriteString(" " + dashes + " |")
+		}
+		sb.WriteString("\n")
+	}
+	for _, record := range cells[1:] {
+		writeRow(record)
+	}
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
+
+// tableText renders v for a table cell.
+func tableText(v Value) string {
+	switch tv := v.(type) {
+	case nil:
+		return ""
+	case String:
+		return string(tv)
+	case Number:
+		if f := float64(tv); isFinite(f) {
+			return formatNumber(f)
+		}
+		return fmt.Sprint(float64(tv))
+	}
+	return v.String()
+}
+
+// cell cleans up text so that it fits in a single table cell.
+func (to *tableOptions) cell(s string) string {
+	s = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ").Replace(s)
+	if to.maxWidth > 0 && utf8.RuneCountInString(s) > to.maxWidth {
+		runes := []rune(s)
+		s = string(runes[:max(to.maxWidth-1, 0)]) + "…"
+	}
+	if to.markdown {
+		s = strings.ReplaceAll(s, "|", `\|`)
+	}
+	return s
+}
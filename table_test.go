@@ -0,0 +1,49 @@
+package simple
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTable(t *testing.T) {
+	a := Array{
+		Struct{"name": String("ann"), "age": Number(31), "tags": Array{String("x")}},
+		Struct{"name": String("bob | robert"), "age": Int(7), "note": String("line one\nline two")},
+		Struct{"name": String("zoë"), "age": nil},
+	}
+
+	var sb strings.Builder
+	require.NoError(t, WriteTable(&sb, a))
+	require.Equal(t, strings.Join([]string{
+		"age  name          note               tags",
+		" 31  ann                              [\"x\"]",
+		"  7  bob | robert  line one line two",
+		"     zoë",
+		"",
+	}, "\n"), sb.String())
+
+	sb.Reset()
+	require.NoError(t, WriteTable(&sb, a, TableColumns("name", "note"), MaxColumnWidth(8)))
+	require.Equal(t, strings.Join([]string{
+		"name      note",
+		"ann",
+		"bob | r…  line on…",
+		"zoë",
+		"",
+	}, "\n"), sb.String())
+
+	sb.Reset()
+	require.NoError(t, WriteTable(&sb, a, TableColumns("name", "age"), Markdown()))
+	require.Equal(t, strings.Join([]string{
+		"| name          | age |",
+		"| ------------- | --: |",
+		"| ann           |  31 |",
+		`| bob \| robert |   7 |`,
+		"| zoë           |     |",
+		"",
+	}, "\n"), sb.String())
+
+	require.EqualError(t, WriteTable(&sb, Array{Number(1)}), "cannot encode value at [0]: number cannot be written as a table row")
+}
@@ -0,0 +1,107 @@
+package simple
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RenderTree prints v to w as an ASCII tree, like the tree command does for
+// directories, which is easier to scan than JSON for deeply nested documents.
+// Every line shows a key or an [index], and then either the value of a scalar
+// or the number of children of a Struct or Array, annotated with its type:
+//
+//	. (struct, 2 keys)
+//	├── name: "ann" (string)
+//	└── tags (array, 2 elements)
+//	    ├── [0]: true (bool)
+//	    └── [1]: null
+//
+// Keys are printed in sorted order, and quoted when they would be ambiguous.
+func RenderTree(w io.Writer, v Value) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(".")
+	writeTreeNode(bw, "", v)
+	return bw.Flush()
+}
+
+func writeTreeNode(bw *bufio.Writer, prefix string, v Value) {
+	var labels []string
+	var children []Value
+	switch tv := v.(type) {
+	case Struct:
+		if len(tv) > 0 {
+			fmt.Fprintf(bw, " (struct, %d %s)\n", len(tv), plural(len(tv), "key", "keys"))
+			for _, k := range sortedKeys(tv) {
+				labels = append(labels, treeKey(k))
+				children = append(children, tv[k])
+			}
+		}
+	case Array:
+		if len(tv) > 0 {
+			fmt.Fprintf(bw, " (array, %d %s)\n", len(tv), plural(len(tv), "element", "elements"))
+			for i, ev := range tv {
+				labels = append(labels, "["+strconv.Itoa(i)+"]")
+				children = append(children, ev)
+			}
+		}
+	}
+	if children == nil {
+		bw.WriteString(": " + scalarText(v))
+		if v != nil {
+			bw.WriteString(" (" + kindName(v) + ")")
+		}
+		bw.WriteByte('\n')
+		return
+	}
+	for i, label := range labels {
+		branch, indent := "├── ", "│   "
+		if i == len(labels)-1 {
+			branch, indent = "└── ", "    "
+		}
+		bw.WriteString(prefix + branch + label)
+		writeTreeNode(bw, prefix+indent, children[i])
+	}
+}
+
+// scalarText renders a scalar for humans. Composites are only rendered when
+// they are empty.
+func scalarText(v Value) string {
+	switch tv := v.(type) {
+	case nil:
+		return "null"
+	case Number:
+		if f := float64(tv); !isFinite(f) {
+			return nonFiniteName(f)
+		}
+		return formatNumber(float64(tv))
+	case String:
+		var buf bytes.Buffer
+		writeJSONString(&buf, string(tv), false, false)
+		return buf.String()
+	case Struct:
+		return "{}"
+	case Array:
+		return "[]"
+	}
+	return v.String()
+}
+
+func treeKey(k string) string {
+	if k == "" || k != strings.TrimSpace(k) || strings.ContainsAny(k, ":[]\"") ||
+		strings.ContainsFunc(k, func(r rune) bool { return !unicode.IsPrint(r) }) {
+		return strconv.Quote(k)
+	}
+	return k
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
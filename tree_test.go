@@ -0,0 +1,51 @@
+package simple
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTree(t *testing.T) {
+	v := Struct{
+		"name":  String("ann"),
+		"tags":  Array{Bool(true), nil, Number(math.NaN())},
+		"meta":  Struct{"n": Int(1), "deep": Array{Struct{"x": RawNumber("1.0")}}},
+		"none":  Struct{},
+		"a: b":  Array{},
+		"count": Number(1.5),
+	}
+	var buf bytes.Buffer
+	require.NoError(t, RenderTree(&buf, v))
+	require.Equal(t, `. (struct, 6 keys)
+├── "a: b": [] (array)
+├── count: 1.5 (number)
+├── meta (struct, 2 keys)
+│   ├── deep (array, 1 element)
+│   │   └── [0] (struct, 1 key)
+│   │       └── x: 1.0 (number)
+│   └── n: 1 (number)
+├── name: "ann" (string)
+├── none: {} (struct)
+└── tags (array, 3 elements)
+    ├── [0]: true (bool)
+    ├── [1]: null
+    └── [2]: NaN (number)
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, RenderTree(&buf, String("x")))
+	require.Equal(t, ".: \"x\" (string)\n", buf.String())
+}
+
+func TestRenderTreeQuotesKeys(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RenderTree(&buf, Struct{"esc\x1bkey": Int(1), "tab\tkey": Int(2), "plain key": Int(3)}))
+	require.Equal(t, `. (struct, 3 keys)
+├── "esc\x1bkey": 1 (number)
+├── plain key: 3 (number)
+└── "tab\tkey": 2 (number)
+`, buf.String())
+}
@@ -0,0 +1,114 @@
+package simple
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// TypeScriptTypes generates TypeScript definitions for values described by
+// schema, which is usually inferred from sample payloads with [InferSchema].
+// Objects become exported interfaces, named after the path that leads to them
+// like [GoTypes] does. Properties that are not required are optional, and
+// values with more than one type become unions.
+func TypeScriptTypes(name string, schema Struct) []byte {
+	ts := tsTypes{names: map[string]bool{}}
+	root := goIdentifier(name)
+	rootType := ts.tsType(root, schema)
+	if rootType != root {
+		ts.defs = slices.Insert(ts.defs, 0, fmt.Sprintf("export type %s = %s;\n", root, rootType))
+	}
+	return []byte(strings.Join(ts.defs, "\n"))
+}
+
+type tsTypes struct {
+	defs  []string
+	names map[string]bool
+}
+
+func (ts *tsTypes) uniqueName(name string) string {
+	out := name
+	for i := 2; ts.names[out]; i++ {
+		out = name + strconv.Itoa(i)
+	}
+	ts.names[out] = true
+	return out
+}
+
+// tsType returns the TypeScript type for schema, defining interfaces as
+// needed.
+func (ts *tsTypes) tsType(name string, schema Struct) string {
+	types := schemaTypes(schema)
+	if len(types) == 0 {
+		return "unknown"
+	}
+	var union []string
+	for _, t := range types {
+		var tt string
+		switch t {
+		case "string":
+			tt = "string"
+		case "integer", "number":
+			tt = "number"
+		case "boolean":
+			tt = "boolean"
+		case "null":
+			tt = "null"
+		case "array":
+			items, _ := schema["items"].(Struct)
+			tt = ts.tsType(goSingular(name), items)
+			if strings.Contains(tt, " ") {
+				tt = "(" + tt + ")"
+			}
+			tt += "[]"
+		case "object":
+			props, _ := schema["properties"].(Struct)
+			if len(props) == 0 {
+				tt = "Record<string, unknown>"
+			} else {
+				tt = ts.tsInterface(name, schema, props)
+			}
+		default:
+			tt = "unknown"
+		}
+		if !slices.Contains(union, tt) {
+			union = append(union, tt)
+		}
+	}
+	return strings.Join(union, " | ")
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+func (ts *tsTypes) tsInterface(name string, schema Struct, props Struct) string {
+	name = ts.uniqueName(name)
+	required := map[string]bool{}
+	if req, ok := schema["required"].(Array); ok {
+		for _, r := range req {
+			if s, ok := r.(String); ok {
+				required[string(s)] = true
+			}
+		}
+	}
+	// the interface goes before the types of its properties
+	slot := len(ts.defs)
+	ts.defs = append(ts.defs, "")
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "export interface %s {\n", name)
+	for _, k := range sortedKeys(props) {
+		ps, _ := props[k].(Struct)
+		key := k
+		if !tsIdentifier.MatchString(k) {
+			key = strconv.Quote(k)
+		}
+		if !required[k] {
+			key += "?"
+		}
+		fmt.Fprintf(&sb, "  %s: %s;\n", key, ts.tsType(name+goIdentifier(k), ps))
+	}
+	sb.WriteString("}\n")
+	ts.defs[slot] = sb.String()
+	return name
+}
@@ -0,0 +1,39 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeScriptTypes(t *testing.T) {
+	samples := []Value{
+		mustFromJSON(t, `{"id": 1, "user-name": "ann", "address": {"city": "x", "zip": null}, "line_items": [{"sku": "a"}], "meta": {}, "mixed": 1, "ids": [1, "a"]}`),
+		mustFromJSON(t, `{"id": 2.5, "user-name": "bob", "line_items": [], "meta": {}, "mixed": "x", "ids": [], "address": null}`),
+	}
+	require.Equal(t, `export interface Order {
+  address: OrderAddress | null;
+  id: number;
+  ids: (string | number)[];
+  line_items: OrderLineItem[];
+  meta: Record<string, unknown>;
+  mixed: string | number;
+  "user-name": string;
+}
+
+export interface OrderAddress {
+  city: string;
+  zip: null;
+}
+
+export interface OrderLineItem {
+  sku: string;
+}
+`, string(TypeScriptTypes("order", InferSchema(samples...))))
+
+	require.Equal(t, "export type Scores = number[];\n", string(TypeScriptTypes("scores", InferSchema(mustFromJSON(t, `[1]`)))))
+	require.Equal(t, "export type Anything = unknown;\n", string(TypeScriptTypes("anything", InferSchema())))
+
+	partial := InferSchema(mustFromJSON(t, `{"a": 1}`), mustFromJSON(t, `{}`))
+	require.Equal(t, "export interface Partial {\n  a?: number;\n}\n", string(TypeScriptTypes("partial", partial)))
+}
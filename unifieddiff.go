@@ -0,0 +1,174 @@
+package simple
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around each change in the
+// output of [UnifiedDiff].
+const diffContext = 3
+
+// maxDiffCells bounds the memory used to find the longest common subsequence
+// of lines. Beyond it, the differing lines are replaced as a whole.
+const maxDiffCells = 1 << 22
+
+type diffLine struct {
+	op   byte // ' ', '-' or '+'
+	text string
+}
+
+// UnifiedDiff writes a line-oriented unified diff of a and b to w, for CLI
+// comparison tools and test output. Both are rendered as indented JSON with
+// sorted keys, like [Render] does but without syntax colors, and the lines
+// that changed are shown with three lines of context and colored with the
+// Added, Removed and Hunk colors of the [Palette]. [RenderIndent] and
+// [WithPalette] are respected, [FoldDepth] is not, since it would hide
+// changes. Nothing is written when a and b render the same. Use [Diff] to
+// inspect the changes programmatically.
+func UnifiedDiff(w io.Writer, a, b Value, opts ...RenderOption) error {
+	r := renderer{palette: DefaultPalette, indent: "  "}
+	for _, o := range opts {
+		o(&r)
+	}
+	palette := r.palette
+	r.palette, r.foldDepth = Palette{}, 0
+	r.render(a, 0)
+	aLines := strings.Split(r.buf.String(), "\n")
+	r.buf.Reset()
+	r.render(b, 0)
+	bLines := strings.Split(r.buf.String(), "\n")
+
+	lines := diffLines(aLines, bLines)
+	bw := bufio.NewWriter(w)
+	line := func(color, text string) {
+		if color != "" {
+			text = color + text + ansiReset
+		}
+		bw.WriteString(text + "\n")
+	}
+	aLine, bLine := 0, 0
+	for start := 0; start < len(lines); {
+		change := start
+		for change < len(lines) && lines[change].op == ' ' {
+			change++
+		}
+		if change == len(lines) {
+			break
+		}
+		if start == 0 {
+			line(palette.Removed, "--- a")
+			line(palette.Added, "+++ b")
+		}
+		// skip the unchanged lines before the context of the hunk
+		for ; start < change-diffContext; start++ {
+			aLine++
+			bLine++
+		}
+		last := change
+		end := change
+		for ; end < len(lines) && end-last <= 2*diffContext; end++ {
+			if lines[end].op != ' ' {
+				last = end
+			}
+		}
+		end = min(last+diffContext+1, len(lines))
+		aCount, bCount := 0, 0
+		for _, l := range lines[start:end] {
+			if l.op != '+' {
+				aCount++
+			}
+			if l.op != '-' {
+				bCount++
+			}
+		}
+		line(palette.Hunk, fmt.Sprintf("@@ -%s +%s @@", hunkRange(aLine, aCount), hunkRange(bLine, bCount)))
+		for _, l := range lines[start:end] {
+			switch l.op {
+			case '-':
+				line(palette.Removed, "-"+l.text)
+			case '+':
+				line(palette.Added, "+"+l.text)
+			default:
+				line("", " "+l.text)
+			}
+		}
+		aLine += aCount
+		bLine += bCount
+		start = end
+	}
+	return bw.Flush()
+}
+
+// hunkRange formats the range of a hunk header, given the number of lines
+// before the hunk and the number of lines in it.
+func hunkRange(before, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", before)
+	case 1:
+		return fmt.Sprint(before + 1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+// diffLines returns the edit script that turns a into b, keeping their
+// longest common subsequence of lines unchanged.
+func diffLines(a, b []string) []diffLine {
+	var out []diffLine
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		out = append(out, diffLine{' ', a[prefix]})
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(ma)*len(mb) > maxDiffCells {
+		for _, l := range ma {
+			out = append(out, diffLine{'-', l})
+		}
+		for _, l := range mb {
+			out = append(out, diffLine{'+', l})
+		}
+	} else {
+		// lcs[i][j] is the length of the longest common subsequence of
+		// ma[i:] and mb[j:]
+		lcs := make([][]int32, len(ma)+1)
+		for i := range lcs {
+			lcs[i] = make([]int32, len(mb)+1)
+		}
+		for i := len(ma) - 1; i >= 0; i-- {
+			for j := len(mb) - 1; j >= 0; j-- {
+				if ma[i] == mb[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(ma) || j < len(mb) {
+			switch {
+			case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+				out = append(out, diffLine{' ', ma[i]})
+				i++
+				j++
+			case j == len(mb) || (i < len(ma) && lcs[i+1][j] >= lcs[i][j+1]):
+				out = append(out, diffLine{'-', ma[i]})
+				i++
+			default:
+				out = append(out, diffLine{'+', mb[j]})
+				j++
+			}
+		}
+	}
+	for _, l := range a[len(a)-suffix:] {
+		out = append(out, diffLine{' ', l})
+	}
+	return out
+}
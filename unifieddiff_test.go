@@ -0,0 +1,45 @@
+package simple
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	a := Struct{"a": Int(1), "b": Int(2), "c": Int(3), "d": Int(4), "e": Int(5), "f": Int(6), "g": Int(7), "h": Int(8), "i": Int(9)}
+	b := Struct{"a": Int(0), "b": Int(2), "c": Int(3), "d": Int(4), "e": Int(5), "f": Int(6), "g": Int(7), "h": Int(8), "i": Int(9), "j": Int(10)}
+	var buf bytes.Buffer
+	require.NoError(t, UnifiedDiff(&buf, a, b, WithPalette(Palette{})))
+	require.Equal(t, `--- a
++++ b
+@@ -1,5 +1,5 @@
+ {
+-  "a": 1,
++  "a": 0,
+   "b": 2,
+   "c": 3,
+   "d": 4,
+@@ -7,5 +7,6 @@
+   "f": 6,
+   "g": 7,
+   "h": 8,
+-  "i": 9
++  "i": 9,
++  "j": 10
+ }
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, UnifiedDiff(&buf, a, a))
+	require.Empty(t, buf.String())
+
+	buf.Reset()
+	require.NoError(t, UnifiedDiff(&buf, Array{}, Array{String("x")}))
+	require.Equal(t, "\x1b[31m--- a\x1b[0m\n\x1b[32m+++ b\x1b[0m\n\x1b[36m@@ -1 +1,3 @@\x1b[0m\n\x1b[31m-[]\x1b[0m\n\x1b[32m+[\x1b[0m\n\x1b[32m+  \"x\"\x1b[0m\n\x1b[32m+]\x1b[0m\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, UnifiedDiff(&buf, Array{Int(1)}, Array{Int(1), Int(2)}, WithPalette(Palette{}), RenderIndent("\t")))
+	require.Equal(t, "--- a\n+++ b\n@@ -1,3 +1,4 @@\n [\n-\t1\n+\t1,\n+\t2\n ]\n", buf.String())
+}
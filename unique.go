@@ -0,0 +1,52 @@
+package simple
+
+// Unique returns a copy of a without the elements that are equal to an
+// earlier element, for cleaning up merged data sets. Equality is semantic,
+// so Int(1) and Number(1) are duplicates, as are a nil Struct and an empty
+// one.
+func Unique(a Array) Array {
+	return uniqueBy(a, func(v Value) Value { return v })
+}
+
+// UniqueBy is like [Unique], but two elements are duplicates when the values
+// at key inside of them are equal. Elements without a value at key are
+// treated as if it was null.
+func UniqueBy(a Array, key Path) Array {
+	return uniqueBy(a, func(v Value) Value {
+		kv, _ := Lookup(v, key)
+		return kv
+	})
+}
+
+func uniqueBy(a Array, key func(Value) Value) Array {
+	seen := valueSet{}
+	out := Array{}
+	for _, ev := range a {
+		if seen.add(key(ev)) {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+// valueSet is a set of Values, bucketed by their [Hash].
+type valueSet map[uint64][]Value
+
+// add adds v to the set and reports whether it was not already in it.
+func (s valueSet) add(v Value) bool {
+	if s.contains(v) {
+		return false
+	}
+	h := Hash(v)
+	s[h] = append(s[h], v)
+	return true
+}
+
+func (s valueSet) contains(v Value) bool {
+	for _, other := range s[Hash(v)] {
+		if equal(v, other) {
+			return true
+		}
+	}
+	return false
+}
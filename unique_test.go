@@ -0,0 +1,27 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnique(t *testing.T) {
+	require.Equal(t, Array{
+		Int(1), String("1"), Struct{"a": Array{}}, nil,
+	}, Unique(Array{
+		Int(1), String("1"), Number(1), Struct{"a": Array{}}, nil, Struct{"a": Array(nil)}, nil, RawNumber("1.0"),
+	}))
+	require.Equal(t, Array{}, Unique(nil))
+}
+
+func TestUniqueBy(t *testing.T) {
+	users := Array{
+		Struct{"email": String("a@x"), "n": Int(1)},
+		Struct{"email": String("b@x"), "n": Int(2)},
+		Struct{"email": String("a@x"), "n": Int(3)},
+		Struct{"n": Int(4)},
+		Struct{"email": nil, "n": Int(5)},
+	}
+	require.Equal(t, Array{users[0], users[1], users[3]}, UniqueBy(users, MustParsePath("email")))
+}
@@ -0,0 +1,162 @@
+package simple
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxURLIndex is the largest array index that FromURLValues accepts, larger
+// ones are treated as struct keys so that a single parameter like a[999999999]
+// cannot allocate a huge Array.
+const maxURLIndex = 1000
+
+// FromURLValues converts form or query-string data to a [Struct], following
+// the bracket conventions of Rails, PHP and the qs package:
+//
+//   - a=1 becomes {"a":"1"}, and a=1&a=2 becomes {"a":["1","2"]}.
+//   - a[b]=1 becomes {"a":{"b":"1"}}.
+//   - a[]=1&a[]=2 becomes {"a":["1","2"]}, even with a single value.
+//   - a[0][b]=1&a[1][b]=2 becomes {"a":[{"b":"1"},{"b":"2"}]}. Indexes that
+//     are skipped are null.
+//
+// All values are strings. Keys with unbalanced brackets are kept as they are.
+// Conflicting keys like a=1&a[b]=2 are applied in sorted order, so the nested
+// one wins.
+func FromURLValues(values url.Values) Struct {
+	out := Struct{}
+	for _, k := range sortedKeysOf(values) {
+		vals := values[k]
+		if len(vals) == 0 {
+			continue
+		}
+		name, segs := parseURLKey(k)
+		out[name] = insertURLValue(out[name], segs, urlStrings(vals))
+	}
+	return out
+}
+
+// parseURLKey splits a key like a[b][] into its name and bracketed segments.
+func parseURLKey(k string) (string, []string) {
+	i := strings.IndexByte(k, '[')
+	if i <= 0 {
+		return k, nil
+	}
+	var segs []string
+	for rest := k[i:]; rest != ""; {
+		end := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || end < 0 {
+			return k, nil
+		}
+		segs = append(segs, rest[1:end])
+		rest = rest[end+1:]
+	}
+	return k[:i], segs
+}
+
+func urlIndex(seg string) (int, bool) {
+	i, err := strconv.Atoi(seg)
+	if err != nil || i < 0 || i > maxURLIndex || strconv.Itoa(i) != seg {
+		return 0, false
+	}
+	return i, true
+}
+
+// insertURLValue puts the values of a single key into v, at the location
+// described by segs.
+func insertURLValue(v Value, segs []string, vals Array) Value {
+	if len(segs) == 0 {
+		if len(vals) == 1 {
+			return vals[0]
+		}
+		return vals
+	}
+	seg := segs[0]
+	if seg == "" {
+		arr, _ := v.(Array)
+		if len(segs) == 1 {
+			return append(arr, vals...)
+		}
+		// a[][b]=1&a[][b]=2 makes one element per value
+		for _, val := range vals {
+			arr = append(arr, insertURLValue(nil, segs[1:], Array{val}))
+		}
+		return arr
+	}
+	if _, isStruct := v.(Struct); !isStruct {
+		if i, ok := urlIndex(seg); ok {
+			arr, _ := v.(Array)
+			for len(arr) <= i {
+				arr = append(arr, nil)
+			}
+			arr[i] = insertURLValue(arr[i], segs[1:], vals)
+			return arr
+		}
+	}
+	s, ok := v.(Struct)
+	if !ok {
+		s = Struct{}
+	}
+	s[seg] = insertURLValue(s[seg], segs[1:], vals)
+	return s
+}
+
+func urlStrings(vals []string) Array {
+	out := make(Array, len(vals))
+	for i, val := range vals {
+		out[i] = String(val)
+	}
+	return out
+}
+
+// ToURLValues converts s to form or query-string data, the reverse of
+// [FromURLValues]. Nested structs use keys like a[b], arrays of scalars use
+// a[], and arrays that hold composites are indexed like a[0][b]. Scalars are
+// rendered with [AsString], null becomes an empty string, and empty
+// composites are left out.
+func ToURLValues(s Struct) (url.Values, error) {
+	out := url.Values{}
+	for _, k := range sortedKeys(s) {
+		if err := addURLValues(out, Path{k}, k, s[k]); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func addURLValues(out url.Values, path Path, key string, v Value) error {
+	switch tv := v.(type) {
+	case nil:
+		out.Add(key, "")
+	case Struct:
+		for _, k := range sortedKeys(tv) {
+			if err := addURLValues(out, path.Key(k), key+"["+k+"]", tv[k]); err != nil {
+				return err
+			}
+		}
+	case Array:
+		indexed := false
+		for _, ev := range tv {
+			switch ev.(type) {
+			case Struct, Array:
+				indexed = true
+			}
+		}
+		for i, ev := range tv {
+			ekey := key + "[]"
+			if indexed {
+				ekey = key + "[" + strconv.Itoa(i) + "]"
+			}
+			if err := addURLValues(out, path.Index(i), ekey, ev); err != nil {
+				return err
+			}
+		}
+	default:
+		str, err := AsString(v)
+		if err != nil {
+			return encodeError{path: path, problem: err.Error(), err: err}
+		}
+		out.Add(key, str)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package simple
+
+import (
+	"math"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromURLValues(t *testing.T) {
+	for _, tc := range []struct {
+		query  string
+		output string
+	}{
+		{query: "a=1&b=2&b=3", output: `{"a":"1","b":["2","3"]}`},
+		{query: "a[b]=1&a[c][d]=2", output: `{"a":{"b":"1","c":{"d":"2"}}}`},
+		{query: "tags[]=x", output: `{"tags":["x"]}`},
+		{query: "a[][b]=1&a[][b]=2", output: `{"a":[{"b":"1"},{"b":"2"}]}`},
+		{query: "a[1][b]=2&a[0][b]=1&a[0][c]=3", output: `{"a":[{"b":"1","c":"3"},{"b":"2"}]}`},
+		{query: "a[2]=x", output: `{"a":[null,null,"x"]}`},
+		{query: "a[99999]=x&a[01]=y", output: `{"a":{"01":"y","99999":"x"}}`},
+		{query: "a=1&a[b]=2", output: `{"a":{"b":"2"}}`},
+		{query: "a[b=1&[c]=2&d]=3", output: `{"[c]":"2","a[b":"1","d]":"3"}`},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			values, err := url.ParseQuery(tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.output, FromURLValues(values).String())
+		})
+	}
+}
+
+func TestToURLValues(t *testing.T) {
+	v := mustFromJSON(t, `{"q":"go","page":2,"tags":["a","b"],"filter":{"on":true,"none":null},"items":[{"id":1},{"id":2,"x":[]}]}`).(Struct)
+	values, err := ToURLValues(v)
+	require.NoError(t, err)
+	require.Equal(t, "filter%5Bnone%5D=&filter%5Bon%5D=true&items%5B0%5D%5Bid%5D=1&items%5B1%5D%5Bid%5D=2&page=2&q=go&tags%5B%5D=a&tags%5B%5D=b", values.Encode())
+
+	back := FromURLValues(values)
+	require.Equal(t, `{"filter":{"none":"","on":"true"},"items":[{"id":"1"},{"id":"2"}],"page":"2","q":"go","tags":["a","b"]}`, back.String())
+
+	_, err = ToURLValues(Struct{"a": Struct{"b": Number(math.NaN())}})
+	require.EqualError(t, err, `cannot encode value at .a.b: cannot coerce number NaN to string: not a finite number`)
+}
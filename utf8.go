@@ -0,0 +1,103 @@
+package simple
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidUTF8 is returned when a string is not valid UTF-8 and the
+// [UTF8Policy] is [UTF8Reject].
+var ErrInvalidUTF8 = errors.New("invalid UTF-8")
+
+// UTF8Policy decides what happens to strings (and struct keys) that are not
+// valid UTF-8. A [String] is supposed to hold UTF-8 text, but Go strings can
+// hold arbitrary bytes.
+type UTF8Policy int
+
+const (
+	// UTF8PassThrough keeps strings as they are. It is the default for
+	// [FromValue].
+	UTF8PassThrough UTF8Policy = iota
+	// UTF8Replace replaces each invalid byte with U+FFFD, the same way
+	// encoding/json does. It is the default for [FromJSON].
+	UTF8Replace
+	// UTF8Reject fails with [ErrInvalidUTF8].
+	UTF8Reject
+)
+
+// InvalidUTF8 sets how [FromJSON] and [JSONDecoder] deal with invalid UTF-8 in
+// the input. encoding/json always replaces invalid bytes, so
+// [UTF8PassThrough] behaves like [UTF8Replace] here. [UTF8Reject] needs to see
+// the raw input, so it has no effect on [FromJSONDecoder].
+func InvalidUTF8(p UTF8Policy) JSONOption {
+	return func(b *jsonBuilder) { b.utf8 = p }
+}
+
+// ConvertInvalidUTF8 sets how [FromValue] deals with strings and map keys that
+// are not valid UTF-8.
+func ConvertInvalidUTF8(p UTF8Policy) FromValueOption {
+	return func(c *converter) { c.utf8 = p }
+}
+
+// toValidUTF8 replaces every invalid byte of s with U+FFFD. Unlike
+// strings.ToValidUTF8, runs of invalid bytes are not collapsed.
+func toValidUTF8(s string) string {
+	if utf8.ValidString(s) {
+		return s
+	}
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for _, r := range s {
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// invalidUTF8Offset returns the offset of the first invalid byte in b, or -1.
+func invalidUTF8Offset(b []byte) int {
+	for i := 0; i < len(b); {
+		r, size := utf8.DecodeRune(b[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
+
+// utf8Reader fails when the data read from r is not valid UTF-8. Data is
+// handed out as soon as it is read, but a chunk with an invalid byte is never
+// handed out, so an invalid sequence cannot end up in a complete JSON value.
+type utf8Reader struct {
+	r      io.Reader
+	offset int64
+	// tail holds an incomplete sequence at the end of the last chunk
+	tail []byte
+}
+
+func (u *utf8Reader) Read(p []byte) (int, error) {
+	n, err := u.r.Read(p)
+	data := append(u.tail, p[:n]...)
+	start := u.offset - int64(len(u.tail))
+	end := len(data)
+	if err == nil {
+		// leave an incomplete sequence at the end for the next read
+		for back := 1; back < utf8.UTFMax && back <= end; back++ {
+			if utf8.RuneStart(data[end-back]) {
+				if !utf8.FullRune(data[end-back:]) {
+					end -= back
+				}
+				break
+			}
+		}
+	}
+	if i := invalidUTF8Offset(data[:end]); i >= 0 {
+		return 0, fmt.Errorf("%w at offset %d", ErrInvalidUTF8, start+int64(i))
+	}
+	u.tail = append(u.tail[:0], data[end:]...)
+	u.offset += int64(n)
+	return n, err
+}
@@ -0,0 +1,61 @@
+package simple
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidUTF8(t *testing.T) {
+	bad := "a\xffb\xe2\x82"
+	t.Run("FromValue", func(t *testing.T) {
+		input := map[string]any{"s": []string{bad}, bad: true}
+
+		v, err := FromValue(input)
+		require.NoError(t, err)
+		require.Equal(t, Struct{"s": Array{String(bad)}, bad: Bool(true)}, v)
+
+		v, err = FromValue(input, ConvertInvalidUTF8(UTF8Replace))
+		require.NoError(t, err)
+		require.Equal(t, Struct{"s": Array{String("a�b��")}, "a�b��": Bool(true)}, v)
+
+		_, err = FromValue(map[string]any{"s": []string{"ok", bad}}, ConvertInvalidUTF8(UTF8Reject))
+		require.ErrorIs(t, err, ErrInvalidUTF8)
+		require.EqualError(t, err, "cannot convert value at .s[1]: invalid UTF-8")
+	})
+	t.Run("FromJSON", func(t *testing.T) {
+		input := "{\n  \"s\": \"" + bad + "\"\n}"
+		for _, opts := range [][]JSONOption{nil, {InvalidUTF8(UTF8PassThrough)}, {InvalidUTF8(UTF8Replace)}} {
+			v, err := FromJSON(json.RawMessage(input), opts...)
+			require.NoError(t, err)
+			require.Equal(t, Struct{"s": String("a�b��")}, v)
+		}
+
+		_, err := FromJSON(json.RawMessage(input), InvalidUTF8(UTF8Reject))
+		require.ErrorIs(t, err, ErrInvalidUTF8)
+		require.EqualError(t, err, "invalid JSON at line 2, column 10: invalid UTF-8")
+	})
+	t.Run("JSONDecoder", func(t *testing.T) {
+		// a multi-byte character split across reads is fine
+		dec := NewJSONDecoder(iotest.OneByteReader(strings.NewReader(`"€" "ok"`)), InvalidUTF8(UTF8Reject))
+		v, err := dec.Decode()
+		require.NoError(t, err)
+		require.Equal(t, String("€"), v)
+
+		for _, input := range []string{`"` + bad + `"`, "\"ok\" \"\xe2\x82"} {
+			dec = NewJSONDecoder(iotest.OneByteReader(strings.NewReader(input)), InvalidUTF8(UTF8Reject))
+			for {
+				_, err = dec.Decode()
+				if err != nil {
+					break
+				}
+			}
+			require.ErrorIs(t, err, ErrInvalidUTF8)
+			require.NotErrorIs(t, err, io.EOF)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package simple
+
+import "fmt"
+
+// VersionedValue keeps a Value along with its previous revisions, for
+// configuration systems that need to roll back. Only the current revision is
+// kept in full. Every older one is kept as the patches that turn the revision
+// after it back into it, like [DiffPatches] finds them, except that scalars
+// are compared by representation, so that a revision comes back exactly as
+// it was committed, with a [RawNumber] still being one.
+//
+// Revisions are numbered from 0, the initial Value. A VersionedValue is not
+// safe for concurrent use.
+type VersionedValue struct {
+	current Immutable
+	rev     int
+	// undo[i] turns revision oldest+i+1 back into oldest+i
+	undo   [][]Patch
+	oldest int
+	keep   int
+}
+
+// NewVersionedValue returns a VersionedValue at revision 0, holding a deep
+// copy of v. At most keep revisions before the current one are kept, or all
+// of them if keep is 0 or less.
+func NewVersionedValue(v Value, keep int) *VersionedValue {
+	return &VersionedValue{current: NewImmutable(v), keep: keep}
+}
+
+// Rev returns the number of the current revision.
+func (vv *VersionedValue) Rev() int {
+	return vv.rev
+}
+
+// Oldest returns the number of the oldest revision that is still kept.
+func (vv *VersionedValue) Oldest() int {
+	return vv.oldest
+}
+
+// Value returns the current revision.
+func (vv *VersionedValue) Value() Immutable {
+	return vv.current
+}
+
+// Commit makes a deep copy of v the new current revision, and returns its
+// number. If the history is full, the oldest revision is dropped.
+func (vv *VersionedValue) Commit(v Value) int {
+	next := NewImmutable(v)
+	var changes []Change
+	diff(&changes, Path{}, next.v, vv.current.v, identical)
+	vv.undo = append(vv.undo, changePatches(changes))
+	vv.current = next
+	vv.rev++
+	if vv.keep > 0 && len(vv.undo) > vv.keep {
+		vv.undo = vv.undo[len(vv.undo)-vv.keep:]
+		vv.oldest = vv.rev - vv.keep
+	}
+	return vv.rev
+}
+
+// At returns the revision rev, by undoing the changes made after it. It is an
+// error if rev is not kept.
+func (vv *VersionedValue) At(rev int) (Immutable, error) {
+	if rev < vv.oldest || rev > vv.rev {
+		return Immutable{}, fmt.Errorf("revision %d is out of range [%d, %d]", rev, vv.oldest, vv.rev)
+	}
+	v := vv.current.v
+	for i := len(vv.undo) - 1; i >= rev-vv.oldest; i-- {
+		var err error
+		if v, err = ApplyPatch(v, vv.undo[i]...); err != nil {
+			return Immutable{}, fmt.Errorf("cannot undo revision %d: %w", vv.oldest+i+1, err)
+		}
+	}
+	return Immutable{v: v}, nil
+}
+
+// DiffSince returns the changes made to the Value since revision rev, as
+// found by [Diff].
+func (vv *VersionedValue) DiffSince(rev int) ([]Change, error) {
+	old, err := vv.At(rev)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(old.v, vv.current.v), nil
+}
+
+// Revert rolls back to revision rev by committing it again as a new revision,
+// so the revisions after it stay in the history and can be returned to. It
+// returns the number of the new revision.
+func (vv *VersionedValue) Revert(rev int) (int, error) {
+	old, err := vv.At(rev)
+	if err != nil {
+		return 0, err
+	}
+	return vv.Commit(old.v), nil
+}
@@ -0,0 +1,68 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionedValue(t *testing.T) {
+	revs := []Value{
+		Struct{"hosts": Array{String("a"), String("b"), String("c")}, "ttl": Int(60)},
+		Struct{"hosts": Array{String("a")}, "ttl": Int(30), "debug": Bool(true)},
+		Struct{"hosts": Array{String("x"), String("y")}},
+		Array{Int(1)},
+	}
+	vv := NewVersionedValue(revs[0], 0)
+	for i, v := range revs[1:] {
+		require.Equal(t, i+1, vv.Commit(v))
+	}
+	require.Equal(t, 3, vv.Rev())
+	for rev, want := range revs {
+		got, err := vv.At(rev)
+		require.NoError(t, err)
+		require.Equal(t, want, got.Value())
+	}
+
+	changes, err := vv.DiffSince(2)
+	require.NoError(t, err)
+	require.Equal(t, []Change{{Kind: Modified, Path: Path{}, Old: revs[2], New: revs[3]}}, changes)
+
+	rev, err := vv.Revert(1)
+	require.NoError(t, err)
+	require.Equal(t, 4, rev)
+	require.Equal(t, revs[1], vv.Value().Value())
+	got, err := vv.At(3)
+	require.NoError(t, err)
+	require.Equal(t, revs[3], got.Value())
+
+	_, err = vv.At(5)
+	require.EqualError(t, err, "revision 5 is out of range [0, 4]")
+
+	vv = NewVersionedValue(revs[0], 2)
+	for _, v := range revs[1:] {
+		vv.Commit(v)
+	}
+	require.Equal(t, 1, vv.Oldest())
+	_, err = vv.At(0)
+	require.EqualError(t, err, "revision 0 is out of range [1, 3]")
+	got, err = vv.At(1)
+	require.NoError(t, err)
+	require.Equal(t, revs[1], got.Value())
+	_, err = vv.Revert(0)
+	require.Error(t, err)
+}
+
+func TestVersionedValueExactHistory(t *testing.T) {
+	vv := NewVersionedValue(Struct{"a": RawNumber("1.00"), "b": Array{Number(2)}}, 0)
+	vv.Commit(Struct{"a": Int(1), "b": Array{Int(2)}})
+	old, err := vv.At(0)
+	require.NoError(t, err)
+	require.Equal(t, Struct{"a": RawNumber("1.00"), "b": Array{Number(2)}}, old.Value())
+
+	rev, err := vv.Revert(0)
+	require.NoError(t, err)
+	require.Equal(t, 2, rev)
+	require.Equal(t, `{"a":1.00,"b":[2]}`, vv.Value().String())
+	require.Equal(t, Struct{"a": RawNumber("1.00"), "b": Array{Number(2)}}, vv.Value().Value())
+}
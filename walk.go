@@ -0,0 +1,165 @@
+package simple
+
+import (
+	"errors"
+	"sort"
+)
+
+// WalkFunc is called by [Walk] for every value in a tree. Returning false for
+// descend skips the children of a [Struct] or [Array]. Returning an error stops
+// the walk and that error is returned from [Walk], unless it is [SkipAll].
+type WalkFunc func(path Path, v Value) (descend bool, err error)
+
+// SkipAll can be returned from a [WalkFunc] to stop the walk early without
+// [Walk] returning an error.
+var SkipAll = errors.New("skip everything and stop the walk")
+
+// Walk does a depth-first traversal of v, calling fn for v itself and then for
+// everything nested inside of it. Struct keys are visited in sorted order so
+// walks are deterministic.
+func Walk(v Value, fn WalkFunc) error {
+	err := walk(Path{}, v, fn)
+	if err == SkipAll {
+		return nil
+	}
+	return err
+}
+
+func walk(path Path, v Value, fn WalkFunc) error {
+	descend, err := fn(path, v)
+	if err != nil || !descend {
+		return err
+	}
+	switch tv := v.(type) {
+	case Struct:
+		for _, k := range sortedKeys(tv) {
+			if err := walk(path.Key(k), tv[k], fn); err != nil {
+				return err
+			}
+		}
+	case Array:
+		for i, ev := range tv {
+			if err := walk(path.Index(i), ev, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of s in sorted order.
+func sortedKeys(s Struct) []string {
+	return sortedKeysOf(s)
+}
+
+// TransformFunc is called by [Transform] for every value in a tree, and
+// returns the value that should take its place.
+type TransformFunc func(path Path, v Value) (Value, error)
+
+// Transform builds a new tree by passing every value in v through fn. The
+// tree is processed bottom-up: the children of a [Struct] or [Array] are
+// transformed first and fn is then called with a copy of the composite that
+// holds the transformed children. Values returned by fn are not transformed
+// again. v itself is never modified.
+func Transform(v Value, fn TransformFunc) (Value, error) {
+	return transform(Path{}, v, fn)
+}
+
+func transform(path Path, v Value, fn TransformFunc) (Value, error) {
+	switch tv := v.(type) {
+	case Struct:
+		out := make(Struct, len(tv))
+		for _, k := range sortedKeys(tv) {
+			nv, err := transform(path.Key(k), tv[k], fn)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = nv
+		}
+		v = out
+	case Array:
+		out := make(Array, len(tv))
+		for i, ev := range tv {
+			nv, err := transform(path.Index(i), ev, fn)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = nv
+		}
+		v = out
+	}
+	return fn(path, v)
+}
+
+// Filter returns a copy of v that only contains the values for which keep
+// returns true. When keep returns false for a [Struct] or [Array], that whole
+// subtree is dropped without keep being called for its children. Dropped array
+// elements are removed, so later elements shift down; the paths given to keep
+// are always the paths in the original v. If v itself is not kept, Filter
+// returns nil.
+func Filter(v Value, keep func(path Path, v Value) bool) Value {
+	out, _ := filter(Path{}, v, keep)
+	return out
+}
+
+func filter(path Path, v Value, keep func(Path, Value) bool) (Value, bool) {
+	if !keep(path, v) {
+		return nil, false
+	}
+	switch tv := v.(type) {
+	case Struct:
+		out := make(Struct, len(tv))
+		for k, ev := range tv {
+			if nv, ok := filter(path.Key(k), ev, keep); ok {
+				out[k] = nv
+			}
+		}
+		return out, true
+	case Array:
+		out := make(Array, 0, len(tv))
+		for i, ev := range tv {
+			if nv, ok := filter(path.Index(i), ev, keep); ok {
+				out = append(out, nv)
+			}
+		}
+		return out, true
+	}
+	return v, true
+}
+
+// RenameKeys returns a copy of v where every struct key has been passed
+// through mapping. mapping is given the path of the [Struct] that holds the
+// key (using the original key names) along with the key itself. If two keys of
+// the same Struct are renamed to the same thing, the one that was last in
+// sorted order wins.
+func RenameKeys(v Value, mapping func(path Path, key string) string) Value {
+	return renameKeys(Path{}, v, mapping)
+}
+
+func renameKeys(path Path, v Value, mapping func(Path, string) string) Value {
+	switch tv := v.(type) {
+	case Struct:
+		out := make(Struct, len(tv))
+		for _, k := range sortedKeys(tv) {
+			out[mapping(path, k)] = renameKeys(path.Key(k), tv[k], mapping)
+		}
+		return out
+	case Array:
+		out := make(Array, len(tv))
+		for i, ev := range tv {
+			out[i] = renameKeys(path.Index(i), ev, mapping)
+		}
+		return out
+	}
+	return v
+}
+
+// sortedKeysOf returns the keys of any string keyed map in sorted order.
+func sortedKeysOf[M ~map[string]V, V any](m M) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
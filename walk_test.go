@@ -0,0 +1,143 @@
+package simple
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalk(t *testing.T) {
+	v := Struct{
+		"b": Array{Number(1), Struct{"c": Bool(true)}},
+		"a": String("x"),
+		"_private": Struct{
+			"secret": String("shh"),
+		},
+	}
+	t.Run("visits everything in order", func(t *testing.T) {
+		var visited []string
+		err := Walk(v, func(path Path, v Value) (bool, error) {
+			visited = append(visited, path.String())
+			return path.String() != "._private", nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"", "._private", ".a", ".b", ".b[0]", ".b[1]", ".b[1].c"}, visited)
+	})
+	t.Run("errors stop the walk", func(t *testing.T) {
+		boom := errors.New("boom")
+		count := 0
+		err := Walk(v, func(path Path, v Value) (bool, error) {
+			count++
+			if _, ok := v.(Array); ok {
+				return false, boom
+			}
+			return true, nil
+		})
+		require.ErrorIs(t, err, boom)
+		require.Equal(t, 5, count)
+	})
+	t.Run("SkipAll", func(t *testing.T) {
+		var last Path
+		err := Walk(v, func(path Path, v Value) (bool, error) {
+			last = path
+			if v == String("x") {
+				return false, SkipAll
+			}
+			return true, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, Path{"a"}, last)
+	})
+}
+
+func TestTransform(t *testing.T) {
+	v := Struct{
+		"weights_lb": Array{Number(10), Number(20)},
+		"name":       String("crate"),
+	}
+	got, err := Transform(v, func(path Path, v Value) (Value, error) {
+		if len(path) == 2 && path[0] == "weights_lb" {
+			return v.(Number) * 0.5, nil
+		}
+		if s, ok := v.(String); ok {
+			return String("<" + s + ">"), nil
+		}
+		return v, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, Struct{
+		"weights_lb": Array{Number(5), Number(10)},
+		"name":       String("<crate>"),
+	}, got)
+	require.Equal(t, Number(10), v["weights_lb"].(Array)[0], "input must not be modified")
+
+	boom := errors.New("boom")
+	_, err = Transform(v, func(path Path, v Value) (Value, error) {
+		if _, ok := v.(String); ok {
+			return nil, boom
+		}
+		return v, nil
+	})
+	require.ErrorIs(t, err, boom)
+}
+
+func TestFilter(t *testing.T) {
+	v := Struct{
+		"id":    Number(1),
+		"_etag": String("abc"),
+		"items": Array{
+			Struct{"sku": String("a"), "_internal": Bool(true)},
+			nil,
+			Struct{"sku": String("b")},
+		},
+	}
+	got := Filter(v, func(path Path, v Value) bool {
+		if len(path) > 0 {
+			if k, ok := path[len(path)-1].(string); ok && strings.HasPrefix(k, "_") {
+				return false
+			}
+		}
+		return v != nil
+	})
+	require.Equal(t, Struct{
+		"id": Number(1),
+		"items": Array{
+			Struct{"sku": String("a")},
+			Struct{"sku": String("b")},
+		},
+	}, got)
+	require.Len(t, v["items"].(Array), 3, "input must not be modified")
+	require.Contains(t, v, "_etag")
+
+	require.Nil(t, Filter(v, func(Path, Value) bool { return false }))
+}
+
+func TestRenameKeys(t *testing.T) {
+	v := Struct{
+		"user_id": Number(1),
+		"line_items": Array{
+			Struct{"unit_price": Number(2)},
+		},
+		"raw": Struct{"keep_me": Bool(true)},
+	}
+	got := RenameKeys(v, func(path Path, key string) string {
+		if len(path) > 0 && path[0] == "raw" {
+			return key
+		}
+		parts := strings.Split(key, "_")
+		for i := 1; i < len(parts); i++ {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+		return strings.Join(parts, "")
+	})
+	require.Equal(t, Struct{
+		"userId": Number(1),
+		"lineItems": Array{
+			Struct{"unitPrice": Number(2)},
+		},
+		"raw": Struct{"keep_me": Bool(true)},
+	}, got)
+	require.Contains(t, v, "user_id", "input must not be modified")
+}
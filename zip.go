@@ -0,0 +1,57 @@
+package simple
+
+import "fmt"
+
+// Zip turns parallel Arrays into records, a common step when ingesting
+// columnar API responses. Every key of columns must hold an [Array], and all
+// of them must have the same length. The result has a [Struct] for every
+// index, with the element at that index of every column, so
+//
+//	{"name":["a","b"],"age":[1,2]}
+//
+// becomes [{"age":1,"name":"a"},{"age":2,"name":"b"}].
+func Zip(columns Struct) (Array, error) {
+	n := -1
+	for _, k := range sortedKeys(columns) {
+		col, ok := columns[k].(Array)
+		if !ok {
+			return nil, fmt.Errorf("cannot zip column %q: %s is not an array", k, kindName(columns[k]))
+		}
+		if n >= 0 && len(col) != n {
+			return nil, fmt.Errorf("cannot zip column %q: it has %d elements, other columns have %d", k, len(col), n)
+		}
+		n = len(col)
+	}
+	out := make(Array, max(n, 0))
+	for i := range out {
+		row := make(Struct, len(columns))
+		for k, col := range columns {
+			row[k] = col.(Array)[i]
+		}
+		out[i] = row
+	}
+	return out, nil
+}
+
+// Unzip is the inverse of [Zip], it turns an Array of Structs into a Struct
+// of parallel Arrays. Every key that appears in any row becomes a column, and
+// rows that do not have a key get a null in that column.
+func Unzip(rows Array) (Struct, error) {
+	for i, row := range rows {
+		if _, ok := row.(Struct); !ok {
+			return nil, fmt.Errorf("cannot unzip row %d: %s is not a struct", i, kindName(row))
+		}
+	}
+	out := Struct{}
+	for i, row := range rows {
+		for k := range row.(Struct) {
+			if _, ok := out[k]; !ok {
+				out[k] = make(Array, len(rows))
+			}
+		}
+		for k, col := range out {
+			col.(Array)[i] = row.(Struct)[k]
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,51 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestZip(t *testing.T) {
+	rows, err := Zip(Struct{
+		"name": Array{String("a"), String("b")},
+		"age":  Array{Int(1), nil},
+	})
+	require.NoError(t, err)
+	require.Equal(t, Array{
+		Struct{"name": String("a"), "age": Int(1)},
+		Struct{"name": String("b"), "age": nil},
+	}, rows)
+
+	cols, err := Unzip(rows)
+	require.NoError(t, err)
+	require.Equal(t, Struct{
+		"name": Array{String("a"), String("b")},
+		"age":  Array{Int(1), nil},
+	}, cols)
+
+	rows, err = Zip(Struct{})
+	require.NoError(t, err)
+	require.Equal(t, Array{}, rows)
+
+	_, err = Zip(Struct{"a": Array{nil}, "b": Array{}})
+	require.EqualError(t, err, `cannot zip column "b": it has 0 elements, other columns have 1`)
+	_, err = Zip(Struct{"a": String("x")})
+	require.EqualError(t, err, `cannot zip column "a": string is not an array`)
+}
+
+func TestUnzip(t *testing.T) {
+	cols, err := Unzip(Array{
+		Struct{"a": Int(1)},
+		Struct{"b": Int(2)},
+		Struct{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, Struct{
+		"a": Array{Int(1), nil, nil},
+		"b": Array{nil, Int(2), nil},
+	}, cols)
+
+	_, err = Unzip(Array{Struct{}, Array{}})
+	require.EqualError(t, err, "cannot unzip row 1: array is not a struct")
+}